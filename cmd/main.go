@@ -3,7 +3,9 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"time"
 
@@ -17,6 +19,7 @@ import (
 	"github.com/ysomad/financer/internal/config"
 	"github.com/ysomad/financer/internal/postgres"
 	"github.com/ysomad/financer/internal/postgres/pgclient"
+	"github.com/ysomad/financer/internal/rates"
 	"github.com/ysomad/financer/internal/service"
 	"github.com/ysomad/financer/internal/slogx"
 )
@@ -38,16 +41,38 @@ func mustMigrate(dsn, migrationsDir string) {
 	}
 }
 
+// importRates saves exchange rates of providers.
+func importRates(ctx context.Context, st *postgres.ExchangeRateStorage, providers ...rates.Provider) error {
+	for _, p := range providers {
+		rr, err := p.Rates(ctx)
+		if err != nil {
+			return fmt.Errorf("rates not loaded: %w", err)
+		}
+
+		if err := st.Save(ctx, rr, time.Now()); err != nil {
+			return fmt.Errorf("rates not saved: %w", err)
+		}
+
+		slog.Info("exchange rates imported", "count", len(rr))
+	}
+
+	return nil
+}
+
 func main() {
 	var (
 		migrate       bool
 		migrationsDir string
 		configPath    string
+		ratesDir      string
+		fetchRates    bool
 	)
 
 	flag.BoolVar(&migrate, "migrate", false, "run migrations on start")
 	flag.StringVar(&migrationsDir, "migrations-dir", "./migrations", "path to migrations directory")
 	flag.StringVar(&configPath, "conf", "./configs/local.toml", "path to app config")
+	flag.StringVar(&ratesDir, "import-rates", "", "import exchange rates from directory with ECB and CBR xml files and exit")
+	flag.BoolVar(&fetchRates, "fetch-rates", false, "fetch today exchange rates from ECB and CBR and exit")
 	flag.Parse()
 
 	var conf config.Config
@@ -80,12 +105,47 @@ func main() {
 	userStorage := &postgres.UserStorage{Client: pgClient}
 	operationStorage := &postgres.OperationStorage{Client: pgClient}
 	keywordStorage := &postgres.KeywordStorage{Client: pgClient}
+	subscriptionStorage := &postgres.SubscriptionStorage{Client: pgClient}
+	billStorage := &postgres.BillStorage{Client: pgClient}
+	debtStorage := &postgres.DebtStorage{Client: pgClient}
+	splitStorage := &postgres.SplitStorage{Client: pgClient}
+	accountStorage := &postgres.AccountStorage{Client: pgClient}
+	rateStorage := &postgres.ExchangeRateStorage{Client: pgClient}
+	reconciliationStorage := &postgres.ReconciliationStorage{Client: pgClient}
+	netWorthStorage := &postgres.NetWorthStorage{Client: pgClient}
+	assetStorage := &postgres.AssetStorage{Client: pgClient}
+	backupStorage := &postgres.BackupStorage{Client: pgClient}
+
+	if ratesDir != "" || fetchRates {
+		var providers []rates.Provider
+
+		if ratesDir != "" {
+			providers = append(providers, rates.Dir(ratesDir))
+		}
+
+		if fetchRates {
+			client := &http.Client{Timeout: 30 * time.Second}
+			providers = append(providers, rates.NewECB(client), rates.NewCBR(client))
+		}
+
+		if err := importRates(context.Background(), rateStorage, providers...); err != nil {
+			slogx.Fatal(err.Error())
+		}
+
+		return
+	}
 
 	stateStorage := expirable.NewLRU[string, state.State](100, nil, time.Hour*24)
 
 	userService := service.NewUser(userStorage)
-
-	bot, err := bot.New(conf, stateStorage, categoryStorage, userService, operationStorage, keywordStorage)
+	allowanceService := service.NewAllowance(operationStorage, billStorage, subscriptionStorage, rateStorage)
+	reportService := service.NewReport(operationStorage, rateStorage, reconciliationStorage, assetStorage)
+	netWorthService := service.NewNetWorth(accountStorage, debtStorage, netWorthStorage, rateStorage, assetStorage)
+
+	bot, err := bot.New(conf, stateStorage, categoryStorage, userService, operationStorage, keywordStorage,
+		subscriptionStorage, billStorage, debtStorage, splitStorage,
+		accountStorage, reconciliationStorage, assetStorage, rateStorage, backupStorage, allowanceService,
+		reportService, netWorthService)
 	if err != nil {
 		slogx.Fatal(err.Error())
 	}
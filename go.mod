@@ -13,6 +13,7 @@ require (
 	github.com/pressly/goose/v3 v3.18.0
 	github.com/rmg/iso4217 v1.0.1
 	github.com/stretchr/testify v1.8.4
+	golang.org/x/text v0.14.0
 	gopkg.in/telebot.v3 v3.2.1
 )
 
@@ -32,7 +33,6 @@ require (
 	golang.org/x/crypto v0.17.0 // indirect
 	golang.org/x/exp v0.0.0-20240119083558-1b970713d09a // indirect
 	golang.org/x/sync v0.6.0 // indirect
-	golang.org/x/text v0.14.0 // indirect
 	golang.org/x/tools v0.17.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240125205218-1f4bbc51befe // indirect
 	google.golang.org/grpc v1.60.1 // indirect
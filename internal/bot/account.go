@@ -0,0 +1,249 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/ysomad/financer/internal/bot/msg"
+	botstate "github.com/ysomad/financer/internal/bot/state"
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+	"github.com/ysomad/financer/internal/postgres"
+)
+
+// resolveAccount returns account mentioned by name, if name is empty returns
+// default account or first account in currency if currency is not empty.
+func (b *Bot) resolveAccount(ctx context.Context, uid int64, name, currency string) (domain.Account, error) {
+	if name != "" {
+		return b.account.FindByName(ctx, uid, name)
+	}
+
+	if currency == "" {
+		return b.account.FindDefault(ctx, uid)
+	}
+
+	// accounts are ordered by default first
+	accs, err := b.account.ListByUserID(ctx, uid)
+	if err != nil {
+		return domain.Account{}, err
+	}
+
+	for _, acc := range accs {
+		if acc.Currency == currency {
+			return acc, nil
+		}
+	}
+
+	return domain.Account{}, postgres.ErrNotFound
+}
+
+// addAccount adds account from command payload in format {name} {?currency} {?opening balance}.
+func (b *Bot) addAccount(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	args := c.Args()
+	if len(args) < 1 || len(args) > 3 {
+		return c.Send(msg.Get(msg.AccountAddUsage, usr.Language))
+	}
+
+	acc := domain.Account{
+		ID:       uuid.NewString(),
+		UID:      usr.ID,
+		Name:     args[0],
+		Currency: usr.Currency,
+	}
+
+	opening := ""
+
+	for _, arg := range args[1:] {
+		if domain.IsCurrency(arg) {
+			acc.Currency = arg
+			continue
+		}
+
+		opening = arg
+	}
+
+	m, err := money.Parse(opening, acc.Currency)
+	if err != nil {
+		return c.Send(msg.Get(msg.AccountAddUsage, usr.Language))
+	}
+
+	acc.OpeningBalance = m
+
+	if err := acc.Validate(); err != nil {
+		return c.Send(msg.Get(msg.AccountAddUsage, usr.Language))
+	}
+
+	err = b.account.Save(stdContext(c), postgres.SaveAccountParams{
+		ID:             acc.ID,
+		UID:            acc.UID,
+		Name:           acc.Name,
+		Currency:       acc.Currency,
+		OpeningBalance: acc.OpeningBalance,
+		CreatedAt:      time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, postgres.ErrAlreadyExists) {
+			return c.Send(msg.Getf(msg.AccountExists, usr.Language, acc.Name))
+		}
+
+		return fmt.Errorf("account not saved: %w", err)
+	}
+
+	return c.Send(msg.Getf(msg.AccountAdded, usr.Language,
+		acc.Name, msg.Money(acc.OpeningBalance, acc.Currency, usr.Language), acc.Name))
+}
+
+func (b *Bot) listBalances(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	balances, err := b.account.ListBalances(stdContext(c), usr.ID)
+	if err != nil {
+		return fmt.Errorf("balances not listed: %w", err)
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(msg.Get(msg.BalancesTitle, usr.Language))
+	sb.WriteString("\n")
+
+	for _, ab := range balances {
+		sb.WriteString("\n")
+		sb.WriteString(msg.Getf(msg.BalanceItem, usr.Language, ab.Name, msg.Money(ab.Balance, ab.Currency, usr.Language)))
+
+		if ab.IsDefault {
+			sb.WriteString(" ⭐")
+		}
+	}
+
+	return c.Send(sb.String())
+}
+
+func (b *Bot) selectDefaultAccount(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	accs, err := b.account.ListByUserID(stdContext(c), usr.ID)
+	if err != nil {
+		return fmt.Errorf("accounts not listed: %w", err)
+	}
+
+	kb := &tele.ReplyMarkup{}
+	step := botstate.StepAccountDefault
+	rows := make([]tele.Row, 0, len(accs)+1)
+
+	for _, acc := range accs {
+		rows = append(rows, kb.Row(kb.Data(acc.Name+" "+acc.Currency, step.String(), acc.ID)))
+	}
+
+	rows = append(rows, kb.Row(btnCancel(kb, usr.Language)))
+	kb.Inline(rows...)
+
+	return c.Send(msg.Get(msg.AccountDefaultSelection, usr.Language), kb)
+}
+
+func (b *Bot) setDefaultAccount(c tele.Context, usr domain.User, accountID string) error {
+	ctx := stdContext(c)
+
+	acc, err := b.account.FindByID(ctx, usr.ID, accountID)
+	if err != nil {
+		return fmt.Errorf("account not found: %w", err)
+	}
+
+	if err := b.account.SetDefault(ctx, usr.ID, acc.ID); err != nil {
+		return fmt.Errorf("default account not set: %w", err)
+	}
+
+	return c.Edit(msg.Getf(msg.AccountDefaultSaved, usr.Language, acc.Name))
+}
+
+// transfer moves money between accounts, command payload format is
+// {amount} {from account} {to account} {?amount in to account currency}.
+func (b *Bot) transfer(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	args := c.Args()
+	if len(args) != 3 && len(args) != 4 {
+		return c.Send(msg.Get(msg.TransferUsage, usr.Language))
+	}
+
+	ctx := stdContext(c)
+	accs := make([]domain.Account, 2)
+
+	for i, arg := range args[1:3] {
+		var err error
+
+		name := strings.TrimPrefix(arg, "@")
+
+		accs[i], err = b.account.FindByName(ctx, usr.ID, name)
+		if err != nil {
+			if errors.Is(err, postgres.ErrNotFound) {
+				return c.Send(msg.Getf(msg.AccountNotFound, usr.Language, name))
+			}
+
+			return fmt.Errorf("account not found: %w", err)
+		}
+	}
+
+	m, err := money.Parse(args[0], accs[0].Currency)
+	if err != nil {
+		return c.Send(msg.Get(msg.TransferUsage, usr.Language))
+	}
+
+	t := domain.AccountTransfer{
+		From:      accs[0],
+		To:        accs[1],
+		Money:     m,
+		ToMoney:   m,
+		OccuredAt: time.Now(),
+	}
+
+	if len(args) == 4 {
+		t.ToMoney, err = money.Parse(args[3], t.To.Currency)
+		if err != nil {
+			return c.Send(msg.Get(msg.TransferUsage, usr.Language))
+		}
+	} else if t.From.Currency != t.To.Currency {
+		return c.Send(msg.Getf(msg.TransferToAmountRequired, usr.Language, t.To.Currency))
+	}
+
+	if err := t.Validate(); err != nil {
+		return c.Send(msg.Get(msg.TransferUsage, usr.Language))
+	}
+
+	if err := b.operation.SaveTransfer(ctx, postgres.SaveTransferParams{
+		ID:          uuid.NewString(),
+		UID:         usr.ID,
+		AccountID:   t.From.ID,
+		ToAccountID: t.To.ID,
+		Name:        t.From.Name + " → " + t.To.Name,
+		Currency:    t.From.Currency,
+		Money:       -t.Money,
+		ToCurrency:  t.To.Currency,
+		ToMoney:     t.ToMoney,
+		OccuredAt:   t.OccuredAt,
+		CreatedAt:   time.Now(),
+	}); err != nil {
+		return fmt.Errorf("transfer not saved: %w", err)
+	}
+
+	return c.Send(msg.Getf(msg.TransferSaved, usr.Language,
+		msg.Money(t.Money, t.From.Currency, usr.Language), t.From.Name, msg.Money(t.ToMoney, t.To.Currency, usr.Language), t.To.Name))
+}
@@ -0,0 +1,98 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/ysomad/financer/internal/bot/msg"
+	"github.com/ysomad/financer/internal/domain"
+)
+
+func (b *Bot) today(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	a, err := b.allowance.Today(stdContext(c), usr, time.Now())
+	if err != nil {
+		return fmt.Errorf("allowance not calculated: %w", err)
+	}
+
+	text := msg.Getf(msg.Today, usr.Language,
+		msg.Money(a.Today, usr.Currency, usr.Language),
+		a.From.Format(dateLayout), a.To.AddDate(0, 0, -1).Format(dateLayout), a.DaysLeft,
+		msg.Money(a.Income, usr.Currency, usr.Language),
+		msg.Money(a.Commitments, usr.Currency, usr.Language),
+		msg.Money(a.Spent+a.SpentToday, usr.Currency, usr.Language),
+		msg.Money(a.SpentToday, usr.Currency, usr.Language))
+
+	if a.Approximate {
+		text += "\n\n" + msg.Get(msg.ReportApproximate, usr.Language)
+	}
+
+	return c.Send(text)
+}
+
+// allowanceFooter returns safe to spend today amount which is shown after expense confirmation.
+func (b *Bot) allowanceFooter(ctx context.Context, usr domain.User) (string, error) {
+	a, err := b.allowance.Today(ctx, usr, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("allowance not calculated: %w", err)
+	}
+
+	return msg.Getf(msg.TodayFooter, usr.Language, msg.Money(a.Today, usr.Currency, usr.Language)), nil
+}
+
+func (b *Bot) toggleAllowanceFooter(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	usr.AllowanceFooter = !usr.AllowanceFooter
+
+	if err := b.user.Update(stdContext(c), usr); err != nil {
+		return fmt.Errorf("allowance footer not toggled: %w", err)
+	}
+
+	if usr.AllowanceFooter {
+		return c.Send(msg.Get(msg.TodayFooterOn, usr.Language))
+	}
+
+	return c.Send(msg.Get(msg.TodayFooterOff, usr.Language))
+}
+
+func (b *Bot) setPayDay(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	args := c.Args()
+	if len(args) != 1 {
+		return c.Send(msg.Get(msg.PayDayUsage, usr.Language))
+	}
+
+	payDay, err := strconv.Atoi(args[0])
+	if err != nil {
+		return c.Send(msg.Get(msg.PayDayUsage, usr.Language))
+	}
+
+	usr.PayDay = payDay
+
+	if err := b.user.Update(stdContext(c), usr); err != nil {
+		if errors.Is(err, domain.ErrInvalidPayDay) {
+			return c.Send(msg.Get(msg.PayDayUsage, usr.Language))
+		}
+
+		return fmt.Errorf("pay day not set: %w", err)
+	}
+
+	return c.Send(msg.Getf(msg.PayDaySaved, usr.Language, usr.PayDay))
+}
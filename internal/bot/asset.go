@@ -0,0 +1,246 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/ysomad/financer/internal/bot/msg"
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+	"github.com/ysomad/financer/internal/postgres"
+)
+
+// addAsset adds asset from command payload in format {ticker} {?decimals}.
+func (b *Bot) addAsset(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	args := c.Args()
+	if len(args) != 1 && len(args) != 2 {
+		return c.Send(msg.Get(msg.AssetAddUsage, usr.Language))
+	}
+
+	a := domain.Asset{
+		ID:        uuid.NewString(),
+		UID:       usr.ID,
+		Ticker:    args[0],
+		Decimals:  domain.DefaultAssetDecimals,
+		CreatedAt: time.Now(),
+	}
+
+	if len(args) == 2 {
+		var err error
+
+		a.Decimals, err = strconv.Atoi(args[1])
+		if err != nil {
+			return c.Send(msg.Get(msg.AssetAddUsage, usr.Language))
+		}
+	}
+
+	if err := a.Validate(); err != nil {
+		return c.Send(msg.Get(msg.AssetAddUsage, usr.Language))
+	}
+
+	if err := b.asset.Save(stdContext(c), a); err != nil {
+		if errors.Is(err, postgres.ErrAlreadyExists) {
+			return c.Send(msg.Getf(msg.AssetExists, usr.Language, a.Ticker))
+		}
+
+		return fmt.Errorf("asset not saved: %w", err)
+	}
+
+	return c.Send(msg.Getf(msg.AssetAdded, usr.Language, a.Ticker, a.Ticker))
+}
+
+// recordAsset records bought or sold asset, command payload format is {amount} {ticker} {?note}.
+func (b *Bot) recordAsset(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	args := c.Args()
+	if len(args) < 2 {
+		return c.Send(msg.Get(msg.AssetUsage, usr.Language))
+	}
+
+	ctx := stdContext(c)
+	ticker := strings.ToUpper(args[1])
+
+	a, err := b.asset.FindByTicker(ctx, usr.ID, ticker)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return c.Send(msg.Getf(msg.AssetNotFound, usr.Language, ticker))
+		}
+
+		return fmt.Errorf("asset not found: %w", err)
+	}
+
+	amount, err := money.ParseDecimal(args[0], a.Decimals)
+	if err != nil || amount.IsZero() {
+		return c.Send(msg.Get(msg.AssetUsage, usr.Language))
+	}
+
+	holding, err := b.holding(ctx, usr.ID, a.ID)
+	if err != nil {
+		return err
+	}
+
+	total := holding.Amount.Add(amount)
+	if total.Sign() < 0 {
+		return c.Send(msg.Getf(msg.AssetNotEnough, usr.Language,
+			msg.Decimal(holding.Amount, a.Ticker, a.DisplayDecimals(), usr.Language)))
+	}
+
+	if err := b.asset.SaveOperation(ctx, postgres.SaveAssetOperationParams{
+		ID:        uuid.NewString(),
+		UID:       usr.ID,
+		AssetID:   a.ID,
+		Amount:    amount,
+		Note:      strings.Join(args[2:], " "),
+		OccuredAt: time.Now(),
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("asset operation not saved: %w", err)
+	}
+
+	return c.Send(msg.Getf(msg.AssetSaved, usr.Language,
+		msg.Decimal(amount, a.Ticker, a.Decimals, usr.Language),
+		msg.Decimal(total, a.Ticker, a.DisplayDecimals(), usr.Language)))
+}
+
+// holding returns amount of asset held by user.
+func (b *Bot) holding(ctx context.Context, uid int64, assetID string) (domain.Holding, error) {
+	holdings, err := b.asset.ListHoldings(ctx, uid)
+	if err != nil {
+		return domain.Holding{}, fmt.Errorf("holdings not listed: %w", err)
+	}
+
+	for _, h := range holdings {
+		if h.ID == assetID {
+			return h, nil
+		}
+	}
+
+	return domain.Holding{}, postgres.ErrNotFound
+}
+
+// listAssets sends assets held by user valued in default currency by the latest rate.
+func (b *Bot) listAssets(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	ctx := stdContext(c)
+
+	holdings, err := b.asset.ListHoldings(ctx, usr.ID)
+	if err != nil {
+		return fmt.Errorf("holdings not listed: %w", err)
+	}
+
+	if len(holdings) == 0 {
+		return c.Send(msg.Get(msg.AssetsEmpty, usr.Language))
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(msg.Get(msg.AssetsTitle, usr.Language))
+	sb.WriteString("\n")
+
+	approximate := false
+
+	for _, h := range holdings {
+		amount := msg.Decimal(h.Amount, h.Ticker, h.DisplayDecimals(), usr.Language)
+
+		sb.WriteString("\n")
+
+		rate, exact, err := b.report.Rate(ctx, usr.ID, h.Ticker, usr.Currency, time.Now())
+		if err != nil {
+			if !errors.Is(err, domain.ErrRateNotFound) {
+				return fmt.Errorf("rate not found: %w", err)
+			}
+
+			sb.WriteString(msg.Getf(msg.AssetItemNoRate, usr.Language, amount, h.Ticker))
+
+			continue
+		}
+
+		value, err := money.FromFloat(h.Amount.Float()*rate, usr.Currency)
+		if err != nil {
+			return fmt.Errorf("asset not valued: %w", err)
+		}
+
+		approximate = approximate || !exact
+
+		sb.WriteString(msg.Getf(msg.AssetItem, usr.Language, amount, msg.Money(value, usr.Currency, usr.Language)))
+	}
+
+	if approximate {
+		sb.WriteString("\n\n")
+		sb.WriteString(msg.Get(msg.ReportApproximate, usr.Language))
+	}
+
+	return c.Send(sb.String())
+}
+
+// setAssetRate saves today price of asset, command payload format is {ticker} {price} {?currency}.
+// Prices are saved to shared exchange rates, so only tickers which are not currencies are accepted.
+func (b *Bot) setAssetRate(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	args := c.Args()
+	if len(args) != 2 && len(args) != 3 {
+		return c.Send(msg.Get(msg.AssetRateUsage, usr.Language))
+	}
+
+	ctx := stdContext(c)
+	ticker := strings.ToUpper(args[0])
+
+	if _, err := b.asset.FindByTicker(ctx, usr.ID, ticker); err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return c.Send(msg.Getf(msg.AssetNotFound, usr.Language, ticker))
+		}
+
+		return fmt.Errorf("asset not found: %w", err)
+	}
+
+	price, err := strconv.ParseFloat(strings.Replace(args[1], ",", ".", 1), 64)
+	if err != nil || price <= 0 {
+		return c.Send(msg.Get(msg.AssetRateUsage, usr.Language))
+	}
+
+	currency := usr.Currency
+	if len(args) == 3 {
+		currency = strings.ToUpper(args[2])
+	}
+
+	if !domain.IsCurrency(currency) {
+		return c.Send(msg.Get(msg.AssetRateUsage, usr.Language))
+	}
+
+	now := time.Now()
+
+	if err := b.asset.SavePrice(ctx, usr.ID, domain.ExchangeRate{
+		Date:   time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		Base:   ticker,
+		Quote:  currency,
+		Rate:   price,
+		Source: postgres.RateSourceUser,
+	}, now); err != nil {
+		return fmt.Errorf("asset price not saved: %w", err)
+	}
+
+	return c.Send(msg.Getf(msg.AssetRateSaved, usr.Language, ticker, formatRate(price)+" "+currency))
+}
@@ -0,0 +1,111 @@
+package bot
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/ysomad/financer/internal/bot/msg"
+	botstate "github.com/ysomad/financer/internal/bot/state"
+	"github.com/ysomad/financer/internal/domain"
+)
+
+const (
+	backupExt         = ".json"
+	maxBackupFileMB   = 20
+	maxBackupFileSize = maxBackupFileMB << 20
+)
+
+// sendBackup sends JSON archive with all user data which can be restored by sending it back.
+func (b *Bot) sendBackup(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	now := time.Now()
+
+	bk, err := b.backup.Dump(stdContext(c), usr.ID, now)
+	if err != nil {
+		return fmt.Errorf("backup not dumped: %w", err)
+	}
+
+	data, err := json.MarshalIndent(bk, "", "  ")
+	if err != nil {
+		return fmt.Errorf("backup not encoded: %w", err)
+	}
+
+	return c.Send(&tele.Document{
+		File:     tele.FromReader(bytes.NewReader(data)),
+		FileName: "financer_backup_" + now.Format("2006-01-02") + backupExt,
+		MIME:     "application/json",
+		Caption: msg.Getf(msg.BackupCaption, usr.Language,
+			len(bk.Categories), len(bk.Accounts), len(bk.Operations), len(bk.Keywords)),
+	})
+}
+
+// previewRestore reads backup from document and asks user to confirm restore.
+func (b *Bot) previewRestore(c tele.Context, usr domain.User, doc *tele.Document) error {
+	if doc.FileSize > maxBackupFileSize {
+		return c.Send(msg.Getf(msg.ImportTooLarge, usr.Language, maxBackupFileMB))
+	}
+
+	rc, err := b.tele.File(&doc.File)
+	if err != nil {
+		return fmt.Errorf("file not downloaded: %w", err)
+	}
+	defer rc.Close()
+
+	var bk domain.Backup
+
+	if err := json.NewDecoder(io.LimitReader(rc, maxBackupFileSize)).Decode(&bk); err != nil {
+		return c.Send(msg.Get(msg.RestoreInvalidFile, usr.Language))
+	}
+
+	if err := bk.Validate(); err != nil {
+		if errors.Is(err, domain.ErrBackupVersion) {
+			return c.Send(msg.Getf(msg.RestoreVersion, usr.Language, bk.Version, domain.BackupVersion))
+		}
+
+		return c.Send(msg.Get(msg.RestoreInvalidFile, usr.Language))
+	}
+
+	b.state.Add(usr.IDString(), botstate.State{Step: botstate.StepRestoreConfirm, Data: bk})
+
+	kb := &tele.ReplyMarkup{}
+	kb.Inline(
+		kb.Row(kb.Data(msg.Get(msg.BtnRestore, usr.Language), botstate.StepRestoreConfirm.String())),
+		kb.Row(btnCancel(kb, usr.Language)),
+	)
+
+	return c.Send(msg.Getf(msg.RestorePreview, usr.Language, bk.CreatedAt.Format(dateLayout),
+		len(bk.Categories), len(bk.Accounts), len(bk.Operations), len(bk.Keywords)), kb)
+}
+
+// restoreBackup restores confirmed backup to user, records which already exist are skipped.
+func (b *Bot) restoreBackup(c tele.Context, usr domain.User) error {
+	state, ok := b.state.Get(usr.IDString())
+	if !ok {
+		return fmt.Errorf("restore confirm callback: %w", errStateNotFound)
+	}
+
+	bk, ok := state.Data.(domain.Backup)
+	if !ok {
+		return fmt.Errorf("restore confirm callback: %w", errInvalidStateData)
+	}
+
+	b.state.Remove(usr.IDString())
+
+	res, err := b.backup.Restore(stdContext(c), usr.ID, bk, time.Now())
+	if err != nil {
+		return fmt.Errorf("backup not restored: %w", err)
+	}
+
+	return c.Edit(msg.Getf(msg.RestoreDone, bk.User.Language,
+		res.Categories, res.Accounts, res.Operations, res.Keywords))
+}
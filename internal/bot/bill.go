@@ -0,0 +1,302 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/ysomad/financer/internal/bot/msg"
+	botstate "github.com/ysomad/financer/internal/bot/state"
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+	"github.com/ysomad/financer/internal/postgres"
+)
+
+// billDueLayout is layout of due date in data of bill paid button.
+const billDueLayout = "20060102"
+
+var billRemindDays = [...]int{0, 1, 3, 5, 7}
+
+// addBill parses bill from command payload in format {day of month} {amount} {?currency} {name}.
+func (b *Bot) addBill(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	args := c.Args()
+	if len(args) < 3 {
+		return c.Send(msg.Get(msg.BillAddUsage, usr.Language))
+	}
+
+	dueDay, err := strconv.Atoi(args[0])
+	if err != nil || dueDay < 1 || dueDay > 31 {
+		return c.Send(msg.Get(msg.BillAddUsage, usr.Language))
+	}
+
+	m, currency, name, ok := parseMoneyArgs(args[1:], usr.Currency)
+	if !ok {
+		return c.Send(msg.Get(msg.BillAddUsage, usr.Language))
+	}
+
+	bill := domain.Bill{
+		UID:       usr.ID,
+		Name:      strings.Join(name, " "),
+		Currency:  currency,
+		Money:     -m,
+		DueDay:    dueDay,
+		NextDueAt: domain.NextDueDate(dueDay, time.Now()),
+	}
+
+	kb := &tele.ReplyMarkup{}
+	step := botstate.StepBillRemindDays
+	btns := make([]tele.Btn, 0, len(billRemindDays))
+
+	for _, days := range billRemindDays {
+		text := msg.Getf(msg.BtnDaysBefore, usr.Language, days)
+		btns = append(btns, kb.Data(text, step.String(), strconv.Itoa(days)))
+	}
+
+	kb.Inline(
+		kb.Row(btns...),
+		kb.Row(btnCancel(kb, usr.Language)),
+	)
+
+	b.state.Add(usr.IDString(), botstate.State{Step: step, Data: bill})
+
+	return c.Send(msg.Getf(msg.BillRemindDaysSelection, usr.Language, bill.Name), kb)
+}
+
+func (b *Bot) saveBill(c tele.Context, usr domain.User, data string) error {
+	defer b.state.Remove(usr.IDString())
+
+	state, ok := b.state.Get(usr.IDString())
+	if !ok {
+		return fmt.Errorf("bill remind days callback: %w", errStateNotFound)
+	}
+
+	bill, ok := state.Data.(domain.Bill)
+	if !ok {
+		return fmt.Errorf("bill remind days callback: %w", errInvalidStateData)
+	}
+
+	days, err := strconv.Atoi(data)
+	if err != nil {
+		return fmt.Errorf("bill remind days callback: %w", errUnsupportedCallbackData)
+	}
+
+	if err := b.bill.Save(stdContext(c), postgres.SaveBillParams{
+		ID:         uuid.NewString(),
+		UID:        bill.UID,
+		Name:       bill.Name,
+		Currency:   bill.Currency,
+		Money:      bill.Money,
+		DueDay:     bill.DueDay,
+		RemindDays: days,
+		NextDueAt:  bill.NextDueAt,
+		CreatedAt:  time.Now(),
+	}); err != nil {
+		return fmt.Errorf("bill not saved: %w", err)
+	}
+
+	return c.Edit(msg.Getf(msg.BillAdded, usr.Language,
+		bill.Name, msg.Money(-bill.Money, bill.Currency, usr.Language), bill.NextDueAt.Format(dateLayout), days))
+}
+
+func (b *Bot) listBills(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	bills, err := b.bill.ListByUserID(stdContext(c), usr.ID)
+	if err != nil {
+		return fmt.Errorf("bills not listed: %w", err)
+	}
+
+	if len(bills) == 0 {
+		return c.Send(msg.Get(msg.BillsEmpty, usr.Language))
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(msg.Get(msg.BillsTitle, usr.Language))
+
+	kb := &tele.ReplyMarkup{}
+	rows := make([]tele.Row, 0, len(bills)+1)
+
+	for _, bill := range bills {
+		sb.WriteString("\n\n")
+		sb.WriteString(msg.Getf(msg.BillItem, usr.Language,
+			bill.Name, msg.Money(-bill.Money, bill.Currency, usr.Language), bill.DueDay, bill.NextDueAt.Format(dateLayout)))
+
+		text := msg.Getf(msg.BtnDelete, usr.Language, bill.Name)
+		rows = append(rows, kb.Row(kb.Data(text, botstate.StepBillDelete.String(), bill.ID)))
+	}
+
+	rows = append(rows, kb.Row(btnCancel(kb, usr.Language)))
+	kb.Inline(rows...)
+
+	return c.Send(sb.String(), kb)
+}
+
+func (b *Bot) deleteBill(c tele.Context, usr domain.User, billID string) error {
+	ctx := stdContext(c)
+
+	bill, err := b.bill.FindByID(ctx, usr.ID, billID)
+	if err != nil {
+		return fmt.Errorf("bill not found: %w", err)
+	}
+
+	if err := b.bill.Delete(ctx, usr.ID, billID, time.Now()); err != nil {
+		return fmt.Errorf("bill not deleted: %w", err)
+	}
+
+	return c.Edit(msg.Getf(msg.BillDeleted, usr.Language, bill.Name))
+}
+
+// billPaidData returns data of button which pays bill due at its next due date.
+func billPaidData(bill domain.Bill) string {
+	return bill.ID + ":" + bill.NextDueAt.Format(billDueLayout)
+}
+
+// startBillPayment asks user to confirm or adjust amount of paid bill. Button of reminder about already paid
+// due date is outdated, so bill is not paid twice by the same reminder.
+func (b *Bot) startBillPayment(c tele.Context, usr domain.User, data string) error {
+	billID, due, _ := strings.Cut(data, ":")
+
+	bill, err := b.bill.FindByID(stdContext(c), usr.ID, billID)
+	if err != nil {
+		return fmt.Errorf("bill not found: %w", err)
+	}
+
+	if due != bill.NextDueAt.Format(billDueLayout) {
+		return c.Edit(msg.Getf(msg.BillPaymentOutdated, usr.Language, bill.Name, bill.NextDueAt.Format(dateLayout)))
+	}
+
+	kb := &tele.ReplyMarkup{}
+	btnSave := kb.Data(msg.Getf(msg.BtnBillSave, usr.Language, msg.Money(-bill.Money, bill.Currency, usr.Language)),
+		botstate.StepBillSave.String())
+
+	kb.Inline(
+		kb.Row(btnSave),
+		kb.Row(btnCancel(kb, usr.Language)),
+	)
+
+	b.state.Add(usr.IDString(), botstate.State{Step: botstate.StepBillAmount, Data: bill})
+
+	return c.Edit(msg.Getf(msg.BillPaymentAmount, usr.Language, bill.Name, msg.Money(-bill.Money, bill.Currency, usr.Language)), kb)
+}
+
+// payBill saves bill payment as an operation, if there is no keyword for the bill name user is asked for category.
+func (b *Bot) payBill(c tele.Context, usr domain.User, bill domain.Bill, m money.Money) error {
+	b.state.Remove(usr.IDString())
+
+	acc, err := b.resolveAccount(stdContext(c), usr.ID, "", bill.Currency)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return c.Send(msg.Getf(msg.AccountCurrencyNotFound, usr.Language, bill.Currency))
+		}
+
+		return err
+	}
+
+	return b.recordOperation(c, usr, operation{
+		name:      bill.Name,
+		money:     m,
+		account:   acc,
+		occuredAt: time.Now(),
+		billID:    bill.ID,
+		billDueAt: bill.NextDueAt,
+	})
+}
+
+// saveBillPayment moves bill from paid due date to the next one and saves payment operation,
+// false is returned if the due date is already paid.
+func (b *Bot) saveBillPayment(ctx context.Context, billID string, paidDueAt time.Time,
+	p postgres.SaveOperationParams,
+) (bool, error) {
+	bill, err := b.bill.FindByID(ctx, p.UID, billID)
+	if err != nil {
+		return false, fmt.Errorf("bill not found: %w", err)
+	}
+
+	paid, err := b.bill.SavePayment(ctx, postgres.SaveBillPaymentParams{
+		BillID:    bill.ID,
+		PaidDueAt: paidDueAt,
+		NextDueAt: domain.NextDueDate(bill.DueDay, paidDueAt.AddDate(0, 0, 1)),
+		Operation: p,
+	})
+	if err != nil {
+		return false, fmt.Errorf("bill payment not saved: %w", err)
+	}
+
+	return paid, nil
+}
+
+// remindBills periodically notifies users about upcoming and overdue bills until bot is stopped.
+func (b *Bot) remindBills(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		b.sendBillReminders(context.Background())
+
+		select {
+		case <-b.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *Bot) sendBillReminders(ctx context.Context) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	reminders, err := b.bill.ListToRemind(ctx, today)
+	if err != nil {
+		slog.ErrorContext(ctx, "bills to remind not listed", "err", err.Error())
+	}
+
+	for _, r := range reminders {
+		// due bills are notified by escalation
+		if r.Bill.NextDueAt.After(today) {
+			b.notifyBill(ctx, r, msg.BillReminder)
+		}
+
+		if err := b.bill.MarkReminded(ctx, r.Bill.ID, now); err != nil {
+			slog.ErrorContext(ctx, "bill not marked as reminded", "err", err.Error(), "bill", r.Bill.ID)
+		}
+	}
+
+	overdue, err := b.bill.ListToEscalate(ctx, today)
+	if err != nil {
+		slog.ErrorContext(ctx, "bills to escalate not listed", "err", err.Error())
+	}
+
+	for _, r := range overdue {
+		b.notifyBill(ctx, r, msg.BillOverdue)
+
+		if err := b.bill.MarkEscalated(ctx, r.Bill.ID, now); err != nil {
+			slog.ErrorContext(ctx, "bill not marked as escalated", "err", err.Error(), "bill", r.Bill.ID)
+		}
+	}
+}
+
+func (b *Bot) notifyBill(ctx context.Context, n postgres.BillNotification, id msg.ID) {
+	kb := &tele.ReplyMarkup{}
+	kb.Inline(kb.Row(kb.Data(msg.Get(msg.BtnBillPaid, n.Language), botstate.StepBillPaid.String(), billPaidData(n.Bill))))
+
+	text := msg.Getf(id, n.Language, n.Bill.Name, msg.Money(-n.Bill.Money, n.Bill.Currency, n.Language), n.Bill.NextDueAt.Format(dateLayout))
+
+	if _, err := b.tele.Send(tele.ChatID(n.Bill.UID), text, kb); err != nil {
+		slog.ErrorContext(ctx, "bill notification not sent", "err", err.Error(), "bill", n.Bill.ID)
+	}
+}
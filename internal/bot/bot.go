@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
@@ -27,23 +28,61 @@ import (
 const defaultLang = "en"
 
 type Bot struct {
-	tele      *tele.Bot
-	state     *expirable.LRU[string, botstate.State]
-	category  *postgres.CategoryStorage
-	user      *service.User
-	operation *postgres.OperationStorage
-	keyword   *postgres.KeywordStorage
+	tele           *tele.Bot
+	state          *expirable.LRU[string, botstate.State]
+	category       *postgres.CategoryStorage
+	user           *service.User
+	allowance      *service.Allowance
+	report         *service.Report
+	networth       *service.NetWorth
+	operation      *postgres.OperationStorage
+	keyword        *postgres.KeywordStorage
+	subscription   *postgres.SubscriptionStorage
+	bill           *postgres.BillStorage
+	debt           *postgres.DebtStorage
+	split          *postgres.SplitStorage
+	account        *postgres.AccountStorage
+	reconciliation *postgres.ReconciliationStorage
+	asset          *postgres.AssetStorage
+	rate           *postgres.ExchangeRateStorage
+	backup         *postgres.BackupStorage
+
+	remindersInterval time.Duration
+	duplicates        domain.DuplicateTolerance
+	done              chan struct{}
 }
 
 func New(conf config.Config, st *expirable.LRU[string, botstate.State], cat *postgres.CategoryStorage,
-	usr *service.User, op *postgres.OperationStorage, kw *postgres.KeywordStorage,
+	usr *service.User, op *postgres.OperationStorage, kw *postgres.KeywordStorage, sub *postgres.SubscriptionStorage,
+	bill *postgres.BillStorage, debt *postgres.DebtStorage, split *postgres.SplitStorage, acc *postgres.AccountStorage,
+	rec *postgres.ReconciliationStorage, asset *postgres.AssetStorage, rate *postgres.ExchangeRateStorage,
+	backup *postgres.BackupStorage, allowance *service.Allowance, report *service.Report, networth *service.NetWorth,
 ) (*Bot, error) {
 	bot := &Bot{
-		state:     st,
-		category:  cat,
-		user:      usr,
-		operation: op,
-		keyword:   kw,
+		state:          st,
+		category:       cat,
+		user:           usr,
+		operation:      op,
+		keyword:        kw,
+		subscription:   sub,
+		bill:           bill,
+		debt:           debt,
+		split:          split,
+		account:        acc,
+		reconciliation: rec,
+		asset:          asset,
+		rate:           rate,
+		backup:         backup,
+		allowance:      allowance,
+		report:         report,
+		networth:       networth,
+
+		remindersInterval: conf.Reminders.Interval,
+		duplicates: domain.DuplicateTolerance{
+			Days:          conf.Duplicates.Days,
+			AmountPercent: conf.Duplicates.AmountPercent,
+		},
+		done: make(chan struct{}),
 	}
 
 	var err error
@@ -74,23 +113,56 @@ func New(conf config.Config, st *expirable.LRU[string, botstate.State], cat *pos
 	bot.tele.Handle("/add_category", bot.addCategory)
 	bot.tele.Handle("/delete_keywords", bot.deleteKeywords)
 
+	bot.tele.Handle("/subscriptions", bot.listSubscriptions)
+	bot.tele.Handle("/add_bill", bot.addBill)
+	bot.tele.Handle("/bills", bot.listBills)
+
+	bot.tele.Handle("/lend", bot.lend)
+	bot.tele.Handle("/borrow", bot.borrow)
+	bot.tele.Handle("/repay", bot.repay)
+	bot.tele.Handle("/debts", bot.listDebts)
+	bot.tele.Handle("/settle", bot.settleUp)
+
+	bot.tele.Handle("/today", bot.today)
+	bot.tele.Handle("/today_footer", bot.toggleAllowanceFooter)
+	bot.tele.Handle("/set_payday", bot.setPayDay)
+	bot.tele.Handle("/report", bot.sendReport)
+	bot.tele.Handle("/networth", bot.netWorth)
+
+	bot.tele.Handle("/add_account", bot.addAccount)
+	bot.tele.Handle("/balances", bot.listBalances)
+	bot.tele.Handle("/default_account", bot.selectDefaultAccount)
+	bot.tele.Handle("/transfer", bot.transfer)
+	bot.tele.Handle("/exchange", bot.exchange)
+	bot.tele.Handle("/reconcile", bot.reconcile)
+	bot.tele.Handle("/add_asset", bot.addAsset)
+	bot.tele.Handle("/asset", bot.recordAsset)
+	bot.tele.Handle("/assets", bot.listAssets)
+	bot.tele.Handle("/asset_rate", bot.setAssetRate)
+	bot.tele.Handle("/export", bot.export)
+	bot.tele.Handle("/backup", bot.sendBackup)
+	bot.tele.Handle("/duplicates", bot.listDuplicates)
+
 	bot.tele.Handle("/set_language", bot.setLanguage)
 	bot.tele.Handle("/set_currency", bot.setCurrency)
 
 	bot.tele.Handle(tele.OnCallback, bot.handleCallback)
 	bot.tele.Handle(tele.OnText, bot.handleText)
+	bot.tele.Handle(tele.OnDocument, bot.handleDocument)
 
 	return bot, nil
 }
 
 func (b *Bot) Start() {
 	if b.tele != nil {
+		go b.remindBills(b.remindersInterval)
 		b.tele.Start()
 	}
 }
 
 func (b *Bot) Stop() {
 	if b.tele != nil {
+		close(b.done)
 		b.tele.Stop()
 	}
 }
@@ -122,6 +194,110 @@ func (b *Bot) setCommands() error {
 			Text:        "delete_keywords",
 			Description: "Delete operation keywords",
 		},
+		{
+			Text:        "subscriptions",
+			Description: "List regular payments",
+		},
+		{
+			Text:        "add_bill",
+			Description: "Add bill reminder",
+		},
+		{
+			Text:        "bills",
+			Description: "List bill reminders",
+		},
+		{
+			Text:        "lend",
+			Description: "Lend money to a person",
+		},
+		{
+			Text:        "borrow",
+			Description: "Borrow money from a person",
+		},
+		{
+			Text:        "repay",
+			Description: "Repay debt partially or fully",
+		},
+		{
+			Text:        "debts",
+			Description: "List debts",
+		},
+		{
+			Text:        "settle",
+			Description: "Settle up split bills",
+		},
+		{
+			Text:        "today",
+			Description: "Show how much is safe to spend today",
+		},
+		{
+			Text:        "today_footer",
+			Description: "Toggle safe to spend amount after expenses",
+		},
+		{
+			Text:        "set_payday",
+			Description: "Change day of month when pay period starts",
+		},
+		{
+			Text:        "report",
+			Description: "Show income and expenses for a period",
+		},
+		{
+			Text:        "networth",
+			Description: "Show net worth history",
+		},
+		{
+			Text:        "add_account",
+			Description: "Add account or wallet",
+		},
+		{
+			Text:        "balances",
+			Description: "Show account balances",
+		},
+		{
+			Text:        "default_account",
+			Description: "Change default account",
+		},
+		{
+			Text:        "transfer",
+			Description: "Transfer money between accounts",
+		},
+		{
+			Text:        "exchange",
+			Description: "Exchange currency between accounts",
+		},
+		{
+			Text:        "reconcile",
+			Description: "Compare account balance with the real one",
+		},
+		{
+			Text:        "add_asset",
+			Description: "Add crypto or custom asset",
+		},
+		{
+			Text:        "asset",
+			Description: "Record bought or sold asset",
+		},
+		{
+			Text:        "assets",
+			Description: "Show assets and their value",
+		},
+		{
+			Text:        "asset_rate",
+			Description: "Set asset price",
+		},
+		{
+			Text:        "export",
+			Description: "Export operations to a file",
+		},
+		{
+			Text:        "backup",
+			Description: "Back up all data to a file",
+		},
+		{
+			Text:        "duplicates",
+			Description: "Find duplicate operations",
+		},
 	})
 	if err != nil {
 		return fmt.Errorf("commands not set: %w", err)
@@ -373,6 +549,18 @@ func (b *Bot) handleText(c tele.Context) error {
 		slog.InfoContext(ctx, "new category added", "name", catName, "type", catType)
 
 		return c.Send(msg.Getf(msg.CatAdded, usr.Language, catName))
+	case botstate.StepBillAmount:
+		bill, ok := state.Data.(domain.Bill)
+		if !ok {
+			return fmt.Errorf("bill amount: %w", errInvalidStateData)
+		}
+
+		m, err := money.Parse(strings.TrimPrefix(c.Text(), "-"), bill.Currency)
+		if err != nil || m <= 0 {
+			return c.Send(msg.Get(msg.InvalidOperationFmt, usr.Language))
+		}
+
+		return b.payBill(c, usr, bill, -m)
 	default:
 		// handle operation save
 		parts := strings.Split(c.Text(), " ")
@@ -380,6 +568,39 @@ func (b *Bot) handleText(c tele.Context) error {
 			return c.Send(msg.Get(msg.InvalidOperationFmt, usr.Language))
 		}
 
+		// account mention is searched only in operation name, split participants are mentioned the same way
+		end := len(parts)
+		if i := splitIndex(parts); i > 0 {
+			end = i
+		}
+
+		var accountName string
+
+		for i := 1; i < end; i++ {
+			if name, ok := domain.AccountMention(parts[i]); ok {
+				accountName = name
+				parts = slices.Delete(parts, i, i+1)
+				break
+			}
+		}
+
+		if len(parts) < 2 {
+			return c.Send(msg.Get(msg.InvalidOperationFmt, usr.Language))
+		}
+
+		acc, err := b.resolveAccount(ctx, usr.ID, accountName, "")
+		if err != nil {
+			if errors.Is(err, postgres.ErrNotFound) {
+				return c.Send(msg.Getf(msg.AccountNotFound, usr.Language, accountName))
+			}
+
+			return err
+		}
+
+		if i := splitIndex(parts); i > 0 {
+			return b.handleSplit(c, usr, acc, parts, i)
+		}
+
 		moneyStr := parts[0]
 
 		// костыль
@@ -387,7 +608,7 @@ func (b *Bot) handleText(c tele.Context) error {
 			moneyStr = "-" + moneyStr
 		}
 
-		money, err := money.Parse(moneyStr)
+		money, err := money.Parse(moneyStr, acc.Currency)
 		if err != nil {
 			return c.Send(msg.Get(msg.InvalidOperationFmt, usr.Language))
 		}
@@ -414,57 +635,155 @@ func (b *Bot) handleText(c tele.Context) error {
 			opName = strings.Join(parts[1:last], " ")
 		}
 
-		catType := domain.CatTypeExpenses
+		return b.recordOperation(c, usr, operation{
+			name:      opName,
+			money:     money,
+			account:   acc,
+			occuredAt: occuredAt,
+		})
+	}
+}
 
-		if money > 0 {
-			catType = domain.CatTypeIncome
-		}
+// recordOperation saves operation in category found by keyword, if there is no keyword user is asked for category.
+func (b *Bot) recordOperation(c tele.Context, usr domain.User, op operation) error {
+	ctx := stdContext(c)
+	catType := domain.CatTypeExpenses
 
-		// find operation with the same name
-		cat, err := b.keyword.FindCategory(ctx, usr.ID, opName, catType)
-		if err == nil {
-			err := b.operation.Save(ctx, postgres.SaveOperationParams{
-				ID:        uuid.New().String(),
-				UID:       usr.ID,
-				CatID:     cat.ID,
-				Operation: opName,
-				Currency:  usr.Currency,
-				Money:     money,
-				OccuredAt: occuredAt,
-				CreatedAt: time.Now(),
-			})
-			if err != nil {
-				return fmt.Errorf("operation not saved: %w", err)
-			}
+	if op.money > 0 {
+		catType = domain.CatTypeIncome
+	}
 
-			if cat.Type == domain.CatTypeIncome {
-				return c.Send(msg.Getf(msg.IncomeSaved, usr.Language, money.String(), usr.Currency, cat.Name, opName))
-			}
+	// find operation with the same name
+	cat, err := b.keyword.FindCategory(ctx, usr.ID, op.name, catType)
+	if err == nil {
+		return b.completeOperation(c, usr, op, cat, c.Send)
+	}
+	if !errors.Is(err, postgres.ErrNotFound) {
+		return fmt.Errorf("keyword search failed: %w", err)
+	}
+
+	step := botstate.StepCatSelection
+
+	kb, err := b.categoriesKeyboard(ctx, usr, step, catType, true)
+	if err != nil {
+		return err
+	}
+
+	b.state.Add(usr.IDString(), botstate.State{Step: step, Data: op})
+
+	return c.Send(msg.Get(msg.CatSelection, usr.Language), kb)
+}
+
+// completeOperation saves operation in category and replies with confirmation using send,
+// which is either c.Send or c.Edit depending on how category was chosen.
+func (b *Bot) completeOperation(c tele.Context, usr domain.User, op operation, cat postgres.Category,
+	send func(what any, opts ...any) error,
+) error {
+	ctx := stdContext(c)
+	opID := uuid.NewString()
 
-			return c.Send(msg.Getf(msg.ExpenseSaved, usr.Language, money.String(), usr.Currency, cat.Name, opName))
+	var (
+		dup   domain.Operation
+		isDup bool
+	)
+
+	// bill payment and split are not reverted if operation is discarded, so they are not checked
+	if op.billID == "" && op.split == nil {
+		dup, isDup = b.findDuplicate(ctx, usr.ID,
+			domain.NewFingerprint(op.name, op.account.Currency, op.money, op.occuredAt), op.occuredAt)
+	}
+
+	params := postgres.SaveOperationParams{
+		ID:        opID,
+		UID:       usr.ID,
+		AccountID: op.account.ID,
+		CatID:     cat.ID,
+		Operation: op.name,
+		Currency:  op.account.Currency,
+		Money:     op.money,
+		OccuredAt: op.occuredAt,
+		CreatedAt: time.Now(),
+	}
+
+	var footer string
+
+	// bill is moved to the next due date with the operation, so the same due date is not paid twice
+	if op.billID != "" {
+		paid, err := b.saveBillPayment(ctx, op.billID, op.billDueAt, params)
+		if err != nil {
+			return err
 		}
-		if !errors.Is(err, postgres.ErrNotFound) {
-			return fmt.Errorf("keyword search failed: %w", err)
+
+		if !paid {
+			return send(msg.Getf(msg.BillAlreadyPaid, usr.Language, op.name, op.billDueAt.Format(dateLayout)))
 		}
 
-		step := botstate.StepCatSelection
+		footer = b.operationFooter(ctx, usr, params)
+	} else {
+		var err error
 
-		kb, err := b.categoriesKeyboard(ctx, usr, step, catType, true)
+		if footer, err = b.saveOperation(ctx, usr, params); err != nil {
+			return fmt.Errorf("operation not saved: %w", err)
+		}
+	}
+
+	if op.split != nil {
+		splitFooter, err := b.saveSplit(ctx, usr, *op.split, opID)
 		if err != nil {
 			return err
 		}
 
-		b.state.Add(usr.IDString(), botstate.State{
-			Step: step,
-			Data: operation{
-				name:      opName,
-				money:     money,
-				occuredAt: occuredAt,
-			},
-		})
+		footer = splitFooter + footer
+	}
+
+	text := msg.Getf(msg.ExpenseSaved, usr.Language,
+		msg.Money(op.money.Abs(), op.account.Currency, usr.Language), cat.Name, op.account.Name, op.name)
+	if op.money > 0 {
+		text = msg.Getf(msg.IncomeSaved, usr.Language,
+			msg.Money(op.money, op.account.Currency, usr.Language), cat.Name, op.account.Name, op.name)
+	}
+
+	if isDup {
+		return send(text+footer+"\n\n"+duplicateWarning(usr, dup), duplicateKeyboard(usr, opID))
+	}
+
+	return send(text + footer)
+}
+
+// saveOperation saves operation and returns footer which must be appended to the operation confirmation.
+func (b *Bot) saveOperation(ctx context.Context, usr domain.User, p postgres.SaveOperationParams) (string, error) {
+	if err := b.operation.Save(ctx, p); err != nil {
+		return "", err
+	}
+
+	return b.operationFooter(ctx, usr, p), nil
+}
+
+// operationFooter charges subscription of saved operation and returns notes shown after confirmation.
+func (b *Bot) operationFooter(ctx context.Context, usr domain.User, p postgres.SaveOperationParams) string {
+	var footer strings.Builder
+
+	note, err := b.chargeSubscription(ctx, usr, p)
+	if err != nil {
+		slog.WarnContext(ctx, "subscription not charged", "err", err.Error(), "operation", p.Operation)
+	}
+
+	if note != "" {
+		footer.WriteString("\n\n" + note)
+	}
+
+	if usr.AllowanceFooter && p.Money < 0 {
+		note, err := b.allowanceFooter(ctx, usr)
+		if err != nil {
+			slog.WarnContext(ctx, "allowance footer not calculated", "err", err.Error())
+		}
 
-		return c.Send(msg.Get(msg.CatSelection, usr.Language), kb)
+		if note != "" {
+			footer.WriteString("\n\n" + note)
+		}
 	}
+
+	return footer.String()
 }
 
 // categoriesKeyboard builds inline keyboard with categories.
@@ -509,6 +828,26 @@ func (b *Bot) categoriesKeyboard(ctx context.Context, usr domain.User, nextStep
 	return kb, nil
 }
 
+// parseMoneyArgs parses command arguments in format {amount} {?currency} {rest...}.
+// Currency must be typed in upper case to not be confused with the rest of arguments.
+func parseMoneyArgs(args []string, defaultCurrency string) (money.Money, string, []string, bool) {
+	if len(args) < 2 {
+		return 0, "", nil, false
+	}
+
+	currency, rest := defaultCurrency, args[1:]
+	if domain.IsCurrency(args[1]) && len(args) > 2 {
+		currency, rest = args[1], args[2:]
+	}
+
+	m, err := money.Parse(strings.TrimPrefix(args[0], "~"), currency)
+	if err != nil || m <= 0 {
+		return 0, "", nil, false
+	}
+
+	return m, currency, rest, true
+}
+
 type buttonCallback struct {
 	unique string
 	data   string
@@ -531,7 +870,11 @@ func parseCallback(data string) (buttonCallback, error) {
 type operation struct {
 	name      string
 	money     money.Money
+	account   domain.Account
 	occuredAt time.Time
+	billID    string        // not empty if operation is a bill payment
+	billDueAt time.Time     // paid due date of bill
+	split     *domain.Split // not nil if operation is own share of split bill
 }
 
 func (b *Bot) handleCallback(c tele.Context) error {
@@ -563,29 +906,14 @@ func (b *Bot) handleCallback(c tele.Context) error {
 			return fmt.Errorf("currency selection callback: %w", errInvalidStateData)
 		}
 
-		if err := b.operation.Save(ctx, postgres.SaveOperationParams{
-			ID:        uuid.New().String(),
-			UID:       usr.ID,
-			CatID:     cb.data,
-			Operation: op.name,
-			Currency:  usr.Currency,
-			Money:     op.money,
-			OccuredAt: op.occuredAt,
-			CreatedAt: time.Now(),
-		}); err != nil {
-			return fmt.Errorf("currency selection callback: %w", err)
-		}
+		b.state.Remove(usr.IDString())
 
 		cat, err := b.category.FindByID(ctx, cb.data)
 		if err != nil {
 			return fmt.Errorf("category not found: %w", err)
 		}
 
-		if op.money > 0 {
-			return c.Edit(msg.Getf(msg.IncomeSaved, usr.Language, op.money.String(), usr.Currency, cat.Name, op.name))
-		}
-
-		return c.Edit(msg.Getf(msg.ExpenseSaved, usr.Language, op.money.String(), usr.Currency, cat.Name, op.name))
+		return b.completeOperation(c, usr, op, cat, c.Edit)
 	case botstate.StepCatRenameTypeSelection:
 		kb, err := b.categoriesKeyboard(ctx, usr, botstate.StepCatRenameSelection, domain.CatType(cb.data), false)
 		if err != nil {
@@ -640,6 +968,46 @@ func (b *Bot) handleCallback(c tele.Context) error {
 		}
 
 		return c.Edit(msg.Getf(msg.LangSaved, usr.Language, iso6391.NativeName(usr.Language)))
+	case botstate.StepSubscriptionTrack:
+		return b.trackSubscription(c, usr, cb.data)
+	case botstate.StepBillRemindDays:
+		return b.saveBill(c, usr, cb.data)
+	case botstate.StepBillDelete:
+		return b.deleteBill(c, usr, cb.data)
+	case botstate.StepBillPaid:
+		return b.startBillPayment(c, usr, cb.data)
+	case botstate.StepBillSave:
+		state, ok := b.state.Get(usr.IDString())
+		if !ok {
+			return fmt.Errorf("bill save callback: %w", errStateNotFound)
+		}
+
+		bill, ok := state.Data.(domain.Bill)
+		if !ok {
+			return fmt.Errorf("bill save callback: %w", errInvalidStateData)
+		}
+
+		return b.payBill(c, usr, bill, bill.Money)
+	case botstate.StepSplitSettle:
+		return b.markSettled(c, usr)
+	case botstate.StepAccountDefault:
+		return b.setDefaultAccount(c, usr, cb.data)
+	case botstate.StepReconcileAdjust:
+		return b.postAdjustment(c, usr, cb.data)
+	case botstate.StepReconcileReview:
+		return b.reviewOperations(c, usr, cb.data)
+	case botstate.StepImportColumn:
+		return b.mapImportColumn(c, usr, cb.data)
+	case botstate.StepImportConfirm:
+		return b.importOperations(c, usr, cb.data)
+	case botstate.StepRestoreConfirm:
+		return b.restoreBackup(c, usr)
+	case botstate.StepImportDuplicates:
+		return b.resolveImportDuplicates(c, usr, cb.data)
+	case botstate.StepDuplicateKeep:
+		return b.keepDuplicate(c, usr, cb.data)
+	case botstate.StepDuplicateDiscard:
+		return b.discardDuplicate(c, usr, cb.data)
 	case botstate.StepCancel:
 		b.state.Remove(usr.IDString())
 		return c.Edit(msg.Get(msg.OperationCanceled, usr.Language))
@@ -0,0 +1,168 @@
+package bot
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/ysomad/financer/internal/bot/msg"
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+	"github.com/ysomad/financer/internal/postgres"
+)
+
+func (b *Bot) lend(c tele.Context) error {
+	return b.saveDebt(c, domain.DebtTypeLend)
+}
+
+func (b *Bot) borrow(c tele.Context) error {
+	return b.saveDebt(c, domain.DebtTypeBorrow)
+}
+
+func (b *Bot) repay(c tele.Context) error {
+	return b.saveDebt(c, domain.DebtTypeRepay)
+}
+
+// saveDebt parses debt from command payload in format {amount} {?currency} {person}.
+func (b *Bot) saveDebt(c tele.Context, dt domain.DebtType) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	m, currency, args, ok := parseMoneyArgs(c.Args(), usr.Currency)
+	if !ok {
+		return c.Send(msg.Get(msg.DebtUsage, usr.Language))
+	}
+
+	ctx := stdContext(c)
+	person := strings.Join(args, " ")
+
+	switch dt {
+	case domain.DebtTypeBorrow:
+		m = -m
+	case domain.DebtTypeRepay:
+		balance, err := b.debtBalance(c, usr.ID, person, currency)
+		if err != nil {
+			return err
+		}
+
+		if balance == 0 {
+			return c.Send(msg.Getf(msg.DebtNotFound, usr.Language, person, currency))
+		}
+
+		if m > balance.Abs() {
+			return c.Send(msg.Getf(msg.DebtRepayTooMuch, usr.Language, person, msg.Money(balance.Abs(), currency, usr.Language)))
+		}
+
+		// repayment moves balance towards zero
+		if balance > 0 {
+			m = -m
+		}
+	}
+
+	if err := b.debt.Save(ctx, postgres.SaveDebtParams{
+		ID:        uuid.NewString(),
+		UID:       usr.ID,
+		Person:    person,
+		Type:      dt,
+		Currency:  currency,
+		Money:     m,
+		OccuredAt: time.Now(),
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("debt not saved: %w", err)
+	}
+
+	balance, err := b.debtBalance(c, usr.ID, person, currency)
+	if err != nil {
+		return err
+	}
+
+	return c.Send(msg.Get(msg.DebtSaved, usr.Language) + "\n\n" + formatDebtBalance(usr.Language, domain.DebtBalance{
+		Person:   person,
+		Currency: currency,
+		Money:    balance,
+	}))
+}
+
+func (b *Bot) debtBalance(c tele.Context, uid int64, person, currency string) (money.Money, error) {
+	balances, err := b.debt.ListPersonBalances(stdContext(c), uid, person)
+	if err != nil {
+		return 0, fmt.Errorf("debt balances not listed: %w", err)
+	}
+
+	for _, balance := range balances {
+		if balance.Currency == currency {
+			return balance.Money, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func (b *Bot) listDebts(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	balances, err := b.debt.ListBalances(stdContext(c), usr.ID)
+	if err != nil {
+		return fmt.Errorf("debt balances not listed: %w", err)
+	}
+
+	if len(balances) == 0 {
+		return c.Send(msg.Get(msg.DebtsEmpty, usr.Language))
+	}
+
+	var (
+		sb       strings.Builder
+		lent     = make(map[string]money.Money)
+		borrowed = make(map[string]money.Money)
+		curs     = make([]string, 0)
+	)
+
+	sb.WriteString(msg.Get(msg.DebtsTitle, usr.Language))
+	sb.WriteString("\n")
+
+	for _, balance := range balances {
+		sb.WriteString("\n")
+		sb.WriteString(formatDebtBalance(usr.Language, balance))
+
+		if !slices.Contains(curs, balance.Currency) {
+			curs = append(curs, balance.Currency)
+		}
+
+		if balance.Money > 0 {
+			lent[balance.Currency] += balance.Money
+		} else {
+			borrowed[balance.Currency] -= balance.Money
+		}
+	}
+
+	sb.WriteString("\n")
+
+	for _, cur := range curs {
+		sb.WriteString("\n")
+		sb.WriteString(msg.Getf(msg.DebtsTotal, usr.Language,
+			msg.Money(lent[cur], cur, usr.Language), msg.Money(borrowed[cur], cur, usr.Language)))
+	}
+
+	return c.Send(sb.String())
+}
+
+func formatDebtBalance(lang string, balance domain.DebtBalance) string {
+	switch {
+	case balance.Money > 0:
+		return msg.Getf(msg.DebtOwesYou, lang, balance.Person, msg.Money(balance.Money, balance.Currency, lang))
+	case balance.Money < 0:
+		return msg.Getf(msg.DebtYouOwe, lang, balance.Person, msg.Money(balance.Money.Abs(), balance.Currency, lang))
+	default:
+		return msg.Getf(msg.DebtSettled, lang, balance.Person)
+	}
+}
@@ -0,0 +1,151 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/ysomad/financer/internal/bot/msg"
+	botstate "github.com/ysomad/financer/internal/bot/state"
+	"github.com/ysomad/financer/internal/date"
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/postgres"
+)
+
+// duplicatesListed is max number of duplicate pairs listed by /duplicates command.
+const duplicatesListed = 10
+
+// findDuplicate returns recorded operation which is likely duplicate of new operation with fingerprint fp.
+// Errors are logged only, because duplicate check must not prevent operation from being saved.
+func (b *Bot) findDuplicate(ctx context.Context, uid int64, fp domain.Fingerprint, occuredAt time.Time) (domain.Operation, bool) {
+	from, to := b.duplicates.Window(occuredAt, occuredAt)
+
+	ops, err := b.operation.ListForDuplicates(ctx, uid, from, to)
+	if err != nil {
+		slog.WarnContext(ctx, "operations for duplicate check not listed", "err", err.Error())
+		return domain.Operation{}, false
+	}
+
+	return b.duplicates.FindDuplicate(ops, fp)
+}
+
+func duplicateWarning(usr domain.User, dup domain.Operation) string {
+	return msg.Getf(msg.DuplicateWarning, usr.Language, dup.OccuredAt.Format(dateLayout),
+		msg.Money(dup.Money, dup.Currency, usr.Language), html.EscapeString(dup.Name))
+}
+
+// duplicateKeyboard returns buttons to keep or discard operation which is likely duplicate.
+func duplicateKeyboard(usr domain.User, opID string) *tele.ReplyMarkup {
+	kb := &tele.ReplyMarkup{}
+	kb.Inline(kb.Row(
+		kb.Data(msg.Get(msg.BtnKeepBoth, usr.Language), botstate.StepDuplicateKeep.String(), opID),
+		kb.Data(msg.Get(msg.BtnDiscard, usr.Language), botstate.StepDuplicateDiscard.String(), opID),
+	))
+
+	return kb
+}
+
+// listDuplicates lists likely duplicate operations with buttons to discard or keep them, command payload
+// format is {?period}, see date.ParsePeriod for supported periods, all operations are checked if period is empty.
+func (b *Bot) listDuplicates(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	var from, to time.Time
+
+	if period := strings.Join(c.Args(), ""); period != "" {
+		var err error
+
+		from, to, err = date.ParsePeriod(period, time.Now())
+		if err != nil {
+			return c.Send(msg.Get(msg.DuplicatesUsage, usr.Language))
+		}
+	}
+
+	ops, err := b.operation.ListForDuplicates(stdContext(c), usr.ID, from, to)
+	if err != nil {
+		return fmt.Errorf("operations not listed: %w", err)
+	}
+
+	pairs := b.duplicates.FindDuplicates(ops)
+	if len(pairs) == 0 {
+		return c.Send(msg.Get(msg.DuplicatesEmpty, usr.Language))
+	}
+
+	var (
+		sb   strings.Builder
+		kb   = &tele.ReplyMarkup{}
+		rows = make([]tele.Row, 0, min(len(pairs), duplicatesListed))
+	)
+
+	sb.WriteString(msg.Getf(msg.DuplicatesTitle, usr.Language, len(pairs)))
+
+	for i, p := range pairs[:min(len(pairs), duplicatesListed)] {
+		sb.WriteString("\n\n")
+		sb.WriteString(msg.Getf(msg.DuplicatesItem, usr.Language, i+1,
+			p.Original.OccuredAt.Format(dateLayout), msg.Money(p.Original.Money, p.Original.Currency, usr.Language),
+			html.EscapeString(p.Original.Name),
+			p.Duplicate.OccuredAt.Format(dateLayout), msg.Money(p.Duplicate.Money, p.Duplicate.Currency, usr.Language),
+			html.EscapeString(p.Duplicate.Name)))
+
+		rows = append(rows, kb.Row(
+			kb.Data(msg.Getf(msg.BtnDiscardN, usr.Language, i+1), botstate.StepDuplicateDiscard.String(), p.Duplicate.ID),
+			kb.Data(msg.Getf(msg.BtnKeepN, usr.Language, i+1), botstate.StepDuplicateKeep.String(), p.Duplicate.ID),
+		))
+	}
+
+	if len(pairs) > duplicatesListed {
+		sb.WriteString("\n\n")
+		sb.WriteString(msg.Getf(msg.DuplicatesMore, usr.Language, duplicatesListed))
+	}
+
+	kb.Inline(rows...)
+
+	return c.Send(sb.String(), kb)
+}
+
+// keepDuplicate marks operation as not a duplicate, so it is not reported by /duplicates command.
+func (b *Bot) keepDuplicate(c tele.Context, usr domain.User, opID string) error {
+	if err := b.operation.KeepDuplicate(stdContext(c), usr.ID, opID); err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return c.Send(msg.Get(msg.DuplicateNotFound, usr.Language))
+		}
+
+		return fmt.Errorf("duplicate not kept: %w", err)
+	}
+
+	return c.Send(msg.Get(msg.DuplicateKept, usr.Language))
+}
+
+// discardDuplicate deletes operation which is duplicate.
+func (b *Bot) discardDuplicate(c tele.Context, usr domain.User, opID string) error {
+	ctx := stdContext(c)
+
+	op, err := b.operation.FindByID(ctx, usr.ID, opID)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return c.Send(msg.Get(msg.DuplicateNotFound, usr.Language))
+		}
+
+		return fmt.Errorf("operation not found: %w", err)
+	}
+
+	if err := b.operation.Delete(ctx, usr.ID, opID, time.Now()); err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return c.Send(msg.Get(msg.DuplicateNotFound, usr.Language))
+		}
+
+		return fmt.Errorf("operation not deleted: %w", err)
+	}
+
+	return c.Send(msg.Getf(msg.DuplicateDiscarded, usr.Language,
+		op.OccuredAt.Format(dateLayout), msg.Money(op.Money, op.Currency, usr.Language), html.EscapeString(op.Name)))
+}
@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/ysomad/financer/internal/bot/msg"
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+	"github.com/ysomad/financer/internal/postgres"
+)
+
+// exchange records currency exchange, command payload format is
+// {sold amount} {currency or @account} {bought amount} {currency or @account}.
+// Account is the first one in currency if currency is provided.
+func (b *Bot) exchange(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	args := c.Args()
+	if len(args) != 4 {
+		return c.Send(msg.Get(msg.ExchangeUsage, usr.Language))
+	}
+
+	ctx := stdContext(c)
+	amounts := make([]money.Money, 2)
+	accs := make([]domain.Account, 2)
+
+	for i := range 2 {
+		var err error
+
+		arg := args[i*2+1]
+
+		if name, ok := domain.AccountMention(arg); ok {
+			accs[i], err = b.resolveAccount(ctx, usr.ID, name, "")
+		} else if cur := strings.ToUpper(arg); domain.IsCurrency(cur) {
+			accs[i], err = b.resolveAccount(ctx, usr.ID, "", cur)
+		} else {
+			return c.Send(msg.Get(msg.ExchangeUsage, usr.Language))
+		}
+
+		if errors.Is(err, postgres.ErrNotFound) {
+			return c.Send(msg.Getf(msg.AccountNotFound, usr.Language, arg))
+		}
+
+		if err != nil {
+			return fmt.Errorf("account not found: %w", err)
+		}
+
+		amounts[i], err = money.Parse(args[i*2], accs[i].Currency)
+		if err != nil {
+			return c.Send(msg.Get(msg.ExchangeUsage, usr.Language))
+		}
+	}
+
+	e := domain.CurrencyExchange{
+		From:      accs[0],
+		To:        accs[1],
+		Money:     amounts[0],
+		ToMoney:   amounts[1],
+		OccuredAt: time.Now(),
+	}
+
+	if err := e.Validate(); err != nil {
+		return c.Send(msg.Get(msg.ExchangeUsage, usr.Language))
+	}
+
+	// reference rate is optional, exchange is saved without it if rates are not imported
+	refRate, _, err := b.report.Rate(ctx, usr.ID, e.To.Currency, e.From.Currency, e.OccuredAt)
+	if err != nil && !errors.Is(err, domain.ErrRateNotFound) {
+		slog.WarnContext(ctx, "reference rate not found", "err", err.Error())
+	}
+
+	if err := b.operation.SaveExchange(ctx, postgres.SaveExchangeParams{
+		SaveTransferParams: postgres.SaveTransferParams{
+			ID:          uuid.NewString(),
+			UID:         usr.ID,
+			AccountID:   e.From.ID,
+			ToAccountID: e.To.ID,
+			Name:        e.From.Currency + " → " + e.To.Currency,
+			Currency:    e.From.Currency,
+			Money:       -e.Money,
+			ToCurrency:  e.To.Currency,
+			ToMoney:     e.ToMoney,
+			OccuredAt:   e.OccuredAt,
+			CreatedAt:   time.Now(),
+		},
+		Rate:          e.Rate(),
+		ReferenceRate: refRate,
+	}); err != nil {
+		return fmt.Errorf("exchange not saved: %w", err)
+	}
+
+	text := msg.Getf(msg.ExchangeSaved, usr.Language,
+		msg.Money(e.Money, e.From.Currency, usr.Language), e.From.Name, msg.Money(e.ToMoney, e.To.Currency, usr.Language), e.To.Name,
+		e.To.Currency, formatRate(e.Rate()), e.From.Currency)
+
+	if refRate > 0 {
+		percent, loss := e.Spread(refRate)
+		text += "\n" + msg.Getf(msg.ExchangeSpread, usr.Language,
+			formatRate(refRate), e.From.Currency, strconv.FormatFloat(percent, 'f', 2, 64), msg.Money(loss, e.From.Currency, usr.Language))
+	}
+
+	return c.Send(text)
+}
+
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', 4, 64)
+}
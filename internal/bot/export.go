@@ -0,0 +1,147 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/ysomad/financer/internal/bot/msg"
+	"github.com/ysomad/financer/internal/date"
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/export"
+)
+
+const (
+	exportFormatCSV       = "csv"
+	exportFormatQIF       = "qif"
+	exportFormatLedger    = "ledger"
+	exportFormatBeancount = "beancount"
+)
+
+// exportFormat writes operations of user in [from, to) to w.
+type exportFormat struct {
+	ext   string
+	mime  string
+	write func(ctx context.Context, w io.Writer, usr domain.User, from, to time.Time) error
+}
+
+func (b *Bot) exportFormats() map[string]exportFormat {
+	return map[string]exportFormat{
+		exportFormatCSV:       {ext: "csv", mime: "text/csv", write: b.writeCSV},
+		exportFormatQIF:       {ext: "qif", mime: "application/qif", write: b.writeQIF},
+		exportFormatLedger:    {ext: "journal", mime: "text/plain", write: b.writeLedger},
+		exportFormatBeancount: {ext: "beancount", mime: "text/plain", write: b.writeBeancount},
+	}
+}
+
+// export sends operations file, command payload format is {?format} {?period},
+// see date.ParsePeriod for supported periods, all operations are exported if period is empty.
+func (b *Bot) export(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	args := c.Args()
+	formats := b.exportFormats()
+	name := exportFormatCSV
+
+	if len(args) > 0 {
+		if _, ok := formats[strings.ToLower(args[0])]; ok {
+			name = strings.ToLower(args[0])
+			args = args[1:]
+		}
+	}
+
+	now := time.Now()
+	from, to := time.Time{}, now.AddDate(0, 0, 1)
+	caption := msg.Get(msg.ExportCaptionAll, usr.Language)
+	fileName := "operations"
+
+	if period := strings.Join(args, ""); period != "" {
+		var err error
+
+		from, to, err = date.ParsePeriod(period, now)
+		if err != nil {
+			return c.Send(msg.Get(msg.ExportUsage, usr.Language))
+		}
+
+		last := to.AddDate(0, 0, -1)
+		caption = msg.Getf(msg.ExportCaption, usr.Language, from.Format(dateLayout), last.Format(dateLayout))
+		fileName += "_" + from.Format("2006-01-02") + "_" + last.Format("2006-01-02")
+	}
+
+	format := formats[name]
+	ctx := stdContext(c)
+
+	// file is streamed to telegram while operations are read from database
+	pr, pw := io.Pipe()
+	defer pr.Close()
+
+	go func() {
+		pw.CloseWithError(format.write(ctx, pw, usr, from, to))
+	}()
+
+	return c.Send(&tele.Document{
+		File:     tele.FromReader(pr),
+		FileName: fileName + "." + format.ext,
+		MIME:     format.mime,
+		Caption:  caption,
+	})
+}
+
+// csvConfig returns number format of spreadsheets in language locale.
+func csvConfig(lang string) export.CSVConfig {
+	if lang == "ru" {
+		return export.CSVSemicolon
+	}
+
+	return export.CSVComma
+}
+
+func (b *Bot) writeCSV(ctx context.Context, w io.Writer, usr domain.User, from, to time.Time) error {
+	cw, err := export.NewCSVWriter(w, csvConfig(usr.Language))
+	if err != nil {
+		return err
+	}
+
+	if err := b.operation.Export(ctx, usr.ID, from, to, cw.Write); err != nil {
+		return fmt.Errorf("operations not exported: %w", err)
+	}
+
+	return cw.Flush()
+}
+
+func (b *Bot) writeQIF(ctx context.Context, w io.Writer, usr domain.User, from, to time.Time) error {
+	qw := export.NewQIFWriter(w)
+
+	if err := b.operation.Export(ctx, usr.ID, from, to, qw.Write); err != nil {
+		return fmt.Errorf("operations not exported: %w", err)
+	}
+
+	return qw.Flush()
+}
+
+func (b *Bot) writeLedger(ctx context.Context, w io.Writer, usr domain.User, from, to time.Time) error {
+	lw := export.NewLedgerWriter(w)
+
+	if err := b.operation.Export(ctx, usr.ID, from, to, lw.Write); err != nil {
+		return fmt.Errorf("operations not exported: %w", err)
+	}
+
+	return lw.Flush()
+}
+
+func (b *Bot) writeBeancount(ctx context.Context, w io.Writer, usr domain.User, from, to time.Time) error {
+	bw := export.NewBeancountWriter(w)
+
+	if err := b.operation.Export(ctx, usr.ID, from, to, bw.Write); err != nil {
+		return fmt.Errorf("operations not exported: %w", err)
+	}
+
+	return bw.Flush()
+}
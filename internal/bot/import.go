@@ -0,0 +1,614 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/ysomad/financer/internal/bot/msg"
+	botstate "github.com/ysomad/financer/internal/bot/state"
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/importer"
+	"github.com/ysomad/financer/internal/postgres"
+)
+
+const (
+	maxImportFileMB    = 5
+	maxImportFileSize  = maxImportFileMB << 20
+	importPreviewRows  = 5
+	importPreviewErrs  = 3
+	importColumnLabel  = 32 // max length of column button text in runes
+	importSkipColumn   = -1
+	importWithCategory = "create"
+	importToOther      = "other"
+
+	importKeepDuplicates    = "keep"
+	importDiscardDuplicates = "discard"
+)
+
+// statementReaders read operations from files which need no column mapping by file extension.
+var statementReaders = map[string]func(r io.Reader, currency string) ([]domain.ImportedOperation, []importer.RowError, error){
+	".ofx":   importer.ReadOFX,
+	".qfx":   importer.ReadOFX,
+	".qif":   importer.ReadQIF,
+	".xml":   importer.ReadCAMT053,
+	".sta":   importer.ReadMT940,
+	".mt940": importer.ReadMT940,
+}
+
+// csvImportFields are operation fields in order they are mapped to columns of CSV file.
+var csvImportFields = []struct {
+	prompt   msg.ID
+	optional bool
+	set      func(m *importer.CSVMapping, col int)
+}{
+	{prompt: msg.ImportColumnDate, set: func(m *importer.CSVMapping, col int) { m.Date = col }},
+	{prompt: msg.ImportColumnAmount, set: func(m *importer.CSVMapping, col int) { m.Amount = col }},
+	{prompt: msg.ImportColumnName, set: func(m *importer.CSVMapping, col int) { m.Name = col }},
+	{prompt: msg.ImportColumnCategory, optional: true, set: func(m *importer.CSVMapping, col int) { m.Category = col }},
+	{prompt: msg.ImportColumnCurrency, optional: true, set: func(m *importer.CSVMapping, col int) { m.Currency = col }},
+}
+
+// csvImport is state of column mapping of CSV file.
+type csvImport struct {
+	file    importer.CSVFile
+	mapping importer.CSVMapping
+	field   int // index of csvImportFields which column is asked
+}
+
+// importDuplicates is state of choice whether operations which are likely duplicates
+// of recorded operations are imported.
+type importDuplicates struct {
+	ops        []domain.ImportedOperation
+	rowErrs    []importer.RowError
+	imported   int   // number of operations which were already imported
+	duplicates []int // indexes of ops which are likely duplicates
+}
+
+// pendingImport is state of import confirmation.
+type pendingImport struct {
+	ops     []domain.ImportedOperation
+	catIDs  []string // category of operation matched by name or suggested by keywords, empty if not found
+	kept    []bool   // operation is likely duplicate which user kept, nil if there are none
+	missing []importCategory
+}
+
+// importCategory is category of imported operation identified by name and type.
+type importCategory struct {
+	name    string
+	catType domain.CatType
+}
+
+// handleDocument imports operations from CSV file or bank statement, columns of CSV file are mapped
+// to operation fields by user. JSON file is restored as backup.
+func (b *Bot) handleDocument(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	doc := c.Message().Document
+	ext := strings.ToLower(filepath.Ext(doc.FileName))
+
+	if ext == backupExt {
+		return b.previewRestore(c, usr, doc)
+	}
+
+	read, ok := statementReaders[ext]
+
+	if !ok && ext != ".csv" {
+		return c.Send(msg.Get(msg.ImportUnsupportedFile, usr.Language))
+	}
+
+	if doc.FileSize > maxImportFileSize {
+		return c.Send(msg.Getf(msg.ImportTooLarge, usr.Language, maxImportFileMB))
+	}
+
+	rc, err := b.tele.File(&doc.File)
+	if err != nil {
+		return fmt.Errorf("file not downloaded: %w", err)
+	}
+	defer rc.Close()
+
+	r := io.LimitReader(rc, maxImportFileSize)
+
+	if ext == ".csv" {
+		return b.startCSVImport(c, usr, r)
+	}
+
+	ops, rowErrs, err := read(r, usr.Currency)
+	if err != nil {
+		return b.sendImportError(c, usr, err)
+	}
+
+	return b.previewImport(c, usr, ops, rowErrs, c.Send)
+}
+
+func (b *Bot) sendImportError(c tele.Context, usr domain.User, err error) error {
+	if errors.Is(err, importer.ErrEmptyFile) {
+		return c.Send(msg.Get(msg.ImportEmpty, usr.Language))
+	}
+
+	return c.Send(msg.Get(msg.ImportInvalidFile, usr.Language))
+}
+
+func (b *Bot) startCSVImport(c tele.Context, usr domain.User, r io.Reader) error {
+	f, err := importer.ReadCSV(r)
+	if err != nil {
+		return b.sendImportError(c, usr, err)
+	}
+
+	st := csvImport{
+		file: f,
+		mapping: importer.CSVMapping{
+			Date:     importSkipColumn,
+			Amount:   importSkipColumn,
+			Name:     importSkipColumn,
+			Category: importSkipColumn,
+			Currency: importSkipColumn,
+		},
+	}
+
+	b.state.Add(usr.IDString(), botstate.State{Step: botstate.StepImportColumn, Data: st})
+
+	text := msg.Getf(msg.ImportRead, usr.Language, len(f.Rows), f.Encoding, delimiterName(f.Delimiter)) +
+		"\n\n" + importColumnPrompt(usr, st)
+
+	return c.Send(text, importColumnsKeyboard(usr, st))
+}
+
+func delimiterName(d rune) string {
+	if d == '\t' {
+		return "<code>TAB</code>"
+	}
+
+	return "<code>" + string(d) + "</code>"
+}
+
+func importColumnPrompt(usr domain.User, st csvImport) string {
+	field := csvImportFields[st.field]
+	if field.prompt == msg.ImportColumnCurrency {
+		return msg.Getf(field.prompt, usr.Language, usr.Currency)
+	}
+
+	return msg.Get(field.prompt, usr.Language)
+}
+
+// importColumnsKeyboard returns button for every column of file titled by header or value of the first row.
+func importColumnsKeyboard(usr domain.User, st csvImport) *tele.ReplyMarkup {
+	kb := &tele.ReplyMarkup{}
+	step := botstate.StepImportColumn.String()
+	rows := make([]tele.Row, 0, st.file.Columns+2)
+
+	for i := range st.file.Columns {
+		label := st.file.Column(i)
+		if utf8.RuneCountInString(label) > importColumnLabel {
+			label = string([]rune(label)[:importColumnLabel]) + "…"
+		}
+
+		rows = append(rows, kb.Row(kb.Data(fmt.Sprintf("%d. %s", i+1, label), step, strconv.Itoa(i))))
+	}
+
+	if csvImportFields[st.field].optional {
+		rows = append(rows, kb.Row(kb.Data(msg.Get(msg.BtnSkip, usr.Language), step, strconv.Itoa(importSkipColumn))))
+	}
+
+	rows = append(rows, kb.Row(btnCancel(kb, usr.Language)))
+	kb.Inline(rows...)
+
+	return kb
+}
+
+func (b *Bot) mapImportColumn(c tele.Context, usr domain.User, data string) error {
+	state, ok := b.state.Get(usr.IDString())
+	if !ok {
+		return fmt.Errorf("import column callback: %w", errStateNotFound)
+	}
+
+	st, ok := state.Data.(csvImport)
+	if !ok {
+		return fmt.Errorf("import column callback: %w", errInvalidStateData)
+	}
+
+	col, err := strconv.Atoi(data)
+	if err != nil || col >= st.file.Columns || col < importSkipColumn ||
+		(col == importSkipColumn && !csvImportFields[st.field].optional) {
+		return fmt.Errorf("import column callback: invalid column %s", data)
+	}
+
+	csvImportFields[st.field].set(&st.mapping, col)
+	st.field++
+
+	if st.field < len(csvImportFields) {
+		b.state.Add(usr.IDString(), botstate.State{Step: botstate.StepImportColumn, Data: st})
+		return c.Edit(importColumnPrompt(usr, st), importColumnsKeyboard(usr, st))
+	}
+
+	ops, rowErrs := st.file.Operations(st.mapping, usr.Currency)
+
+	return b.previewImport(c, usr, ops, rowErrs, c.Edit)
+}
+
+// previewImport asks user whether to import operations which are likely duplicates of recorded operations
+// and confirms import of operations. Preview is sent with send, which is either c.Send or c.Edit.
+func (b *Bot) previewImport(c tele.Context, usr domain.User, ops []domain.ImportedOperation, rowErrs []importer.RowError,
+	send func(what any, opts ...any) error,
+) error {
+	if len(ops) == 0 {
+		return b.confirmImport(c, usr, ops, nil, rowErrs, 0, send)
+	}
+
+	ctx := stdContext(c)
+
+	var externalIDs []string
+
+	for _, op := range ops {
+		if op.ExternalID != "" {
+			externalIDs = append(externalIDs, op.ExternalID)
+		}
+	}
+
+	imported := make(map[string]bool)
+
+	if len(externalIDs) > 0 {
+		ids, err := b.operation.ListImported(ctx, usr.ID, externalIDs)
+		if err != nil {
+			return fmt.Errorf("imported operations not listed: %w", err)
+		}
+
+		for _, id := range ids {
+			imported[id] = true
+		}
+	}
+
+	duplicates, originals, err := b.findImportDuplicates(ctx, usr.ID, ops, imported)
+	if err != nil {
+		return err
+	}
+
+	if len(duplicates) == 0 {
+		return b.confirmImport(c, usr, ops, nil, rowErrs, len(imported), send)
+	}
+
+	b.state.Add(usr.IDString(), botstate.State{
+		Step: botstate.StepImportDuplicates,
+		Data: importDuplicates{ops: ops, rowErrs: rowErrs, imported: len(imported), duplicates: duplicates},
+	})
+
+	sb := strings.Builder{}
+	sb.WriteString(msg.Getf(msg.ImportDuplicatesFound, usr.Language, len(duplicates)))
+
+	for i, idx := range duplicates[:min(len(duplicates), importPreviewRows)] {
+		op := ops[idx]
+
+		sb.WriteString("\n")
+		sb.WriteString(msg.Getf(msg.ImportDuplicateItem, usr.Language, op.OccuredAt.Format(dateLayout),
+			msg.Money(op.Money, op.Currency, usr.Language), html.EscapeString(op.Name),
+			originals[i].OccuredAt.Format(dateLayout), html.EscapeString(originals[i].Name)))
+	}
+
+	kb := &tele.ReplyMarkup{}
+	step := botstate.StepImportDuplicates.String()
+	kb.Inline(
+		kb.Row(
+			kb.Data(msg.Get(msg.BtnKeepBoth, usr.Language), step, importKeepDuplicates),
+			kb.Data(msg.Get(msg.BtnDiscard, usr.Language), step, importDiscardDuplicates),
+		),
+		kb.Row(btnCancel(kb, usr.Language)),
+	)
+
+	return send(sb.String(), kb)
+}
+
+// findImportDuplicates returns indexes of operations which are likely duplicates of recorded operations
+// and the recorded operations. Operations which were already imported are skipped on import, so not checked.
+func (b *Bot) findImportDuplicates(ctx context.Context, uid int64, ops []domain.ImportedOperation, imported map[string]bool,
+) ([]int, []domain.Operation, error) {
+	var first, last time.Time
+
+	for _, op := range ops {
+		if first.IsZero() || op.OccuredAt.Before(first) {
+			first = op.OccuredAt
+		}
+
+		if op.OccuredAt.After(last) {
+			last = op.OccuredAt
+		}
+	}
+
+	from, to := b.duplicates.Window(first, last)
+
+	recorded, err := b.operation.ListForDuplicates(ctx, uid, from, to)
+	if err != nil {
+		return nil, nil, fmt.Errorf("operations not listed: %w", err)
+	}
+
+	var (
+		duplicates []int
+		originals  []domain.Operation
+	)
+
+	for i, op := range ops {
+		if op.ExternalID != "" && imported[op.ExternalID] {
+			continue
+		}
+
+		if orig, ok := b.duplicates.FindDuplicate(recorded, op.Fingerprint()); ok {
+			duplicates = append(duplicates, i)
+			originals = append(originals, orig)
+		}
+	}
+
+	return duplicates, originals, nil
+}
+
+// resolveImportDuplicates imports operations which are likely duplicates as kept by user
+// or removes them from import, then confirms import.
+func (b *Bot) resolveImportDuplicates(c tele.Context, usr domain.User, data string) error {
+	state, ok := b.state.Get(usr.IDString())
+	if !ok {
+		return fmt.Errorf("import duplicates callback: %w", errStateNotFound)
+	}
+
+	st, ok := state.Data.(importDuplicates)
+	if !ok {
+		return fmt.Errorf("import duplicates callback: %w", errInvalidStateData)
+	}
+
+	if data == importKeepDuplicates {
+		kept := make([]bool, len(st.ops))
+		for _, i := range st.duplicates {
+			kept[i] = true
+		}
+
+		return b.confirmImport(c, usr, st.ops, kept, st.rowErrs, st.imported, c.Edit)
+	}
+
+	ops := make([]domain.ImportedOperation, 0, len(st.ops)-len(st.duplicates))
+
+	for i, op := range st.ops {
+		if !slices.Contains(st.duplicates, i) {
+			ops = append(ops, op)
+		}
+	}
+
+	return b.confirmImport(c, usr, ops, nil, st.rowErrs, st.imported, c.Edit)
+}
+
+// confirmImport shows the first operations of file, skipped rows, already imported operations
+// and categories which are not found in user categories by name. Operations without category in file
+// get category suggested by keywords.
+func (b *Bot) confirmImport(c tele.Context, usr domain.User, ops []domain.ImportedOperation, kept []bool,
+	rowErrs []importer.RowError, imported int, send func(what any, opts ...any) error,
+) error {
+	if len(ops) == 0 {
+		b.state.Remove(usr.IDString())
+		return send(msg.Get(msg.ImportNothing, usr.Language) + "\n\n" + formatRowErrors(usr, rowErrs))
+	}
+
+	ctx := stdContext(c)
+
+	cats, err := b.userCategories(c, usr)
+	if err != nil {
+		return err
+	}
+
+	keywords, err := b.keyword.ListByUserID(ctx, usr.ID)
+	if err != nil {
+		return fmt.Errorf("keywords not listed: %w", err)
+	}
+
+	var (
+		st       = pendingImport{ops: ops, catIDs: make([]string, len(ops)), kept: kept}
+		catNames = make([]string, len(ops))
+		seen     = make(map[string]bool)
+	)
+
+	for i, op := range ops {
+		if op.Category == "" {
+			if kw, ok := domain.SuggestCategory(keywords, op.Name, op.CatType()); ok {
+				st.catIDs[i], catNames[i] = kw.CatID, kw.CatName
+			}
+
+			continue
+		}
+
+		ic := importCategory{name: op.Category, catType: op.CatType()}
+		catNames[i] = op.Category
+
+		if cat, ok := cats[ic.key()]; ok {
+			st.catIDs[i], catNames[i] = cat.ID, cat.Name
+			continue
+		}
+
+		if !seen[ic.key()] {
+			seen[ic.key()] = true
+			st.missing = append(st.missing, ic)
+		}
+	}
+
+	b.state.Add(usr.IDString(), botstate.State{Step: botstate.StepImportConfirm, Data: st})
+
+	sb := strings.Builder{}
+	sb.WriteString(msg.Getf(msg.ImportPreview, usr.Language, len(ops)))
+	sb.WriteString("\n")
+
+	for i, op := range ops[:min(len(ops), importPreviewRows)] {
+		sb.WriteString("\n")
+		sb.WriteString(msg.Getf(msg.ImportPreviewItem, usr.Language, op.OccuredAt.Format(dateLayout),
+			msg.Money(op.Money, op.Currency, usr.Language), html.EscapeString(op.Name), html.EscapeString(catNames[i])))
+	}
+
+	if len(rowErrs) > 0 {
+		sb.WriteString("\n\n")
+		sb.WriteString(formatRowErrors(usr, rowErrs))
+	}
+
+	if imported > 0 {
+		sb.WriteString("\n\n")
+		sb.WriteString(msg.Getf(msg.ImportDuplicates, usr.Language, imported))
+	}
+
+	kb := &tele.ReplyMarkup{}
+	step := botstate.StepImportConfirm.String()
+
+	if len(st.missing) == 0 {
+		kb.Inline(
+			kb.Row(kb.Data(msg.Get(msg.BtnImport, usr.Language), step, importToOther)),
+			kb.Row(btnCancel(kb, usr.Language)),
+		)
+
+		return send(sb.String(), kb)
+	}
+
+	names := make([]string, len(st.missing))
+	for i, ic := range st.missing {
+		names[i] = html.EscapeString(ic.name)
+	}
+
+	sb.WriteString("\n\n")
+	sb.WriteString(msg.Getf(msg.ImportMissingCategories, usr.Language, strings.Join(names, ", ")))
+
+	kb.Inline(
+		kb.Row(kb.Data(msg.Get(msg.BtnImportCreateCategories, usr.Language), step, importWithCategory)),
+		kb.Row(kb.Data(msg.Get(msg.BtnImportWithoutCategories, usr.Language), step, importToOther)),
+		kb.Row(btnCancel(kb, usr.Language)),
+	)
+
+	return send(sb.String(), kb)
+}
+
+func formatRowErrors(usr domain.User, rowErrs []importer.RowError) string {
+	sb := strings.Builder{}
+	sb.WriteString(msg.Getf(msg.ImportRowErrors, usr.Language, len(rowErrs)))
+
+	for _, e := range rowErrs[:min(len(rowErrs), importPreviewErrs)] {
+		sb.WriteString("\n")
+		sb.WriteString(msg.Getf(msg.ImportRowError, usr.Language, e.Row, html.EscapeString(e.Err.Error())))
+	}
+
+	return sb.String()
+}
+
+func (ic importCategory) key() string {
+	return ic.catType.String() + ":" + domain.NormalizeCategoryName(ic.name)
+}
+
+// userCategories returns user categories by type and normalized name.
+func (b *Bot) userCategories(c tele.Context, usr domain.User) (map[string]postgres.Category, error) {
+	cats, err := b.category.ListByUserID(stdContext(c), usr.ID, domain.CatTypeUnspecified)
+	if err != nil {
+		return nil, fmt.Errorf("categories not listed: %w", err)
+	}
+
+	res := make(map[string]postgres.Category, len(cats))
+	for _, cat := range cats {
+		res[importCategory{name: cat.Name, catType: cat.Type}.key()] = cat
+	}
+
+	return res, nil
+}
+
+// importOperations saves previewed operations to accounts in their currencies in one transaction,
+// missing categories are created if user chose so, otherwise operations are saved to OTHER category.
+// Operations which were already imported are skipped.
+func (b *Bot) importOperations(c tele.Context, usr domain.User, data string) error {
+	state, ok := b.state.Get(usr.IDString())
+	if !ok {
+		return fmt.Errorf("import confirm callback: %w", errStateNotFound)
+	}
+
+	st, ok := state.Data.(pendingImport)
+	if !ok {
+		return fmt.Errorf("import confirm callback: %w", errInvalidStateData)
+	}
+
+	b.state.Remove(usr.IDString())
+
+	ctx := stdContext(c)
+	now := time.Now()
+	created := make(map[string]string, len(st.missing))
+
+	p := postgres.ImportOperationsParams{
+		UID:        usr.ID,
+		Operations: make([]postgres.SaveOperationParams, len(st.ops)),
+	}
+
+	if data == importWithCategory {
+		for _, ic := range st.missing {
+			cat := postgres.SaveCategoryParams{
+				ID:        uuid.NewString(),
+				Name:      ic.name,
+				Type:      ic.catType,
+				Author:    usr.ID,
+				CreatedAt: now,
+			}
+
+			created[ic.key()] = cat.ID
+			p.Categories = append(p.Categories, cat)
+		}
+	}
+
+	accounts := make(map[string]string)
+
+	for i, op := range st.ops {
+		accountID, ok := accounts[op.Currency]
+		if !ok {
+			acc, err := b.resolveAccount(ctx, usr.ID, "", op.Currency)
+			if err != nil {
+				if errors.Is(err, postgres.ErrNotFound) {
+					return c.Edit(msg.Getf(msg.AccountCurrencyNotFound, usr.Language, op.Currency))
+				}
+
+				return fmt.Errorf("account not found: %w", err)
+			}
+
+			accountID = acc.ID
+			accounts[op.Currency] = accountID
+		}
+
+		catID := st.catIDs[i]
+		if catID == "" && op.Category != "" {
+			catID = created[importCategory{name: op.Category, catType: op.CatType()}.key()]
+		}
+
+		if catID == "" {
+			catID = domain.OtherCategoryID
+		}
+
+		p.Operations[i] = postgres.SaveOperationParams{
+			ID:         uuid.NewString(),
+			UID:        usr.ID,
+			AccountID:  accountID,
+			CatID:      catID,
+			Operation:  op.Name,
+			Currency:   op.Currency,
+			Money:      op.Money,
+			OccuredAt:  op.OccuredAt,
+			CreatedAt:  now,
+			ExternalID: op.ExternalID,
+		}
+
+		if st.kept != nil {
+			p.Operations[i].DuplicateKept = st.kept[i]
+		}
+	}
+
+	saved, err := b.operation.Import(ctx, p)
+	if err != nil {
+		return fmt.Errorf("operations not imported: %w", err)
+	}
+
+	return c.Edit(msg.Getf(msg.ImportDone, usr.Language, saved, int64(len(p.Operations))-saved, len(p.Categories)))
+}
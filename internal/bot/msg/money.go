@@ -0,0 +1,44 @@
+package msg
+
+import "github.com/ysomad/financer/internal/money"
+
+// Money formats money value in currency with number format and symbol position of language,
+// e.g. "1 234 567,50 ₽" in russian and "$1,234,567.50" in english. Zero decimals are hidden.
+func Money(m money.Money, currency, lang string) string {
+	symbol, known := money.Symbol(currency)
+
+	switch lang {
+	case "ru":
+		return m.Format(currency, money.FormatConfig{
+			Symbol:   symbol,
+			Thousand: "\u00a0", // no-break space
+			Decimal:  ",",
+		})
+	default:
+		return m.Format(currency, money.FormatConfig{
+			Symbol:   symbol,
+			Prefix:   known,
+			Thousand: ",",
+			Decimal:  ".",
+		})
+	}
+}
+
+// Decimal formats amount of asset with ticker rounded to scale digits after decimal point
+// with number format of language, e.g. "0,015 BTC" in russian and "0.015 BTC" in english.
+func Decimal(d money.Decimal, ticker string, scale int, lang string) string {
+	switch lang {
+	case "ru":
+		return d.Format(scale, money.FormatConfig{
+			Symbol:   ticker,
+			Thousand: "\u00a0", // no-break space
+			Decimal:  ",",
+		})
+	default:
+		return d.Format(scale, money.FormatConfig{
+			Symbol:   ticker,
+			Thousand: ",",
+			Decimal:  ".",
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package msg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+func TestMoney(t *testing.T) {
+	require.Equal(t, "1\u00a0234\u00a0567,50 ₽", Money(123456750, "RUB", "ru"))
+	require.Equal(t, "$1,234,567.50", Money(123456750, "USD", "en"))
+	require.Equal(t, "-$350", Money(-35000, "USD", "en"))
+	require.Equal(t, "1,500 CZK", Money(150000, "CZK", "en"))
+	require.Equal(t, "¥1,500", Money(1500, "JPY", "en"))
+	require.Equal(t, "1.234 KWD", Money(1234, "KWD", "en"))
+}
+
+func TestDecimal(t *testing.T) {
+	d, err := money.ParseDecimal("1234.123456789", 18)
+	require.NoError(t, err)
+
+	require.Equal(t, "1\u00a0234,12345679 BTC", Decimal(d, "BTC", 8, "ru"))
+	require.Equal(t, "1,234.12 ETH", Decimal(d, "ETH", 2, "en"))
+}
@@ -34,6 +34,160 @@ const (
 
 	KeywordsDeleted
 
+	// Subscriptions
+	SubscriptionsTitle
+	SubscriptionsEmpty
+	SubscriptionItem
+	SubscriptionPriceUp
+	SubscriptionTracked
+	SubscriptionAlreadyTracked
+	SubscriptionPriceIncreased
+
+	// Bills
+	BillAddUsage
+	BillRemindDaysSelection
+	BillAdded
+	BillsTitle
+	BillsEmpty
+	BillItem
+	BillDeleted
+	BillReminder
+	BillOverdue
+	BillPaymentAmount
+	BillPaymentOutdated
+	BillAlreadyPaid
+
+	// Debts
+	DebtUsage
+	DebtSaved
+	DebtNotFound
+	DebtRepayTooMuch
+	DebtOwesYou
+	DebtYouOwe
+	DebtSettled
+	DebtsTitle
+	DebtsEmpty
+	DebtsTotal
+
+	// Splits
+	SplitUsage
+	SplitSelf
+	SplitSaved
+	SettleUpTitle
+	SettleUpEmpty
+	SettleUpTransfer
+	SettledUp
+
+	// Allowance
+	Today
+	TodayFooter
+	TodayFooterOn
+	TodayFooterOff
+	PayDayUsage
+	PayDaySaved
+
+	// Accounts
+	AccountAddUsage
+	AccountAdded
+	AccountExists
+	AccountNotFound
+	AccountCurrencyNotFound
+	BalancesTitle
+	BalanceItem
+	AccountDefaultSelection
+	AccountDefaultSaved
+	TransferUsage
+	TransferToAmountRequired
+	TransferSaved
+	ExchangeUsage
+	ExchangeSaved
+	ExchangeSpread
+
+	// Reconciliation
+	ReconcileUsage
+	ReconcileMatched
+	ReconcileDiff
+	AdjustmentSaved
+	AdjustmentExists
+	ReviewEmpty
+	ReviewTitle
+	ReviewItem
+
+	// Reports
+	ReportUsage
+	ReportEmpty
+	ReportTitle
+	ReportCategory
+	ReportApproximate
+	ReportUnconverted
+	ReportDrift
+	ReportAssets
+	ReportAsset
+
+	// Net worth
+	NetWorthTitle
+	NetWorthChange
+
+	// Assets
+	AssetAddUsage
+	AssetAdded
+	AssetExists
+	AssetUsage
+	AssetNotFound
+	AssetSaved
+	AssetNotEnough
+	AssetsEmpty
+	AssetsTitle
+	AssetItem
+	AssetItemNoRate
+	AssetRateUsage
+	AssetRateSaved
+
+	// Export
+	ExportUsage
+	ExportCaption
+	ExportCaptionAll
+
+	// Import
+	ImportUnsupportedFile
+	ImportTooLarge
+	ImportInvalidFile
+	ImportEmpty
+	ImportRead
+	ImportColumnDate
+	ImportColumnAmount
+	ImportColumnName
+	ImportColumnCategory
+	ImportColumnCurrency
+	ImportPreview
+	ImportPreviewItem
+	ImportRowErrors
+	ImportRowError
+	ImportMissingCategories
+	ImportDuplicates
+	ImportDuplicatesFound
+	ImportDuplicateItem
+	ImportNothing
+	ImportDone
+
+	// Backup
+	BackupCaption
+	RestoreInvalidFile
+	RestoreVersion
+	RestorePreview
+	RestoreDone
+
+	// Duplicates
+	DuplicateWarning
+	DuplicateKept
+	DuplicateDiscarded
+	DuplicateNotFound
+	DuplicatesUsage
+	DuplicatesTitle
+	DuplicatesItem
+	DuplicatesMore
+	DuplicatesEmpty
+
 	// logic errors
 	InvalidCurr
 	InvalidOperationFmt
@@ -54,6 +208,23 @@ const (
 	BtnOther
 	BtnIncome
 	BtnExpenses
+	BtnTrackSubscription
+	BtnDaysBefore
+	BtnDelete
+	BtnBillPaid
+	BtnBillSave
+	BtnSettled
+	BtnPostAdjustment
+	BtnReviewOperations
+	BtnSkip
+	BtnImport
+	BtnImportCreateCategories
+	BtnImportWithoutCategories
+	BtnRestore
+	BtnKeepBoth
+	BtnDiscard
+	BtnKeepN
+	BtnDiscardN
 )
 
 type Message struct {
@@ -94,12 +265,12 @@ var messages = map[ID]Message{
 		EN: "Language was set to <b>%s</b>",
 	},
 	ExpenseSaved: {
-		RU: "Потрачено <b>%s %s</b> в категории %s\n\n<i>%s</i>",
-		EN: "Spent <b>%s %s</b> in %s category\n\n<i>%s</i>",
+		RU: "Потрачено <b>%s</b> в категории %s со счета %s\n\n<i>%s</i>",
+		EN: "Spent <b>%s</b> in %s category from %s\n\n<i>%s</i>",
 	},
 	IncomeSaved: {
-		RU: "Заработано <b>%s %s</b> в категории %s\n\n<i>%s</i>",
-		EN: "Earned <b>%s %s</b> in %s category\n\n<i>%s</i>",
+		RU: "Заработано <b>%s</b> в категории %s на счет %s\n\n<i>%s</i>",
+		EN: "Earned <b>%s</b> in %s category to %s\n\n<i>%s</i>",
 	},
 	CatRenameTypeSelection: {
 		RU: "Категорию расходов или доходов хочешь переименовать?",
@@ -133,6 +304,514 @@ var messages = map[ID]Message{
 		RU: "Все ключевые слова операций удалены",
 		EN: "All operation keywords deleted",
 	},
+	SubscriptionsTitle: {
+		RU: "🔁 Регулярные платежи",
+		EN: "🔁 Regular payments",
+	},
+	SubscriptionsEmpty: {
+		RU: "Регулярных платежей не найдено, я найду их когда операция с одинаковым названием повторится хотя бы 3 раза через равные промежутки времени",
+		EN: "No regular payments found, I'll detect them when an operation with the same name repeats at least 3 times at regular intervals",
+	},
+	SubscriptionItem: {
+		RU: "%s <b>%s</b> — %s каждые %d дн.\nВ месяц: %s, в год: %s\nСледующий платеж: %s",
+		EN: "%s <b>%s</b> — %s every %d days\nMonthly: %s, yearly: %s\nNext payment: %s",
+	},
+	SubscriptionPriceUp: {
+		RU: "⚠️ Цена выросла с %s до %s",
+		EN: "⚠️ Price went up from %s to %s",
+	},
+	SubscriptionTracked: {
+		RU: "<b>%s</b> теперь отслеживается как регулярный платеж",
+		EN: "<b>%s</b> is now tracked as a regular payment",
+	},
+	SubscriptionAlreadyTracked: {
+		RU: "<b>%s</b> уже отслеживается",
+		EN: "<b>%s</b> is already tracked",
+	},
+	SubscriptionPriceIncreased: {
+		RU: "⚠️ Цена подписки <b>%s</b> выросла с %s до %s",
+		EN: "⚠️ <b>%s</b> subscription price went up from %s to %s",
+	},
+	BillAddUsage: {
+		RU: "Отправь счет в формате <code>/add_bill {день месяца} {сумма} {?валюта} {название}</code>, например <code>/add_bill 25 700 интернет</code>",
+		EN: "Send bill in format <code>/add_bill {day of month} {amount} {?currency} {name}</code>, for example <code>/add_bill 25 700 internet</code>",
+	},
+	BillRemindDaysSelection: {
+		RU: "За сколько дней до оплаты напомнить о <b>%s</b>?",
+		EN: "How many days before the due date should I remind you about <b>%s</b>?",
+	},
+	BillAdded: {
+		RU: "Счет <b>%s</b> ~%s сохранен, следующая оплата %s, напомню за %d дн.",
+		EN: "Bill <b>%s</b> ~%s saved, next payment is on %s, I'll remind you %d days before",
+	},
+	BillsTitle: {
+		RU: "🧾 Счета",
+		EN: "🧾 Bills",
+	},
+	BillsEmpty: {
+		RU: "Счетов нет, добавь счет командой /add_bill",
+		EN: "No bills yet, add one with /add_bill",
+	},
+	BillItem: {
+		RU: "<b>%s</b> ~%s, %d числа каждого месяца\nСледующая оплата: %s",
+		EN: "<b>%s</b> ~%s, %d day of each month\nNext payment: %s",
+	},
+	BillDeleted: {
+		RU: "Счет <b>%s</b> удален",
+		EN: "Bill <b>%s</b> deleted",
+	},
+	BillReminder: {
+		RU: "🔔 Не забудь оплатить <b>%s</b> ~%s до %s",
+		EN: "🔔 Don't forget to pay <b>%s</b> ~%s by %s",
+	},
+	BillOverdue: {
+		RU: "❗ <b>%s</b> ~%s нужно было оплатить %s, счет все еще не оплачен",
+		EN: "❗ <b>%s</b> ~%s was due on %s and is still not paid",
+	},
+	BillPaymentAmount: {
+		RU: "Отправь сумму оплаты <b>%s</b> или нажми кнопку чтобы сохранить ~%s",
+		EN: "Send paid amount for <b>%s</b> or press the button to save ~%s",
+	},
+	BillPaymentOutdated: {
+		RU: "Это напоминание устарело, следующая оплата <b>%s</b> %s",
+		EN: "This reminder is outdated, next payment of <b>%s</b> is on %s",
+	},
+	BillAlreadyPaid: {
+		RU: "<b>%s</b> за %s уже оплачен, операция не сохранена",
+		EN: "<b>%s</b> due on %s is already paid, operation is not saved",
+	},
+	DebtUsage: {
+		RU: "Отправь долг в формате <code>/lend {сумма} {?валюта} {имя}</code>, <code>/borrow {сумма} {?валюта} {имя}</code> или <code>/repay {сумма} {?валюта} {имя}</code>",
+		EN: "Send debt in format <code>/lend {amount} {?currency} {name}</code>, <code>/borrow {amount} {?currency} {name}</code> or <code>/repay {amount} {?currency} {name}</code>",
+	},
+	DebtSaved: {
+		RU: "Долг сохранен, он не учитывается в расходах и доходах",
+		EN: "Debt saved, it is not counted in expenses and income",
+	},
+	DebtNotFound: {
+		RU: "Нет долгов с <b>%s</b> в %s",
+		EN: "There are no debts with <b>%s</b> in %s",
+	},
+	DebtRepayTooMuch: {
+		RU: "Долг с <b>%s</b> всего %s",
+		EN: "Debt with <b>%s</b> is only %s",
+	},
+	DebtOwesYou: {
+		RU: "➡️ <b>%s</b> должен тебе %s",
+		EN: "➡️ <b>%s</b> owes you %s",
+	},
+	DebtYouOwe: {
+		RU: "⬅️ Ты должен <b>%s</b> %s",
+		EN: "⬅️ You owe <b>%s</b> %s",
+	},
+	DebtSettled: {
+		RU: "🤝 Долгов с <b>%s</b> больше нет",
+		EN: "🤝 You and <b>%s</b> are settled up",
+	},
+	DebtsTitle: {
+		RU: "💸 Долги",
+		EN: "💸 Debts",
+	},
+	DebtsEmpty: {
+		RU: "Долгов нет",
+		EN: "No debts",
+	},
+	DebtsTotal: {
+		RU: "Тебе должны %s, ты должен %s",
+		EN: "You are owed %s, you owe %s",
+	},
+	SplitUsage: {
+		RU: "Отправь счет в формате <code>{сумма} {название} split with {участники} {?paid by {участник}}</code>, участник это <code>@имя</code>, <code>@имя:{доля}</code> или <code>@имя={сумма}</code>, себя обозначай как <code>me</code>, например <code>3000 ужин split with @a @b</code>",
+		EN: "Send bill in format <code>{amount} {name} split with {participants} {?paid by {participant}}</code>, participant is <code>@name</code>, <code>@name:{share}</code> or <code>@name={amount}</code>, refer to yourself as <code>me</code>, for example <code>3000 dinner split with @a @b</code>",
+	},
+	SplitSelf: {
+		RU: "ты",
+		EN: "you",
+	},
+	SplitSaved: {
+		RU: "👥 Счет <b>%s</b> оплатил %s, доли: %s",
+		EN: "👥 Bill <b>%s</b> paid by %s, shares: %s",
+	},
+	SettleUpTitle: {
+		RU: "👥 Чтобы рассчитаться по общим счетам",
+		EN: "👥 To settle up split bills",
+	},
+	SettleUpEmpty: {
+		RU: "По общим счетам никто никому не должен",
+		EN: "Nobody owes anything for split bills",
+	},
+	SettleUpTransfer: {
+		RU: "%s → %s: %s",
+		EN: "%s → %s: %s",
+	},
+	SettledUp: {
+		RU: "Все общие счета отмечены как оплаченные",
+		EN: "All split bills are marked as settled up",
+	},
+	Today: {
+		RU: "💡 Сегодня можно потратить <b>%s</b>\n\nРасчетный период %s – %s, осталось дней: %d\nОжидаемый доход: %s\nПредстоящие счета и подписки: %s\nПотрачено за период: %s, сегодня: %s",
+		EN: "💡 Safe to spend today: <b>%s</b>\n\nPay period %s – %s, days left: %d\nExpected income: %s\nUpcoming bills and subscriptions: %s\nSpent this period: %s, today: %s",
+	},
+	TodayFooter: {
+		RU: "💡 Сегодня можно потратить еще %s",
+		EN: "💡 Safe to spend today: %s",
+	},
+	TodayFooterOn: {
+		RU: "Теперь после каждого расхода я буду показывать сколько еще можно потратить сегодня",
+		EN: "I'll show how much is safe to spend today after each expense",
+	},
+	TodayFooterOff: {
+		RU: "Больше не буду показывать сколько можно потратить сегодня после каждого расхода",
+		EN: "I won't show how much is safe to spend today after each expense anymore",
+	},
+	PayDayUsage: {
+		RU: "Отправь день месяца когда начинается расчетный период, например <code>/set_payday 5</code>",
+		EN: "Send day of month when pay period starts, for example <code>/set_payday 5</code>",
+	},
+	PayDaySaved: {
+		RU: "Расчетный период теперь начинается %d числа",
+		EN: "Pay period now starts on day %d of month",
+	},
+	AccountAddUsage: {
+		RU: "Отправь название счета одним словом, валюту и начальный баланс, например <code>/add_account card USD 1500</code>",
+		EN: "Send account name as a single word, currency and opening balance, for example <code>/add_account card USD 1500</code>",
+	},
+	AccountAdded: {
+		RU: "Счет <b>%s</b> создан с балансом %s, укажи <code>@%s</code> в операции чтобы записать ее на этот счет",
+		EN: "Account <b>%s</b> created with balance %s, mention <code>@%s</code> in operation to record it to the account",
+	},
+	AccountExists: {
+		RU: "Счет <b>%s</b> уже существует",
+		EN: "Account <b>%s</b> already exists",
+	},
+	AccountNotFound: {
+		RU: "Счет <b>%s</b> не найден, посмотреть счета можно в /balances",
+		EN: "Account <b>%s</b> not found, see your accounts in /balances",
+	},
+	AccountCurrencyNotFound: {
+		RU: "Нет счета в валюте %s, создай его через /add_account",
+		EN: "No account in %s currency, create it with /add_account",
+	},
+	BalancesTitle: {
+		RU: "💰 Балансы счетов",
+		EN: "💰 Account balances",
+	},
+	BalanceItem: {
+		RU: "%s: <b>%s</b>",
+		EN: "%s: <b>%s</b>",
+	},
+	AccountDefaultSelection: {
+		RU: "Выбери счет по умолчанию для операций",
+		EN: "Choose default account for operations",
+	},
+	AccountDefaultSaved: {
+		RU: "Счет <b>%s</b> теперь используется по умолчанию",
+		EN: "Account <b>%s</b> is now default",
+	},
+	TransferUsage: {
+		RU: "Отправь сумму и два разных счета, например <code>/transfer 10000 card cash</code>, для счетов в разных валютах добавь сумму зачисления: <code>/transfer 100 usd card 9000</code>",
+		EN: "Send amount and two different accounts, for example <code>/transfer 10000 card cash</code>, for accounts in different currencies add received amount: <code>/transfer 100 usd card 9000</code>",
+	},
+	TransferToAmountRequired: {
+		RU: "Счета в разных валютах, укажи сумму зачисления в %s последним аргументом",
+		EN: "Accounts are in different currencies, provide received amount in %s as the last argument",
+	},
+	TransferSaved: {
+		RU: "🔄 Переведено <b>%s</b> со счета %s, зачислено <b>%s</b> на счет %s",
+		EN: "🔄 Transferred <b>%s</b> from %s, received <b>%s</b> to %s",
+	},
+	ExchangeUsage: {
+		RU: "Отправь проданную и купленную сумму с валютой или счетом, например <code>/exchange 92000 RUB 1000 USD</code> или <code>/exchange 92000 @card 1000 @dollars</code>",
+		EN: "Send sold and bought amounts with currency or account, for example <code>/exchange 92000 RUB 1000 USD</code> or <code>/exchange 92000 @card 1000 @dollars</code>",
+	},
+	ExchangeSaved: {
+		RU: "💱 Обменяно <b>%s</b> со счета %s на <b>%s</b> на счет %s\nКурс: 1 %s = %s %s",
+		EN: "💱 Exchanged <b>%s</b> from %s to <b>%s</b> to %s\nRate: 1 %s = %s %s",
+	},
+	ExchangeSpread: {
+		RU: "Курс ЦБ: %s %s, разница %s%% (%s)",
+		EN: "Reference rate: %s %s, spread %s%% (%s)",
+	},
+	ReconcileUsage: {
+		RU: "Отправь реальный баланс и счет, например <code>/reconcile 15000 @card</code>, без счета сверяется счет по умолчанию",
+		EN: "Send real balance and account, for example <code>/reconcile 15000 @card</code>, default account is reconciled if account is not mentioned",
+	},
+	ReconcileMatched: {
+		RU: "✅ Баланс счета %s сходится: <b>%s</b>",
+		EN: "✅ Balance of %s matches: <b>%s</b>",
+	},
+	ReconcileDiff: {
+		RU: "⚖️ Счет %s\nПо записям: %s\nНа самом деле: %s\nРасхождение: <b>%s</b>",
+		EN: "⚖️ Account %s\nRecorded: %s\nActual: %s\nDifference: <b>%s</b>",
+	},
+	AdjustmentSaved: {
+		RU: "Записана корректировка баланса <b>%s</b>, она не учитывается в доходах и расходах",
+		EN: "Balance adjustment <b>%s</b> is recorded, it is not counted in income and expenses",
+	},
+	AdjustmentExists: {
+		RU: "Корректировка для этой сверки уже записана",
+		EN: "Adjustment for this reconciliation is already recorded",
+	},
+	ReviewEmpty: {
+		RU: "С прошлой сверки не было операций по счету",
+		EN: "There were no operations of the account since the last reconciliation",
+	},
+	ReviewTitle: {
+		RU: "🔍 Операции с прошлой сверки, расхождение %s",
+		EN: "🔍 Operations since the last reconciliation, difference %s",
+	},
+	ReviewItem: {
+		RU: "%s %s <i>%s</i>",
+		EN: "%s %s <i>%s</i>",
+	},
+	ReportUsage: {
+		RU: "Отправь период: <code>week</code>, <code>month</code>, <code>year</code>, месяц <code>01.2024</code>, год <code>2024</code> или даты <code>01.01.2024-15.01.2024</code>",
+		EN: "Send period: <code>week</code>, <code>month</code>, <code>year</code>, month <code>01.2024</code>, year <code>2024</code> or dates <code>01.01.2024-15.01.2024</code>",
+	},
+	ReportEmpty: {
+		RU: "Нет операций за период %s – %s",
+		EN: "No operations for period %s – %s",
+	},
+	ReportTitle: {
+		RU: "📊 Отчет за %s – %s\n\nДоходы: <b>%s</b>\nРасходы: <b>%s</b>\nИтого: <b>%s</b>\n",
+		EN: "📊 Report for %s – %s\n\nIncome: <b>%s</b>\nExpenses: <b>%s</b>\nTotal: <b>%s</b>\n",
+	},
+	ReportCategory: {
+		RU: "%s: %s",
+		EN: "%s: %s",
+	},
+	ReportApproximate: {
+		RU: "≈ Для части операций нет курса на их дату, использован ближайший более ранний курс",
+		EN: "≈ Some operations have no exchange rate on their date, the nearest earlier rate was used",
+	},
+	ReportUnconverted: {
+		RU: "⚠️ Нет курса для валют %s, операции в них не учтены",
+		EN: "⚠️ No exchange rate for %s, operations in these currencies are not included",
+	},
+	NetWorthTitle: {
+		RU: "🏦 Капитал: <b>%s</b>",
+		EN: "🏦 Net worth: <b>%s</b>",
+	},
+	NetWorthChange: {
+		RU: "За %d мес.: %s",
+		EN: "%d mo. change: %s",
+	},
+	ReportDrift: {
+		RU: "⚖️ Расхождение по сверкам: %s (сверок: %d)",
+		EN: "⚖️ Reconciliation drift: %s (reconciliations: %d)",
+	},
+	ReportAssets: {
+		RU: "🪙 Активы на конец периода:",
+		EN: "🪙 Assets at the end of period:",
+	},
+	ReportAsset: {
+		RU: "%s ≈ %s",
+		EN: "%s ≈ %s",
+	},
+	AssetAddUsage: {
+		RU: "Отправь тикер актива и число знаков после запятой, например <code>/add_asset BTC 8</code>, тикер до 10 латинских букв или цифр, знаков от 0 до 18",
+		EN: "Send asset ticker and number of decimal places, for example <code>/add_asset BTC 8</code>, ticker is up to 10 latin letters or digits, decimal places are from 0 to 18",
+	},
+	AssetAdded: {
+		RU: "Актив <b>%s</b> добавлен, записывай покупки командой <code>/asset {количество} %s</code> и продажи с минусом",
+		EN: "Asset <b>%s</b> is added, record purchases with <code>/asset {amount} %s</code> and sales with minus sign",
+	},
+	AssetExists: {
+		RU: "Актив <b>%s</b> уже добавлен",
+		EN: "Asset <b>%s</b> is already added",
+	},
+	AssetUsage: {
+		RU: "Отправь количество и тикер актива, например <code>/asset 0.015 BTC</code> для покупки или <code>/asset -0.01 BTC</code> для продажи. Количество меняет только актив, оплату запиши отдельной операцией",
+		EN: "Send amount and asset ticker, for example <code>/asset 0.015 BTC</code> for purchase or <code>/asset -0.01 BTC</code> for sale. Only asset amount is changed, record the payment as a separate operation",
+	},
+	AssetNotFound: {
+		RU: "Актив <b>%s</b> не найден, добавь его командой <code>/add_asset</code>",
+		EN: "Asset <b>%s</b> is not found, add it with <code>/add_asset</code>",
+	},
+	AssetSaved: {
+		RU: "🪙 Записано <b>%s</b>, всего: %s",
+		EN: "🪙 Recorded <b>%s</b>, total: %s",
+	},
+	AssetNotEnough: {
+		RU: "Нельзя продать больше чем есть, сейчас у тебя %s",
+		EN: "Cannot sell more than you have, you have %s",
+	},
+	AssetsEmpty: {
+		RU: "Активов пока нет, добавь их командой <code>/add_asset</code>",
+		EN: "There are no assets yet, add them with <code>/add_asset</code>",
+	},
+	AssetsTitle: {
+		RU: "🪙 Активы",
+		EN: "🪙 Assets",
+	},
+	AssetItem: {
+		RU: "%s ≈ <b>%s</b>",
+		EN: "%s ≈ <b>%s</b>",
+	},
+	AssetItemNoRate: {
+		RU: "%s, нет курса, задай его командой <code>/asset_rate %s {цена}</code>",
+		EN: "%s, no rate, set it with <code>/asset_rate %s {price}</code>",
+	},
+	AssetRateUsage: {
+		RU: "Отправь цену актива и валюту, например <code>/asset_rate BTC 65000 USD</code>, без валюты используется валюта по умолчанию",
+		EN: "Send asset price and currency, for example <code>/asset_rate BTC 65000 USD</code>, default currency is used if currency is omitted",
+	},
+	AssetRateSaved: {
+		RU: "Цена <b>%s</b> на сегодня: %s",
+		EN: "<b>%s</b> price for today: %s",
+	},
+	ExportUsage: {
+		RU: "Отправь формат и период, например <code>/export csv month</code>, формат <code>csv</code> (по умолчанию), <code>qif</code>, <code>ledger</code> (ledger и hledger) или <code>beancount</code>, без периода выгружаются все операции. Период: <code>week</code>, <code>month</code>, <code>year</code>, месяц <code>01.2024</code>, год <code>2024</code> или даты <code>01.01.2024-15.01.2024</code>",
+		EN: "Send format and period, for example <code>/export csv month</code>, format is <code>csv</code> (default), <code>qif</code>, <code>ledger</code> (ledger and hledger) or <code>beancount</code>, all operations are exported without period. Period is <code>week</code>, <code>month</code>, <code>year</code>, month <code>01.2024</code>, year <code>2024</code> or dates <code>01.01.2024-15.01.2024</code>",
+	},
+	ExportCaption: {
+		RU: "📤 Операции за %s – %s",
+		EN: "📤 Operations for %s – %s",
+	},
+	ExportCaptionAll: {
+		RU: "📤 Все операции",
+		EN: "📤 All operations",
+	},
+	ImportUnsupportedFile: {
+		RU: "Отправь CSV, OFX, QFX, QIF, camt.053 XML или MT940 файл чтобы импортировать операции",
+		EN: "Send CSV, OFX, QFX, QIF, camt.053 XML or MT940 file to import operations",
+	},
+	ImportTooLarge: {
+		RU: "Файл слишком большой, максимальный размер %d МБ",
+		EN: "File is too large, max size is %d MB",
+	},
+	ImportInvalidFile: {
+		RU: "Не удалось прочитать файл, проверь что это CSV",
+		EN: "File cannot be read, make sure it is CSV",
+	},
+	ImportEmpty: {
+		RU: "В файле нет операций",
+		EN: "There are no operations in the file",
+	},
+	ImportRead: {
+		RU: "📥 Прочитано строк: %d, кодировка %s, разделитель %s",
+		EN: "📥 Rows read: %d, encoding %s, delimiter %s",
+	},
+	ImportColumnDate: {
+		RU: "Выбери колонку с <b>датой</b> операции",
+		EN: "Choose column with operation <b>date</b>",
+	},
+	ImportColumnAmount: {
+		RU: "Выбери колонку с <b>суммой</b>, расходы должны быть со знаком минус",
+		EN: "Choose column with <b>amount</b>, expenses must be negative",
+	},
+	ImportColumnName: {
+		RU: "Выбери колонку с <b>названием</b> операции",
+		EN: "Choose column with operation <b>name</b>",
+	},
+	ImportColumnCategory: {
+		RU: "Выбери колонку с <b>категорией</b>, без нее операции попадут в категорию «Другое»",
+		EN: "Choose column with <b>category</b>, operations without it are saved to «Other» category",
+	},
+	ImportColumnCurrency: {
+		RU: "Выбери колонку с <b>валютой</b>, без нее используется %s",
+		EN: "Choose column with <b>currency</b>, %s is used without it",
+	},
+	ImportPreview: {
+		RU: "📥 Операций к импорту: %d, первые из них:",
+		EN: "📥 Operations to import: %d, the first ones:",
+	},
+	ImportPreviewItem: {
+		RU: "%s %s <i>%s</i> %s",
+		EN: "%s %s <i>%s</i> %s",
+	},
+	ImportRowErrors: {
+		RU: "⚠️ Строк пропущено: %d",
+		EN: "⚠️ Rows skipped: %d",
+	},
+	ImportRowError: {
+		RU: "строка %d: %s",
+		EN: "row %d: %s",
+	},
+	ImportMissingCategories: {
+		RU: "Категории не найдены: %s",
+		EN: "Categories not found: %s",
+	},
+	ImportDuplicates: {
+		RU: "♻️ Уже импортировано и будет пропущено: %d",
+		EN: "♻️ Already imported and will be skipped: %d",
+	},
+	ImportDuplicatesFound: {
+		RU: "♊ Похожие операции уже записаны, возможно это дубликаты: %d",
+		EN: "♊ Similar operations are already recorded, these may be duplicates: %d",
+	},
+	ImportDuplicateItem: {
+		RU: "%s %s <i>%s</i> ≈ %s <i>%s</i>",
+		EN: "%s %s <i>%s</i> ≈ %s <i>%s</i>",
+	},
+	ImportNothing: {
+		RU: "Ни одну операцию не удалось импортировать",
+		EN: "No operation can be imported",
+	},
+	ImportDone: {
+		RU: "✅ Импортировано операций: %d, пропущено уже импортированных: %d, создано категорий: %d",
+		EN: "✅ Operations imported: %d, already imported skipped: %d, categories created: %d",
+	},
+
+	// Backup
+	BackupCaption: {
+		RU: "💾 Бэкап: категорий %d, счетов %d, операций %d, ключевых слов %d. Отправь этот файл боту чтобы восстановить данные",
+		EN: "💾 Backup: %d categories, %d accounts, %d operations, %d keywords. Send this file to the bot to restore data",
+	},
+	RestoreInvalidFile: {
+		RU: "Не удалось прочитать бэкап, отправь файл полученный командой /backup",
+		EN: "Backup cannot be read, send file received by /backup command",
+	},
+	RestoreVersion: {
+		RU: "Версия бэкапа %d не поддерживается, поддерживается версия %d",
+		EN: "Backup version %d is not supported, supported version is %d",
+	},
+	RestorePreview: {
+		RU: "💾 Бэкап от %s: категорий %d, счетов %d, операций %d, ключевых слов %d\n\nНастройки будут заменены настройками из бэкапа, текущие данные сохранятся, уже восстановленные записи будут пропущены",
+		EN: "💾 Backup of %s: %d categories, %d accounts, %d operations, %d keywords\n\nSettings will be replaced with settings of backup, current data is kept, already restored records are skipped",
+	},
+	RestoreDone: {
+		RU: "✅ Восстановлено категорий: %d, счетов: %d, операций: %d, ключевых слов: %d",
+		EN: "✅ Restored categories: %d, accounts: %d, operations: %d, keywords: %d",
+	},
+
+	// Duplicates
+	DuplicateWarning: {
+		RU: "♊ Похоже на дубликат операции %s %s <i>%s</i>",
+		EN: "♊ Looks like duplicate of operation %s %s <i>%s</i>",
+	},
+	DuplicateKept: {
+		RU: "✅ Обе операции сохранены",
+		EN: "✅ Both operations are kept",
+	},
+	DuplicateDiscarded: {
+		RU: "🗑 Дубликат удален: %s %s <i>%s</i>",
+		EN: "🗑 Duplicate discarded: %s %s <i>%s</i>",
+	},
+	DuplicateNotFound: {
+		RU: "Операция не найдена, возможно она уже удалена",
+		EN: "Operation not found, it may be already deleted",
+	},
+	DuplicatesUsage: {
+		RU: "Отправь период, например <code>/duplicates month</code>, без периода проверяются все операции. Период: <code>week</code>, <code>month</code>, <code>year</code>, месяц <code>01.2024</code>, год <code>2024</code> или даты <code>01.01.2024-15.01.2024</code>",
+		EN: "Send period, for example <code>/duplicates month</code>, all operations are checked without period. Period is <code>week</code>, <code>month</code>, <code>year</code>, month <code>01.2024</code>, year <code>2024</code> or dates <code>01.01.2024-15.01.2024</code>",
+	},
+	DuplicatesTitle: {
+		RU: "♊ Возможных дубликатов: %d",
+		EN: "♊ Likely duplicates: %d",
+	},
+	DuplicatesItem: {
+		RU: "%d. %s %s <i>%s</i>\n    %s %s <i>%s</i>",
+		EN: "%d. %s %s <i>%s</i>\n    %s %s <i>%s</i>",
+	},
+	DuplicatesMore: {
+		RU: "Показаны первые %d, удали или оставь их чтобы увидеть остальные",
+		EN: "The first %d are shown, discard or keep them to see the rest",
+	},
+	DuplicatesEmpty: {
+		RU: "Дубликатов не найдено",
+		EN: "No duplicates found",
+	},
 
 	// Logic errors
 	InvalidCurr: {
@@ -191,6 +870,74 @@ var messages = map[ID]Message{
 		RU: "📉 Расходы",
 		EN: "📉 Expenses",
 	},
+	BtnTrackSubscription: {
+		RU: "➕ Отслеживать %s",
+		EN: "➕ Track %s",
+	},
+	BtnDaysBefore: {
+		RU: "%d дн.",
+		EN: "%d d.",
+	},
+	BtnDelete: {
+		RU: "🗑 %s",
+		EN: "🗑 %s",
+	},
+	BtnBillPaid: {
+		RU: "✅ Оплачено",
+		EN: "✅ Paid",
+	},
+	BtnBillSave: {
+		RU: "💾 Сохранить %s",
+		EN: "💾 Save %s",
+	},
+	BtnSettled: {
+		RU: "🤝 Все рассчитались",
+		EN: "🤝 Everyone is settled up",
+	},
+	BtnPostAdjustment: {
+		RU: "✏️ Записать корректировку",
+		EN: "✏️ Post adjustment",
+	},
+	BtnReviewOperations: {
+		RU: "🔍 Показать операции",
+		EN: "🔍 Review operations",
+	},
+	BtnSkip: {
+		RU: "Пропустить",
+		EN: "Skip",
+	},
+	BtnImport: {
+		RU: "📥 Импортировать",
+		EN: "📥 Import",
+	},
+	BtnImportCreateCategories: {
+		RU: "📥 Создать категории и импортировать",
+		EN: "📥 Create categories and import",
+	},
+	BtnImportWithoutCategories: {
+		RU: "📥 Импортировать в «Другое»",
+		EN: "📥 Import to «Other»",
+	},
+	BtnRestore: {
+		RU: "💾 Восстановить",
+		EN: "💾 Restore",
+	},
+	BtnKeepBoth: {
+		RU: "✅ Оставить обе",
+		EN: "✅ Keep both",
+	},
+	BtnDiscard: {
+		RU: "🗑 Удалить дубликат",
+		EN: "🗑 Discard",
+	},
+	BtnKeepN: {
+		RU: "✅ Оставить %d",
+		EN: "✅ Keep %d",
+	},
+	BtnDiscardN: {
+		RU: "🗑 Удалить %d",
+		EN: "🗑 Discard %d",
+	},
 }
 
 func Get(id ID, lang string) string {
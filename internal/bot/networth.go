@@ -0,0 +1,100 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/ysomad/financer/internal/bot/msg"
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+)
+
+const (
+	chartWidth  = 10
+	monthLayout = "01.2006"
+)
+
+// netWorthChanges is number of months net worth change is shown for.
+var netWorthChanges = [...]int{1, 6, 12}
+
+func (b *Bot) netWorth(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	nw, err := b.networth.Calc(stdContext(c), usr, time.Now())
+	if err != nil {
+		return fmt.Errorf("net worth not calculated: %w", err)
+	}
+
+	cur := nw.Current()
+
+	sb := strings.Builder{}
+	sb.WriteString(msg.Getf(msg.NetWorthTitle, usr.Language, msg.Money(cur.Money, nw.Currency, usr.Language)))
+	sb.WriteString("\n")
+
+	for _, months := range netWorthChanges {
+		change, ok := nw.Change(months)
+		if !ok {
+			continue
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(msg.Getf(msg.NetWorthChange, usr.Language, months, formatChange(change, nw.Currency, usr.Language)))
+	}
+
+	sb.WriteString("\n\n<pre>")
+	sb.WriteString(netWorthChart(nw.History, nw.Currency, usr.Language))
+	sb.WriteString("</pre>")
+
+	for _, s := range nw.History {
+		if s.Approximate {
+			sb.WriteString("\n")
+			sb.WriteString(msg.Get(msg.ReportApproximate, usr.Language))
+
+			break
+		}
+	}
+
+	return c.Send(sb.String())
+}
+
+// netWorthChart renders horizontal bar chart of monthly net worth, negative values are drawn with light bars.
+func netWorthChart(history []domain.NetWorthSnapshot, currency, lang string) string {
+	var maxAbs money.Money
+
+	for _, s := range history {
+		maxAbs = max(maxAbs, s.Money.Abs())
+	}
+
+	sb := strings.Builder{}
+
+	for _, s := range history {
+		bars := 0
+		if maxAbs > 0 {
+			bars = int(int64(s.Money.Abs()) * chartWidth / int64(maxAbs))
+		}
+
+		bar := "█"
+		if s.Money < 0 {
+			bar = "░"
+		}
+
+		fmt.Fprintf(&sb, "%s %-*s %s\n", s.Month.Format(monthLayout), chartWidth,
+			strings.Repeat(bar, bars), msg.Money(s.Money, currency, lang))
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func formatChange(m money.Money, currency, lang string) string {
+	if m > 0 {
+		return "+" + msg.Money(m, currency, lang)
+	}
+
+	return msg.Money(m, currency, lang)
+}
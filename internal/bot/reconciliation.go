@@ -0,0 +1,168 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/ysomad/financer/internal/bot/msg"
+	botstate "github.com/ysomad/financer/internal/bot/state"
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+	"github.com/ysomad/financer/internal/postgres"
+)
+
+const reviewLimit = 50
+
+// reconcile compares real balance of account from command payload in format {amount} {?@account}
+// with balance calculated from operations, default account is used if account is not mentioned.
+func (b *Bot) reconcile(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	args := c.Args()
+	if len(args) != 1 && len(args) != 2 {
+		return c.Send(msg.Get(msg.ReconcileUsage, usr.Language))
+	}
+
+	var accountName string
+
+	if len(args) == 2 {
+		accountName = strings.TrimPrefix(args[1], "@")
+	}
+
+	ctx := stdContext(c)
+
+	acc, err := b.resolveAccount(ctx, usr.ID, accountName, "")
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return c.Send(msg.Getf(msg.AccountNotFound, usr.Language, accountName))
+		}
+
+		return fmt.Errorf("account not found: %w", err)
+	}
+
+	actual, err := money.Parse(args[0], acc.Currency)
+	if err != nil {
+		return c.Send(msg.Get(msg.ReconcileUsage, usr.Language))
+	}
+
+	balances, err := b.account.ListBalances(ctx, usr.ID)
+	if err != nil {
+		return fmt.Errorf("balances not listed: %w", err)
+	}
+
+	rec := domain.Reconciliation{
+		ID:        uuid.NewString(),
+		UID:       usr.ID,
+		AccountID: acc.ID,
+		Currency:  acc.Currency,
+		Actual:    actual,
+		CreatedAt: time.Now(),
+	}
+
+	for _, ab := range balances {
+		if ab.ID == acc.ID {
+			rec.Recorded = ab.Balance
+		}
+	}
+
+	if err := b.reconciliation.Save(ctx, rec); err != nil {
+		return fmt.Errorf("reconciliation not saved: %w", err)
+	}
+
+	diff := rec.Difference()
+	if diff == 0 {
+		return c.Send(msg.Getf(msg.ReconcileMatched, usr.Language, acc.Name, msg.Money(actual, acc.Currency, usr.Language)))
+	}
+
+	kb := &tele.ReplyMarkup{}
+	kb.Inline(
+		kb.Row(kb.Data(msg.Get(msg.BtnPostAdjustment, usr.Language), botstate.StepReconcileAdjust.String(), rec.ID)),
+		kb.Row(kb.Data(msg.Get(msg.BtnReviewOperations, usr.Language), botstate.StepReconcileReview.String(), rec.ID)),
+		kb.Row(btnCancel(kb, usr.Language)),
+	)
+
+	return c.Send(msg.Getf(msg.ReconcileDiff, usr.Language,
+		acc.Name, msg.Money(rec.Recorded, acc.Currency, usr.Language), msg.Money(actual, acc.Currency, usr.Language), msg.Money(diff, acc.Currency, usr.Language)), kb)
+}
+
+func (b *Bot) postAdjustment(c tele.Context, usr domain.User, recID string) error {
+	ctx := stdContext(c)
+
+	rec, err := b.reconciliation.FindByID(ctx, usr.ID, recID)
+	if err != nil {
+		return fmt.Errorf("reconciliation not found: %w", err)
+	}
+
+	if err := b.reconciliation.SaveAdjustment(ctx, rec, uuid.NewString(), time.Now()); err != nil {
+		if errors.Is(err, postgres.ErrAlreadyExists) {
+			return c.Edit(msg.Get(msg.AdjustmentExists, usr.Language))
+		}
+
+		return fmt.Errorf("adjustment not saved: %w", err)
+	}
+
+	return c.Edit(msg.Getf(msg.AdjustmentSaved, usr.Language, msg.Money(rec.Difference(), rec.Currency, usr.Language)))
+}
+
+// reviewOperations lists operations of reconciled account since previous reconciliation.
+func (b *Bot) reviewOperations(c tele.Context, usr domain.User, recID string) error {
+	ctx := stdContext(c)
+
+	rec, err := b.reconciliation.FindByID(ctx, usr.ID, recID)
+	if err != nil {
+		return fmt.Errorf("reconciliation not found: %w", err)
+	}
+
+	var since time.Time
+
+	prev, err := b.reconciliation.FindLastBefore(ctx, usr.ID, rec.AccountID, rec.CreatedAt)
+	switch {
+	case err == nil:
+		since = prev.CreatedAt
+	case !errors.Is(err, postgres.ErrNotFound):
+		return fmt.Errorf("previous reconciliation not found: %w", err)
+	}
+
+	ops, err := b.operation.ListByAccountID(ctx, usr.ID, rec.AccountID, since)
+	if err != nil {
+		return fmt.Errorf("operations not listed: %w", err)
+	}
+
+	ops = slices.DeleteFunc(ops, func(op domain.Operation) bool { return !op.CreatedAt.Before(rec.CreatedAt) })
+
+	// the latest operations are shown to fit into message
+	if len(ops) > reviewLimit {
+		ops = ops[len(ops)-reviewLimit:]
+	}
+
+	if len(ops) == 0 {
+		return c.Edit(msg.Get(msg.ReviewEmpty, usr.Language))
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(msg.Getf(msg.ReviewTitle, usr.Language, msg.Money(rec.Difference(), rec.Currency, usr.Language)))
+	sb.WriteString("\n")
+
+	for _, op := range ops {
+		sb.WriteString("\n")
+		sb.WriteString(msg.Getf(msg.ReviewItem, usr.Language,
+			op.OccuredAt.Format(dateLayout), msg.Money(op.Money, op.Currency, usr.Language), op.Name))
+	}
+
+	kb := &tele.ReplyMarkup{}
+	kb.Inline(
+		kb.Row(kb.Data(msg.Get(msg.BtnPostAdjustment, usr.Language), botstate.StepReconcileAdjust.String(), rec.ID)),
+		kb.Row(btnCancel(kb, usr.Language)),
+	)
+
+	return c.Edit(sb.String(), kb)
+}
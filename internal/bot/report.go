@@ -0,0 +1,97 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/ysomad/financer/internal/bot/msg"
+	"github.com/ysomad/financer/internal/date"
+	"github.com/ysomad/financer/internal/domain"
+)
+
+// sendReport sends income and expenses by category for period from command payload,
+// see date.ParsePeriod for supported formats.
+func (b *Bot) sendReport(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	from, to, err := date.ParsePeriod(strings.Join(c.Args(), ""), time.Now())
+	if err != nil {
+		return c.Send(msg.Get(msg.ReportUsage, usr.Language))
+	}
+
+	ctx := stdContext(c)
+
+	r, err := b.report.Build(ctx, usr, from, to)
+	if err != nil {
+		return fmt.Errorf("report not built: %w", err)
+	}
+
+	if len(r.Categories) == 0 && len(r.Unconverted) == 0 && r.Reconciliations == 0 && len(r.Assets) == 0 {
+		return c.Send(msg.Getf(msg.ReportEmpty, usr.Language, from.Format(dateLayout), to.AddDate(0, 0, -1).Format(dateLayout)))
+	}
+
+	cats, err := b.category.ListByUserID(ctx, usr.ID, domain.CatTypeUnspecified)
+	if err != nil {
+		return fmt.Errorf("categories not listed: %w", err)
+	}
+
+	names := make(map[string]string, len(cats))
+	for _, cat := range cats {
+		names[cat.ID] = cat.Name
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(msg.Getf(msg.ReportTitle, usr.Language,
+		r.From.Format(dateLayout), r.To.AddDate(0, 0, -1).Format(dateLayout),
+		msg.Money(r.Income, r.Currency, usr.Language), msg.Money(r.Expenses.Abs(), r.Currency, usr.Language),
+		msg.Money(r.Income+r.Expenses, r.Currency, usr.Language)))
+	sb.WriteString("\n")
+
+	for _, ct := range r.Categories {
+		name, ok := names[ct.CatID]
+		if !ok {
+			// category was renamed or deleted after operation was saved
+			cat, err := b.category.FindByID(ctx, ct.CatID)
+			if err == nil {
+				name = cat.Name
+			}
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(msg.Getf(msg.ReportCategory, usr.Language, name, msg.Money(ct.Money, r.Currency, usr.Language)))
+	}
+
+	if len(r.Assets) > 0 {
+		sb.WriteString("\n\n")
+		sb.WriteString(msg.Get(msg.ReportAssets, usr.Language))
+
+		for _, a := range r.Assets {
+			sb.WriteString("\n")
+			sb.WriteString(msg.Getf(msg.ReportAsset, usr.Language,
+				msg.Decimal(a.Amount, a.Ticker, domain.AssetDisplayDecimals, usr.Language), msg.Money(a.Money, r.Currency, usr.Language)))
+		}
+	}
+
+	if r.Reconciliations > 0 {
+		sb.WriteString("\n\n")
+		sb.WriteString(msg.Getf(msg.ReportDrift, usr.Language, msg.Money(r.Drift, r.Currency, usr.Language), r.Reconciliations))
+	}
+
+	if r.Approximate {
+		sb.WriteString("\n\n")
+		sb.WriteString(msg.Get(msg.ReportApproximate, usr.Language))
+	}
+
+	if len(r.Unconverted) > 0 {
+		sb.WriteString("\n\n")
+		sb.WriteString(msg.Getf(msg.ReportUnconverted, usr.Language, strings.Join(r.Unconverted, ", ")))
+	}
+
+	return c.Send(sb.String())
+}
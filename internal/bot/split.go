@@ -0,0 +1,215 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/ysomad/financer/internal/bot/msg"
+	botstate "github.com/ysomad/financer/internal/bot/state"
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+)
+
+const splitSelfName = "me"
+
+var errInvalidSplit = errors.New("invalid split format")
+
+// splitIndex returns index of "split with" in operation text parts or -1 if operation is not a split.
+func splitIndex(parts []string) int {
+	for i := 2; i < len(parts)-1; i++ {
+		if strings.EqualFold(parts[i], "split") && strings.EqualFold(parts[i+1], "with") {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// parseSplit parses split from operation text in format
+// {amount} {name} split with {participants...} {?paid by {participant}}.
+// Participant is @name, @name:{weight} or @name={exact amount}, user is referred as "me"
+// and takes equal share of the rest if not mentioned.
+func parseSplit(parts []string, at int, currency string) (domain.Split, error) {
+	total, err := money.Parse(strings.TrimPrefix(parts[0], "-"), currency)
+	if err != nil || total <= 0 {
+		return domain.Split{}, errInvalidSplit
+	}
+
+	split := domain.Split{
+		Name:     strings.Join(parts[1:at], " "),
+		Money:    total,
+		Currency: currency,
+		Payer:    domain.SplitSelf,
+	}
+
+	args := parts[at+2:]
+
+	if n := len(args); n > 2 && strings.EqualFold(args[n-3], "paid") && strings.EqualFold(args[n-2], "by") {
+		split.Payer = parseParticipant(args[n-1])
+		args = args[:n-3]
+	}
+
+	self := false
+
+	for _, arg := range args {
+		if arg == "" {
+			continue
+		}
+
+		share, err := parseShare(arg, currency)
+		if err != nil {
+			return domain.Split{}, err
+		}
+
+		if share.Participant == domain.SplitSelf {
+			self = true
+		}
+
+		split.Shares = append(split.Shares, share)
+	}
+
+	if !self {
+		split.Shares = append([]domain.SplitShare{{Participant: domain.SplitSelf, Weight: 1}}, split.Shares...)
+	}
+
+	return split, nil
+}
+
+func parseShare(s, currency string) (domain.SplitShare, error) {
+	if name, amount, ok := strings.Cut(s, "="); ok {
+		m, err := money.Parse(amount, currency)
+		if err != nil || m < 0 {
+			return domain.SplitShare{}, errInvalidSplit
+		}
+
+		return domain.SplitShare{Participant: parseParticipant(name), Exact: true, Money: m}, nil
+	}
+
+	if name, weight, ok := strings.Cut(s, ":"); ok {
+		w, err := strconv.ParseInt(weight, 10, 64)
+		if err != nil || w <= 0 {
+			return domain.SplitShare{}, errInvalidSplit
+		}
+
+		return domain.SplitShare{Participant: parseParticipant(name), Weight: w}, nil
+	}
+
+	return domain.SplitShare{Participant: parseParticipant(s), Weight: 1}, nil
+}
+
+func parseParticipant(s string) string {
+	if strings.EqualFold(s, splitSelfName) {
+		return domain.SplitSelf
+	}
+
+	return s
+}
+
+func formatParticipant(lang, p string) string {
+	if p == domain.SplitSelf {
+		return msg.Get(msg.SplitSelf, lang)
+	}
+
+	return p
+}
+
+// handleSplit saves split bill, own share of the user is saved as an expense.
+func (b *Bot) handleSplit(c tele.Context, usr domain.User, acc domain.Account, parts []string, at int) error {
+	split, err := parseSplit(parts, at, acc.Currency)
+	if err != nil {
+		return c.Send(msg.Get(msg.SplitUsage, usr.Language))
+	}
+
+	split.ID = uuid.NewString()
+	split.UID = usr.ID
+	split.OccuredAt = time.Now()
+
+	if err := split.Allocate(); err != nil {
+		return c.Send(msg.Get(msg.SplitUsage, usr.Language))
+	}
+
+	own := split.OwnShare()
+	if own == 0 {
+		footer, err := b.saveSplit(stdContext(c), usr, split, "")
+		if err != nil {
+			return err
+		}
+
+		return c.Send(strings.TrimSpace(footer))
+	}
+
+	return b.recordOperation(c, usr, operation{
+		name:      split.Name,
+		money:     -own,
+		account:   acc,
+		occuredAt: split.OccuredAt,
+		split:     &split,
+	})
+}
+
+// saveSplit saves split linked to operation and returns split summary.
+func (b *Bot) saveSplit(ctx context.Context, usr domain.User, split domain.Split, opID string) (string, error) {
+	split.OperationID = opID
+
+	if err := b.split.Save(ctx, split, time.Now()); err != nil {
+		return "", fmt.Errorf("split not saved: %w", err)
+	}
+
+	shares := make([]string, len(split.Shares))
+	for i, share := range split.Shares {
+		shares[i] = formatParticipant(usr.Language, share.Participant) + " " + msg.Money(share.Money, split.Currency, usr.Language)
+	}
+
+	return "\n\n" + msg.Getf(msg.SplitSaved, usr.Language, msg.Money(split.Money, split.Currency, usr.Language),
+		formatParticipant(usr.Language, split.Payer), strings.Join(shares, ", ")), nil
+}
+
+func (b *Bot) settleUp(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	splits, err := b.split.ListUnsettled(stdContext(c), usr.ID)
+	if err != nil {
+		return fmt.Errorf("splits not listed: %w", err)
+	}
+
+	transfers := domain.SettleUp(splits)
+	if len(transfers) == 0 {
+		return c.Send(msg.Get(msg.SettleUpEmpty, usr.Language))
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(msg.Get(msg.SettleUpTitle, usr.Language))
+	sb.WriteString("\n")
+
+	for _, t := range transfers {
+		sb.WriteString("\n")
+		sb.WriteString(msg.Getf(msg.SettleUpTransfer, usr.Language,
+			formatParticipant(usr.Language, t.From), formatParticipant(usr.Language, t.To), msg.Money(t.Money, t.Currency, usr.Language)))
+	}
+
+	kb := &tele.ReplyMarkup{}
+	kb.Inline(
+		kb.Row(kb.Data(msg.Get(msg.BtnSettled, usr.Language), botstate.StepSplitSettle.String())),
+		kb.Row(btnCancel(kb, usr.Language)),
+	)
+
+	return c.Send(sb.String(), kb)
+}
+
+func (b *Bot) markSettled(c tele.Context, usr domain.User) error {
+	if err := b.split.SettleAll(stdContext(c), usr.ID, time.Now()); err != nil {
+		return fmt.Errorf("splits not settled: %w", err)
+	}
+
+	return c.Edit(msg.Get(msg.SettledUp, usr.Language))
+}
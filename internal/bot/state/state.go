@@ -25,6 +25,38 @@ const (
 	// Category add
 	StepCatAddTypeSelection Step = "category_add_type_selection"
 	StepCatAdd              Step = "category_step_add"
+
+	// Subscriptions
+	StepSubscriptionTrack Step = "subscription_track"
+
+	// Bills
+	StepBillRemindDays Step = "bill_remind_days"
+	StepBillDelete     Step = "bill_delete"
+	StepBillPaid       Step = "bill_paid"
+	StepBillAmount     Step = "bill_amount"
+	StepBillSave       Step = "bill_save"
+
+	// Splits
+	StepSplitSettle Step = "split_settle"
+
+	// Accounts
+	StepAccountDefault Step = "account_default"
+
+	// Reconciliation
+	StepReconcileAdjust Step = "reconcile_adjust"
+	StepReconcileReview Step = "reconcile_review"
+
+	// Import
+	StepImportColumn     Step = "import_column"
+	StepImportDuplicates Step = "import_duplicates"
+	StepImportConfirm    Step = "import_confirm"
+
+	// Duplicates
+	StepDuplicateKeep    Step = "duplicate_keep"
+	StepDuplicateDiscard Step = "duplicate_discard"
+
+	// Backup
+	StepRestoreConfirm Step = "restore_confirm"
 )
 
 func (s Step) String() string {
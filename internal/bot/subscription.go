@@ -0,0 +1,195 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	tele "gopkg.in/telebot.v3"
+
+	"github.com/ysomad/financer/internal/bot/msg"
+	botstate "github.com/ysomad/financer/internal/bot/state"
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/postgres"
+)
+
+const dateLayout = "02.01.2006"
+
+func (b *Bot) listSubscriptions(c tele.Context) error {
+	usr, ok := userFromContext(c)
+	if !ok {
+		return errUserNotInContext
+	}
+
+	ctx := stdContext(c)
+	now := time.Now()
+
+	// yearly subscriptions need at least 3 charges
+	ops, err := b.operation.ListByUserID(ctx, usr.ID, now.AddDate(-2, -1, 0), now.AddDate(0, 0, 1))
+	if err != nil {
+		return fmt.Errorf("operations not listed: %w", err)
+	}
+
+	tracked, err := b.subscription.ListByUserID(ctx, usr.ID)
+	if err != nil {
+		return fmt.Errorf("subscriptions not listed: %w", err)
+	}
+
+	subs := mergeSubscriptions(tracked, domain.DetectSubscriptions(ops, now))
+	if len(subs) == 0 {
+		return c.Send(msg.Get(msg.SubscriptionsEmpty, usr.Language))
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(msg.Get(msg.SubscriptionsTitle, usr.Language))
+
+	kb := &tele.ReplyMarkup{}
+	rows := make([]tele.Row, 0, len(subs)+1)
+	step := botstate.StepSubscriptionTrack
+
+	for i, sub := range subs {
+		mark := "🔍"
+		if sub.Tracked {
+			mark = "✅"
+		}
+
+		sb.WriteString("\n\n")
+		sb.WriteString(msg.Getf(msg.SubscriptionItem, usr.Language,
+			mark, sub.Name, msg.Money(-sub.Money, sub.Currency, usr.Language), sub.Interval,
+			msg.Money(sub.MonthlyCost(), sub.Currency, usr.Language), msg.Money(sub.YearlyCost(), sub.Currency, usr.Language),
+			sub.NextAt.Format(dateLayout)))
+
+		if sub.PriceIncreased() {
+			sb.WriteString("\n")
+			sb.WriteString(msg.Getf(msg.SubscriptionPriceUp, usr.Language,
+				msg.Money(-sub.PrevMoney, sub.Currency, usr.Language), msg.Money(-sub.Money, sub.Currency, usr.Language)))
+		}
+
+		if !sub.Tracked {
+			text := msg.Getf(msg.BtnTrackSubscription, usr.Language, sub.Name)
+			rows = append(rows, kb.Row(kb.Data(text, step.String(), strconv.Itoa(i))))
+		}
+	}
+
+	if len(rows) == 0 {
+		return c.Send(sb.String())
+	}
+
+	rows = append(rows, kb.Row(btnCancel(kb, usr.Language)))
+	kb.Inline(rows...)
+
+	b.state.Add(usr.IDString(), botstate.State{Step: step, Data: subs})
+
+	return c.Send(sb.String(), kb)
+}
+
+// mergeSubscriptions returns tracked subscriptions followed by detected ones which are not tracked yet.
+func mergeSubscriptions(tracked, detected []domain.Subscription) []domain.Subscription {
+	subs := make([]domain.Subscription, 0, len(tracked)+len(detected))
+	idx := make(map[string]int, len(tracked))
+
+	for _, sub := range tracked {
+		idx[subscriptionKey(sub.Name, sub.Currency)] = len(subs)
+		subs = append(subs, sub)
+	}
+
+	for _, sub := range detected {
+		i, ok := idx[subscriptionKey(sub.Name, sub.Currency)]
+		if !ok {
+			subs = append(subs, sub)
+			continue
+		}
+
+		// history is more accurate in price changes
+		if subs[i].Money == sub.Money {
+			subs[i].PrevMoney = sub.PrevMoney
+		}
+	}
+
+	return subs
+}
+
+func subscriptionKey(name, currency string) string {
+	return domain.NormalizeOperationName(name) + "|" + currency
+}
+
+func (b *Bot) trackSubscription(c tele.Context, usr domain.User, data string) error {
+	state, ok := b.state.Get(usr.IDString())
+	if !ok {
+		return fmt.Errorf("subscription track callback: %w", errStateNotFound)
+	}
+
+	subs, ok := state.Data.([]domain.Subscription)
+	if !ok {
+		return fmt.Errorf("subscription track callback: %w", errInvalidStateData)
+	}
+
+	i, err := strconv.Atoi(data)
+	if err != nil || i < 0 || i >= len(subs) {
+		return fmt.Errorf("subscription track callback: %w", errUnsupportedCallbackData)
+	}
+
+	sub := subs[i]
+	if sub.Tracked {
+		return c.Send(msg.Getf(msg.SubscriptionAlreadyTracked, usr.Language, sub.Name))
+	}
+
+	if err := b.subscription.Save(stdContext(c), postgres.SaveSubscriptionParams{
+		ID:        uuid.NewString(),
+		UID:       usr.ID,
+		CatID:     sub.CatID,
+		Name:      sub.Name,
+		Currency:  sub.Currency,
+		Money:     sub.Money,
+		Interval:  sub.Interval,
+		NextAt:    sub.NextAt,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("subscription not saved: %w", err)
+	}
+
+	// state data is shared with the cache, so other buttons of the message see the change
+	subs[i].Tracked = true
+
+	return c.Send(msg.Getf(msg.SubscriptionTracked, usr.Language, sub.Name))
+}
+
+// chargeSubscription moves next payment date of tracked subscription with the same name as saved operation
+// and returns a warning if subscription price went up.
+func (b *Bot) chargeSubscription(ctx context.Context, usr domain.User, p postgres.SaveOperationParams) (string, error) {
+	if p.Money >= 0 {
+		return "", nil
+	}
+
+	sub, err := b.subscription.FindByName(ctx, usr.ID, p.Operation, p.Currency)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("subscription not found: %w", err)
+	}
+
+	if err := b.subscription.UpdateCharge(ctx, postgres.UpdateSubscriptionChargeParams{
+		ID:        sub.ID,
+		Money:     p.Money,
+		NextAt:    p.OccuredAt.AddDate(0, 0, sub.Interval),
+		UpdatedAt: time.Now(),
+	}); err != nil {
+		return "", fmt.Errorf("subscription charge not updated: %w", err)
+	}
+
+	if p.Money >= sub.Money {
+		return "", nil
+	}
+
+	slog.InfoContext(ctx, "subscription price increased", "subscription", sub.ID, "old", sub.Money, "new", p.Money)
+
+	return msg.Getf(msg.SubscriptionPriceIncreased, usr.Language,
+		sub.Name, msg.Money(-sub.Money, sub.Currency, usr.Language), msg.Money(-p.Money, sub.Currency, usr.Language)), nil
+}
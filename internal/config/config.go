@@ -1,14 +1,29 @@
 package config
 
+import "time"
+
 type Config struct {
-	LogLevel    string   `toml:"log_level" env-required:"true"`
-	AccessToken string   `env:"TELEGRAM_ACCESS_TOKEN" env-required:"true"`
-	Verbose     bool     `toml:"verbose"`
-	Postgres    Postgres `toml:"postgres"`
-	Version     string   `env:"VERSION"`
+	LogLevel    string     `toml:"log_level" env-required:"true"`
+	AccessToken string     `env:"TELEGRAM_ACCESS_TOKEN" env-required:"true"`
+	Verbose     bool       `toml:"verbose"`
+	Postgres    Postgres   `toml:"postgres"`
+	Reminders   Reminders  `toml:"reminders"`
+	Duplicates  Duplicates `toml:"duplicates"`
+	Version     string     `env:"VERSION"`
 }
 
 type Postgres struct {
 	URL      string `env:"PG_URL" env-required:"true"`
 	MaxConns int32  `toml:"max_conns" env-required:"true"`
 }
+
+type Reminders struct {
+	Interval time.Duration `toml:"interval" env-default:"1h"`
+}
+
+// Duplicates is tolerance of likely duplicate operations, operations of the same currency and similar name
+// are duplicates if their dates differ by Days at most and amounts by AmountPercent of the larger amount.
+type Duplicates struct {
+	Days          int     `toml:"days" env-default:"1"`
+	AmountPercent float64 `toml:"amount_percent" env-default:"0"`
+}
@@ -2,6 +2,7 @@ package date
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -25,3 +26,52 @@ func Parse(s string) (time.Time, error) {
 
 	return date, fmt.Errorf("invalid date format: %s", s)
 }
+
+// ParsePeriod parses period [from, to) relative to now, supported formats are
+// week, month, year, month in format 01.2006, year in format 2006 and
+// inclusive range of dates in format 02.01.2006-02.01.2006.
+// Empty string is current month.
+func ParsePeriod(s string, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch s {
+	case "", "month":
+		from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return from, from.AddDate(0, 1, 0), nil
+	case "week":
+		// week starts on monday
+		from := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+		return from, from.AddDate(0, 0, 7), nil
+	case "year":
+		from := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+		return from, from.AddDate(1, 0, 0), nil
+	}
+
+	if from, err := time.ParseInLocation("01.2006", s, now.Location()); err == nil {
+		return from, from.AddDate(0, 1, 0), nil
+	}
+
+	if from, err := time.ParseInLocation("2006", s, now.Location()); err == nil {
+		return from, from.AddDate(1, 0, 0), nil
+	}
+
+	if fromStr, toStr, ok := strings.Cut(s, "-"); ok {
+		from, err := Parse(fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+
+		to, err := Parse(toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+
+		if to.Before(from) {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid period: %s", s)
+		}
+
+		return from, to.AddDate(0, 0, 1), nil
+	}
+
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid period: %s", s)
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+// DefaultAccountName is name of the account created for every new user.
+const DefaultAccountName = "Cash"
+
+var ErrInvalidAccountName = errors.New("account name must be a single word")
+
+// Account is a wallet, card or any other place where money is kept.
+// Every operation belongs to an account and must be in account currency.
+type Account struct {
+	ID             string
+	UID            int64
+	Name           string
+	Currency       string
+	OpeningBalance money.Money
+	IsDefault      bool
+}
+
+// AccountBalance is account with its current balance.
+type AccountBalance struct {
+	Account
+	Balance money.Money
+}
+
+// AccountMention returns account name from operation text argument in format @name.
+func AccountMention(s string) (string, bool) {
+	name, ok := strings.CutPrefix(s, "@")
+	if !ok || name == "" {
+		return "", false
+	}
+
+	return name, true
+}
+
+func (a *Account) Validate() error {
+	a.Name = strings.TrimPrefix(a.Name, "@")
+	a.Currency = strings.ToUpper(a.Currency)
+
+	if a.Name == "" || strings.ContainsAny(a.Name, " @") {
+		return ErrInvalidAccountName
+	}
+
+	if !IsCurrency(a.Currency) {
+		return ErrUnsupportedCurrency
+	}
+
+	return nil
+}
+
+// DefaultAccountFor returns account which must be default after user currency is changed to currency,
+// so operations without account are in user currency. Default account is kept if it is in the currency,
+// its currency is changed if it is unused, i.e. has no operations, reconciliations and opening balance.
+// Otherwise the first account in the currency is returned. False is returned if user has no account
+// in the currency and a new one must be created.
+func DefaultAccountFor(accounts []Account, used map[string]bool, currency string) (Account, bool) {
+	for _, acc := range accounts {
+		if !acc.IsDefault {
+			continue
+		}
+
+		if acc.Currency == currency {
+			return acc, true
+		}
+
+		if !used[acc.ID] && acc.OpeningBalance == 0 {
+			acc.Currency = currency
+			return acc, true
+		}
+	}
+
+	for _, acc := range accounts {
+		if acc.Currency == currency {
+			return acc, true
+		}
+	}
+
+	return Account{}, false
+}
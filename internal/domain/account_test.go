@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultAccountFor(t *testing.T) {
+	accounts := []Account{
+		{ID: "cash", Name: "Cash", Currency: "USD", IsDefault: true},
+		{ID: "card", Name: "Card", Currency: "RUB"},
+		{ID: "euro", Name: "Euro", Currency: "EUR"},
+	}
+
+	acc, ok := DefaultAccountFor(accounts, nil, "RUB")
+	require.True(t, ok)
+	require.Equal(t, Account{ID: "cash", Name: "Cash", Currency: "RUB", IsDefault: true}, acc)
+
+	acc, ok = DefaultAccountFor(accounts, map[string]bool{"cash": true}, "RUB")
+	require.True(t, ok)
+	require.Equal(t, accounts[1], acc)
+
+	_, ok = DefaultAccountFor(accounts, map[string]bool{"cash": true}, "JPY")
+	require.False(t, ok)
+
+	accounts[0].OpeningBalance = 100
+	acc, ok = DefaultAccountFor(accounts, nil, "EUR")
+	require.True(t, ok)
+	require.Equal(t, accounts[2], acc)
+
+	acc, ok = DefaultAccountFor(accounts, map[string]bool{"cash": true}, "USD")
+	require.True(t, ok)
+	require.Equal(t, accounts[0], acc)
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+// incomeHistoryPeriods is number of previous pay periods used to estimate expected income.
+const incomeHistoryPeriods = 3
+
+// PayPeriod returns pay period [from, to) containing t, which starts on pay day of month.
+func PayPeriod(payDay int, t time.Time) (time.Time, time.Time) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	from := NextDueDate(payDay, time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location()))
+	if from.After(day) {
+		from = NextDueDate(payDay, time.Date(day.Year(), day.Month()-1, 1, 0, 0, 0, 0, day.Location()))
+	}
+
+	return from, NextDueDate(payDay, from.AddDate(0, 0, 1))
+}
+
+// Allowance is amount of money which is safe to spend today.
+type Allowance struct {
+	From        time.Time
+	To          time.Time
+	Income      money.Money // expected income for the pay period
+	Commitments money.Money // bills and subscriptions which are not paid till the end of pay period
+	Spent       money.Money // spent in pay period before today
+	SpentToday  money.Money
+	DaysLeft    int
+	Today       money.Money // negative if today budget is overspent
+	// Approximate is true if some money was converted using rate of an earlier date.
+	Approximate bool
+}
+
+type AllowanceParams struct {
+	PayDay int
+	Now    time.Time
+	// Operations from the start of incomeHistoryPeriods pay periods before current till now.
+	Operations    []Operation
+	Bills         []Bill
+	Subscriptions []Subscription
+}
+
+// AllowanceHistoryStart returns date from which operations must be passed to CalcAllowance.
+func AllowanceHistoryStart(payDay int, now time.Time) time.Time {
+	from, _ := PayPeriod(payDay, now)
+
+	for range incomeHistoryPeriods {
+		from, _ = PayPeriod(payDay, from.AddDate(0, 0, -1))
+	}
+
+	return from
+}
+
+// CalcAllowance divides money left in the current pay period by days left in it.
+// Expected income is the biggest of income received in the current period
+// and average income of previous periods.
+func CalcAllowance(p AllowanceParams) Allowance {
+	from, to := PayPeriod(p.PayDay, p.Now)
+	today := time.Date(p.Now.Year(), p.Now.Month(), p.Now.Day(), 0, 0, 0, 0, p.Now.Location())
+	historyFrom := AllowanceHistoryStart(p.PayDay, p.Now)
+
+	a := Allowance{
+		From:     from,
+		To:       to,
+		DaysLeft: daysBetween(today, to),
+	}
+
+	var prevIncome, income money.Money
+
+	for _, op := range p.Operations {
+		switch {
+		case op.OccuredAt.Before(historyFrom):
+		case op.OccuredAt.Before(from):
+			if op.Money > 0 {
+				prevIncome += op.Money
+			}
+		case op.Money > 0:
+			income += op.Money
+		case op.OccuredAt.Before(today):
+			a.Spent -= op.Money
+		default:
+			a.SpentToday -= op.Money
+		}
+	}
+
+	a.Income = max(income, prevIncome/incomeHistoryPeriods)
+
+	for _, b := range p.Bills {
+		if b.NextDueAt.Before(to) {
+			a.Commitments -= b.Money
+		}
+	}
+
+	for _, s := range p.Subscriptions {
+		if s.Interval <= 0 {
+			continue
+		}
+
+		for t := s.NextAt; t.Before(to); t = t.AddDate(0, 0, s.Interval) {
+			if !t.Before(today) {
+				a.Commitments -= s.Money
+			}
+		}
+	}
+
+	left := a.Income - a.Commitments - a.Spent
+	if a.DaysLeft > 0 {
+		left /= money.Money(a.DaysLeft)
+	}
+
+	a.Today = left - a.SpentToday
+
+	return a
+}
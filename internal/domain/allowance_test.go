@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayPeriod(t *testing.T) {
+	from, to := PayPeriod(10, time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC))
+	require.Equal(t, time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), from)
+	require.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), to)
+
+	from, to = PayPeriod(10, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), from)
+	require.Equal(t, time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC), to)
+
+	from, to = PayPeriod(31, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	require.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), from)
+	require.Equal(t, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), to)
+}
+
+func TestCalcAllowance(t *testing.T) {
+	now := time.Date(2024, 3, 21, 15, 0, 0, 0, time.UTC)
+
+	a := CalcAllowance(AllowanceParams{
+		PayDay: 1,
+		Now:    now,
+		Operations: []Operation{
+			newOperation("old salary", 9000000, time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)),
+			newOperation("salary", 9000000, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+			newOperation("salary", 9000000, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)),
+			newOperation("rent", -3000000, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)),
+			newOperation("food", -1000000, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)),
+			newOperation("coffee", -30000, time.Date(2024, 3, 21, 0, 0, 0, 0, time.UTC)),
+		},
+		Bills: []Bill{
+			{Money: -70000, NextDueAt: time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC)},
+			{Money: -50000, NextDueAt: time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC)},
+		},
+		Subscriptions: []Subscription{
+			{Money: -10000, Interval: 7, NextAt: time.Date(2024, 3, 22, 0, 0, 0, 0, time.UTC)},
+		},
+	})
+
+	require.EqualValues(t, 6000000, a.Income)
+	require.EqualValues(t, 70000+20000, a.Commitments)
+	require.EqualValues(t, 4000000, a.Spent)
+	require.EqualValues(t, 30000, a.SpentToday)
+	require.Equal(t, 11, a.DaysLeft)
+	// (60000 - 900 - 40000) / 11 - 300
+	require.EqualValues(t, 173636-30000, a.Today)
+}
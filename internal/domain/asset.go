@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+const (
+	// DefaultAssetDecimals is number of decimal places of asset if user did not provide it.
+	DefaultAssetDecimals = 8
+	// AssetDisplayDecimals is maximal number of decimal places shown to user.
+	AssetDisplayDecimals = 8
+	maxTickerLen         = 10
+)
+
+var (
+	ErrInvalidTicker        = errors.New("ticker must be up to 10 latin letters or digits")
+	ErrInvalidAssetDecimals = errors.New("asset decimals must be from 0 to 18")
+	ErrTickerIsCurrency     = errors.New("ticker must not be a currency code")
+)
+
+// Asset is user defined holding which is not a currency, e.g. cryptocurrency or stock.
+// Asset is valued in currencies through exchange rates with ticker as base.
+type Asset struct {
+	ID        string
+	UID       int64
+	Ticker    string
+	Decimals  int // number of decimal places of amount
+	CreatedAt time.Time
+}
+
+func (a *Asset) Validate() error {
+	a.Ticker = strings.ToUpper(a.Ticker)
+
+	if !IsTicker(a.Ticker) {
+		return ErrInvalidTicker
+	}
+
+	if IsCurrency(a.Ticker) {
+		return ErrTickerIsCurrency
+	}
+
+	if a.Decimals < 0 || a.Decimals > money.MaxScale {
+		return ErrInvalidAssetDecimals
+	}
+
+	return nil
+}
+
+// DisplayDecimals returns number of decimal places which is sensible to show to user.
+func (a Asset) DisplayDecimals() int {
+	return min(a.Decimals, AssetDisplayDecimals)
+}
+
+// IsTicker returns true if s is up to 10 upper case latin letters or digits.
+func IsTicker(s string) bool {
+	if s == "" || len(s) > maxTickerLen {
+		return false
+	}
+
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AssetMovement is amount of asset bought (positive) or sold (negative).
+type AssetMovement struct {
+	Ticker    string
+	Amount    money.Decimal
+	OccuredAt time.Time
+}
+
+// Holding is amount of asset held by user.
+type Holding struct {
+	Asset
+	Amount money.Decimal
+}
+
+// AssetValue is amount of asset valued in currency.
+type AssetValue struct {
+	Ticker string
+	Amount money.Decimal
+	Money  money.Money
+}
+
+// SumHoldings returns amount of every asset before to, assets with zero amount are skipped.
+func SumHoldings(movements []AssetMovement, to time.Time) map[string]money.Decimal {
+	sums := make(map[string]money.Decimal)
+
+	for _, m := range movements {
+		if m.OccuredAt.Before(to) {
+			sums[m.Ticker] = sums[m.Ticker].Add(m.Amount)
+		}
+	}
+
+	for ticker, amount := range sums {
+		if amount.IsZero() {
+			delete(sums, ticker)
+		}
+	}
+
+	return sums
+}
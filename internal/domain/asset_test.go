@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+func TestAssetValidate(t *testing.T) {
+	a := Asset{Ticker: "usdt", Decimals: 6}
+	require.NoError(t, a.Validate())
+	require.Equal(t, "USDT", a.Ticker)
+
+	a = Asset{Ticker: "TOOLONGTICKER", Decimals: 8}
+	require.ErrorIs(t, a.Validate(), ErrInvalidTicker)
+
+	a = Asset{Ticker: "B-C", Decimals: 8}
+	require.ErrorIs(t, a.Validate(), ErrInvalidTicker)
+
+	a = Asset{Ticker: "USD", Decimals: 2}
+	require.ErrorIs(t, a.Validate(), ErrTickerIsCurrency)
+
+	a = Asset{Ticker: "ETH", Decimals: 19}
+	require.ErrorIs(t, a.Validate(), ErrInvalidAssetDecimals)
+}
+
+func mustDecimal(t *testing.T, s string) money.Decimal {
+	t.Helper()
+
+	d, err := money.ParseDecimal(s, money.MaxScale)
+	require.NoError(t, err)
+
+	return d
+}
+
+func TestAssetsValuation(t *testing.T) {
+	conv := NewConverter([]ExchangeRate{
+		{Date: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), Base: "BTC", Quote: "USD", Rate: 40000},
+		{Date: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), Base: "USD", Quote: "RUB", Rate: 90},
+	})
+
+	assets := []AssetMovement{
+		{Ticker: "BTC", Amount: mustDecimal(t, "0.015"), OccuredAt: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)},
+		{Ticker: "BTC", Amount: mustDecimal(t, "-0.005"), OccuredAt: time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)},
+		{Ticker: "BTC", Amount: mustDecimal(t, "1"), OccuredAt: time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)},
+		{Ticker: "ETH", Amount: mustDecimal(t, "0.1"), OccuredAt: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)},
+		{Ticker: "ETH", Amount: mustDecimal(t, "-0.1"), OccuredAt: time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)},
+	}
+
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	holdings := SumHoldings(assets, to)
+	require.Len(t, holdings, 1)
+	require.Equal(t, "0.01", holdings["BTC"].String())
+
+	// 0.01 BTC = 400 USD = 36000 RUB
+	m, approximate := CalcNetWorth(NetWorthParams{
+		To:        to,
+		Currency:  "RUB",
+		Assets:    assets,
+		Converter: conv,
+	})
+	require.False(t, approximate)
+	require.EqualValues(t, 3600000, m)
+
+	r := Report{To: to, Currency: "USD"}
+	r.AddAssets(append(assets, AssetMovement{Ticker: "XYZ", Amount: mustDecimal(t, "5")}), conv)
+	require.Equal(t, []string{"XYZ"}, r.Unconverted)
+	require.Len(t, r.Assets, 1)
+	require.EqualValues(t, 40000, r.Assets[0].Money)
+}
@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+const (
+	// BackupFormat identifies backup archive among other JSON files.
+	BackupFormat = "financer-backup"
+	// BackupVersion is version of backup archive schema, it must be incremented on any change of the schema
+	// which makes archives of previous version unreadable.
+	BackupVersion = 1
+)
+
+var (
+	ErrBackupFormat  = errors.New("file is not a backup")
+	ErrBackupVersion = errors.New("unsupported backup version")
+	ErrInvalidBackup = errors.New("invalid backup")
+)
+
+// Backup is archive with all data of user: settings, own categories, categories attached to user,
+// keywords, accounts and not deleted operations. Money is in minor units of its currency.
+type Backup struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+
+	User       BackupUser       `json:"user"`
+	Categories []BackupCategory `json:"categories"`
+	// UserCategories are ids of categories attached to user, both own and common ones.
+	UserCategories []string          `json:"user_categories"`
+	Keywords       []BackupKeyword   `json:"keywords"`
+	Accounts       []BackupAccount   `json:"accounts"`
+	Operations     []BackupOperation `json:"operations"`
+}
+
+type BackupUser struct {
+	Currency        string `json:"currency"`
+	Language        string `json:"language"`
+	PayDay          int    `json:"pay_day"`
+	AllowanceFooter bool   `json:"allowance_footer"`
+}
+
+// BackupCategory is category created by user.
+type BackupCategory struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Type      CatType    `json:"type"`
+	CreatedAt time.Time  `json:"created_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+type BackupKeyword struct {
+	Operation string `json:"operation"`
+	CatID     string `json:"category_id"`
+}
+
+type BackupAccount struct {
+	ID             string      `json:"id"`
+	Name           string      `json:"name"`
+	Currency       string      `json:"currency"`
+	OpeningBalance money.Money `json:"opening_balance"`
+	IsDefault      bool        `json:"is_default"`
+	CreatedAt      time.Time   `json:"created_at"`
+	DeletedAt      *time.Time  `json:"deleted_at,omitempty"`
+}
+
+type BackupOperation struct {
+	ID            string        `json:"id"`
+	Type          OperationType `json:"type"`
+	AccountID     string        `json:"account_id"`
+	CatID         string        `json:"category_id,omitempty"`
+	Name          string        `json:"name"`
+	Currency      string        `json:"currency"`
+	Money         money.Money   `json:"money"`
+	ToAccountID   string        `json:"to_account_id,omitempty"`
+	ToCurrency    string        `json:"to_currency,omitempty"`
+	ToMoney       money.Money   `json:"to_money,omitempty"`
+	Rate          *float64      `json:"rate,omitempty"`
+	ReferenceRate *float64      `json:"reference_rate,omitempty"`
+	ExternalID    string        `json:"external_id,omitempty"`
+	OccuredAt     time.Time     `json:"occured_at"`
+	CreatedAt     time.Time     `json:"created_at"`
+	DuplicateKept bool          `json:"duplicate_kept,omitempty"`
+}
+
+// Validate checks version of backup and references between its records,
+// categories of operations and keywords may be common categories which are not in backup.
+func (b Backup) Validate() error {
+	if b.Format != BackupFormat {
+		return ErrBackupFormat
+	}
+
+	if b.Version != BackupVersion {
+		return fmt.Errorf("%w: %d", ErrBackupVersion, b.Version)
+	}
+
+	usr := User{Currency: b.User.Currency, Language: b.User.Language, PayDay: b.User.PayDay}
+	if err := usr.Validate(); err != nil {
+		return fmt.Errorf("%w: user: %w", ErrInvalidBackup, err)
+	}
+
+	ids := make([]string, 0, len(b.Categories)+len(b.UserCategories)+len(b.Keywords))
+
+	for _, cat := range b.Categories {
+		if cat.Type != CatTypeExpenses && cat.Type != CatTypeIncome && cat.Type != CatTypeOther {
+			return fmt.Errorf("%w: category %s: invalid type %s", ErrInvalidBackup, cat.ID, cat.Type)
+		}
+
+		ids = append(ids, cat.ID)
+	}
+
+	ids = append(ids, b.UserCategories...)
+	for _, kw := range b.Keywords {
+		ids = append(ids, kw.CatID)
+	}
+
+	accounts := make(map[string]string, len(b.Accounts))
+
+	for _, acc := range b.Accounts {
+		if !IsCurrency(acc.Currency) {
+			return fmt.Errorf("%w: account %s: %w", ErrInvalidBackup, acc.ID, ErrUnsupportedCurrency)
+		}
+
+		ids = append(ids, acc.ID)
+		accounts[acc.ID] = acc.Currency
+	}
+
+	for _, op := range b.Operations {
+		if err := op.validate(accounts); err != nil {
+			return fmt.Errorf("%w: operation %s: %w", ErrInvalidBackup, op.ID, err)
+		}
+
+		ids = append(ids, op.ID)
+		if op.CatID != "" {
+			ids = append(ids, op.CatID)
+		}
+	}
+
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return fmt.Errorf("%w: invalid id %s", ErrInvalidBackup, id)
+		}
+	}
+
+	return nil
+}
+
+func (op BackupOperation) validate(accounts map[string]string) error {
+	if cur, ok := accounts[op.AccountID]; !ok || cur != op.Currency {
+		return errors.New("account not found or in other currency")
+	}
+
+	switch op.Type {
+	case OperationTypeRegular, OperationTypeAdjustment:
+		return nil
+	case OperationTypeTransfer, OperationTypeExchange:
+		if cur, ok := accounts[op.ToAccountID]; !ok || cur != op.ToCurrency {
+			return errors.New("destination account not found or in other currency")
+		}
+
+		if op.Type == OperationTypeExchange && op.Rate == nil {
+			return errors.New("exchange has no rate")
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("invalid type %s", op.Type)
+}
+
+// BackupID returns id of restored record. Record keeps its id if the id is free or belongs to the same user,
+// otherwise id is derived from the original one and user id, so restoring the same backup again
+// returns the same ids and restored records are not duplicated.
+func BackupID(id string, uid int64, taken bool) string {
+	if !taken {
+		return id
+	}
+
+	return uuid.NewSHA1(uuid.MustParse(id), []byte(strconv.FormatInt(uid, 10))).String()
+}
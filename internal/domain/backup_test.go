@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func validBackup() Backup {
+	rate := 0.92
+
+	return Backup{
+		Format:  BackupFormat,
+		Version: BackupVersion,
+		User:    BackupUser{Currency: "USD", Language: "en", PayDay: 1},
+		Categories: []BackupCategory{
+			{ID: "5f0b6f7e-3b5e-4a57-9c2c-1d1f1a2b3c4d", Name: "Coffee", Type: CatTypeExpenses},
+		},
+		UserCategories: []string{"5f0b6f7e-3b5e-4a57-9c2c-1d1f1a2b3c4d", OtherCategoryID},
+		Keywords:       []BackupKeyword{{Operation: "starbucks", CatID: "5f0b6f7e-3b5e-4a57-9c2c-1d1f1a2b3c4d"}},
+		Accounts: []BackupAccount{
+			{ID: "0c6d3f1e-8a1b-4d0e-9f5a-2b3c4d5e6f70", Name: "Cash", Currency: "USD", IsDefault: true},
+			{ID: "1d7e4a2f-9b2c-4e1f-8a6b-3c4d5e6f7081", Name: "Euro", Currency: "EUR"},
+		},
+		Operations: []BackupOperation{
+			{
+				ID:        "2e8f5b3a-ac3d-4f2a-9b7c-4d5e6f708192",
+				Type:      OperationTypeRegular,
+				AccountID: "0c6d3f1e-8a1b-4d0e-9f5a-2b3c4d5e6f70",
+				CatID:     "5f0b6f7e-3b5e-4a57-9c2c-1d1f1a2b3c4d",
+				Name:      "starbucks",
+				Currency:  "USD",
+				Money:     -450,
+				OccuredAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				ID:          "3f9a6c4b-bd4e-4a3b-8c8d-5e6f708192a3",
+				Type:        OperationTypeExchange,
+				AccountID:   "0c6d3f1e-8a1b-4d0e-9f5a-2b3c4d5e6f70",
+				Name:        "Cash → Euro",
+				Currency:    "USD",
+				Money:       -10000,
+				ToAccountID: "1d7e4a2f-9b2c-4e1f-8a6b-3c4d5e6f7081",
+				ToCurrency:  "EUR",
+				ToMoney:     9200,
+				Rate:        &rate,
+				OccuredAt:   time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+}
+
+func TestBackupValidate(t *testing.T) {
+	require.NoError(t, validBackup().Validate())
+
+	tests := []struct {
+		name   string
+		modify func(b *Backup)
+		err    error
+	}{
+		{
+			name:   "other format",
+			modify: func(b *Backup) { b.Format = "" },
+			err:    ErrBackupFormat,
+		},
+		{
+			name:   "newer version",
+			modify: func(b *Backup) { b.Version = BackupVersion + 1 },
+			err:    ErrBackupVersion,
+		},
+		{
+			name:   "unknown account",
+			modify: func(b *Backup) { b.Operations[0].AccountID = "4a0b7d5c-ce5f-4b4c-9d9e-6f708192a3b4" },
+			err:    ErrInvalidBackup,
+		},
+		{
+			name:   "currency of other account",
+			modify: func(b *Backup) { b.Operations[1].ToCurrency = "USD" },
+			err:    ErrInvalidBackup,
+		},
+		{
+			name:   "exchange without rate",
+			modify: func(b *Backup) { b.Operations[1].Rate = nil },
+			err:    ErrInvalidBackup,
+		},
+		{
+			name:   "invalid id",
+			modify: func(b *Backup) { b.Keywords[0].CatID = "coffee" },
+			err:    ErrInvalidBackup,
+		},
+		{
+			name:   "invalid user",
+			modify: func(b *Backup) { b.User.Language = "de" },
+			err:    ErrInvalidBackup,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := validBackup()
+			tt.modify(&b)
+			require.ErrorIs(t, b.Validate(), tt.err)
+		})
+	}
+}
+
+func TestBackupID(t *testing.T) {
+	id := "2e8f5b3a-ac3d-4f2a-9b7c-4d5e6f708192"
+
+	require.Equal(t, id, BackupID(id, 1, false))
+
+	restored := BackupID(id, 1, true)
+	require.NotEqual(t, id, restored)
+	require.Equal(t, restored, BackupID(id, 1, true))
+	require.NotEqual(t, restored, BackupID(id, 2, true))
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+// Bill is a regular payment which is not charged automatically, so user must be reminded about it.
+type Bill struct {
+	ID         string
+	UID        int64
+	Name       string
+	Currency   string
+	Money      money.Money // expected amount, negative as any expense
+	DueDay     int
+	RemindDays int
+	NextDueAt  time.Time
+}
+
+// RemindAt returns date when user must be reminded about the next payment.
+func (b Bill) RemindAt() time.Time {
+	return b.NextDueAt.AddDate(0, 0, -b.RemindDays)
+}
+
+// NextDueDate returns first date not before t with day of month equal to dueDay.
+// If month is shorter than dueDay the last day of month is used.
+func NextDueDate(dueDay int, t time.Time) time.Time {
+	t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	for {
+		due := time.Date(t.Year(), t.Month(), min(dueDay, daysIn(t.Year(), t.Month())), 0, 0, 0, 0, t.Location())
+		if !due.Before(t) {
+			return due
+		}
+
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+	}
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
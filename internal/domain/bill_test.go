@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextDueDate(t *testing.T) {
+	tests := []struct {
+		name   string
+		dueDay int
+		from   time.Time
+		want   time.Time
+	}{
+		{
+			name:   "same month",
+			dueDay: 25,
+			from:   time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 1, 25, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "due today",
+			dueDay: 10,
+			from:   time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "next month",
+			dueDay: 5,
+			from:   time.Date(2024, 12, 10, 0, 0, 0, 0, time.UTC),
+			want:   time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "short month",
+			dueDay: 31,
+			from:   time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, NextDueDate(tt.dueDay, tt.from))
+		})
+	}
+}
@@ -1,6 +1,14 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// MaxNameLen is max length of category and operation names in runes.
+const MaxNameLen = 64
 
 type CatType string
 
@@ -16,3 +24,17 @@ func (t CatType) String() string {
 }
 
 var OtherCategoryID = uuid.Nil.String()
+
+// NormalizeCategoryName lowers category name and drops emojis and punctuation,
+// so "🍏 Groceries" and "groceries" are treated as the same category.
+func NormalizeCategoryName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+
+		return ' '
+	}, s)
+
+	return strings.Join(strings.Fields(s), " ")
+}
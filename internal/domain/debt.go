@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+type DebtType string
+
+const (
+	DebtTypeLend   DebtType = "LEND"
+	DebtTypeBorrow DebtType = "BORROW"
+	DebtTypeRepay  DebtType = "REPAY"
+)
+
+func (t DebtType) String() string {
+	return string(t)
+}
+
+// Debt is money lent, borrowed or repaid, it is not an operation and never affects expenses or income.
+type Debt struct {
+	ID        string
+	UID       int64
+	Person    string
+	Type      DebtType
+	Currency  string
+	Money     money.Money // positive if person owes user, negative if user owes person
+	OccuredAt time.Time
+}
+
+// DebtBalance is total debt between user and person in one currency.
+type DebtBalance struct {
+	Person   string
+	Currency string
+	Money    money.Money // positive if person owes user, negative if user owes person
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+// DuplicateTolerance is how much operations may differ to be likely duplicates,
+// zero tolerance matches operations of the same date and amount only.
+type DuplicateTolerance struct {
+	Days          int     // max difference of dates in days
+	AmountPercent float64 // max difference of amounts in percents of the larger amount
+}
+
+// Fingerprint identifies operation by date, amount, currency and normalized name to find likely duplicates,
+// e.g. operation typed twice by accident or imported from statement after it was typed manually.
+type Fingerprint struct {
+	Date     time.Time // date without time in UTC
+	Money    money.Money
+	Currency string
+	Name     string // name normalized by NormalizeCategoryName
+}
+
+func NewFingerprint(name, currency string, m money.Money, occuredAt time.Time) Fingerprint {
+	return Fingerprint{
+		Date:     time.Date(occuredAt.Year(), occuredAt.Month(), occuredAt.Day(), 0, 0, 0, 0, time.UTC),
+		Money:    m,
+		Currency: currency,
+		Name:     NormalizeCategoryName(name),
+	}
+}
+
+func (o Operation) Fingerprint() Fingerprint {
+	return NewFingerprint(o.Name, o.Currency, o.Money, o.OccuredAt)
+}
+
+func (o ImportedOperation) Fingerprint() Fingerprint {
+	return NewFingerprint(o.Name, o.Currency, o.Money, o.OccuredAt)
+}
+
+// Window returns [from, to) dates of operations which may be duplicates of operations occured in [first, last],
+// window is one day wider than tolerance, so it includes whole days in any time zone.
+func (t DuplicateTolerance) Window(first, last time.Time) (time.Time, time.Time) {
+	from := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, first.Location())
+	to := time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, last.Location())
+
+	return from.AddDate(0, 0, -t.Days-1), to.AddDate(0, 0, t.Days+2)
+}
+
+// Matches returns true if operations are likely duplicates: they are in the same currency, both are expenses or
+// incomes, dates and amounts differ within tolerance and words of one name are all in the other name,
+// so "coffee" matches "Coffee Starbucks".
+func (t DuplicateTolerance) Matches(a, b Fingerprint) bool {
+	if a.Currency != b.Currency || (a.Money < 0) != (b.Money < 0) {
+		return false
+	}
+
+	if days := a.Date.Sub(b.Date).Abs().Hours() / 24; days > float64(t.Days) {
+		return false
+	}
+
+	larger := max(a.Money.Abs(), b.Money.Abs())
+	if float64((a.Money - b.Money).Abs()) > float64(larger)*t.AmountPercent/100 {
+		return false
+	}
+
+	return nameContains(a.Name, b.Name) || nameContains(b.Name, a.Name)
+}
+
+// nameContains returns true if every word of sub is a word of name.
+func nameContains(name, sub string) bool {
+	if sub == "" {
+		return name == ""
+	}
+
+	words := strings.Fields(name)
+
+	for _, w := range strings.Fields(sub) {
+		if !slices.Contains(words, w) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FindDuplicate returns the first of ops which is likely duplicate of operation with fingerprint fp.
+func (t DuplicateTolerance) FindDuplicate(ops []Operation, fp Fingerprint) (Operation, bool) {
+	for _, op := range ops {
+		if op.Type == OperationTypeRegular && t.Matches(op.Fingerprint(), fp) {
+			return op, true
+		}
+	}
+
+	return Operation{}, false
+}
+
+// DuplicatePair is operations which are likely duplicates, duplicate is the one created later.
+type DuplicatePair struct {
+	Original  Operation
+	Duplicate Operation
+}
+
+// FindDuplicates returns pairs of likely duplicates among regular operations ordered by occured_at.
+// Every operation is in one pair at most, operations which user kept are not reported as duplicates.
+func (t DuplicateTolerance) FindDuplicates(ops []Operation) []DuplicatePair {
+	var (
+		pairs  []DuplicatePair
+		paired = make(map[string]bool)
+	)
+
+	for i, a := range ops {
+		if paired[a.ID] || a.Type != OperationTypeRegular {
+			continue
+		}
+
+		fp := a.Fingerprint()
+
+		for _, b := range ops[i+1:] {
+			if b.OccuredAt.Sub(a.OccuredAt) > time.Duration(t.Days+1)*24*time.Hour {
+				break
+			}
+
+			if paired[b.ID] || b.Type != OperationTypeRegular || !t.Matches(fp, b.Fingerprint()) {
+				continue
+			}
+
+			pair := DuplicatePair{Original: a, Duplicate: b}
+			if b.CreatedAt.Before(a.CreatedAt) {
+				pair = DuplicatePair{Original: b, Duplicate: a}
+			}
+
+			if pair.Duplicate.DuplicateKept {
+				continue
+			}
+
+			paired[a.ID], paired[b.ID] = true, true
+			pairs = append(pairs, pair)
+
+			break
+		}
+	}
+
+	return pairs
+}
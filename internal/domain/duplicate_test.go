@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+func TestDuplicateToleranceMatches(t *testing.T) {
+	day := time.Date(2024, 3, 1, 23, 30, 0, 0, time.UTC)
+	fp := NewFingerprint("Coffee", "RUB", -30000, day)
+
+	tests := []struct {
+		name string
+		tol  DuplicateTolerance
+		fp   Fingerprint
+		want bool
+	}{
+		{"same", DuplicateTolerance{}, NewFingerprint("coffee", "RUB", -30000, day.Add(-time.Hour)), true},
+		{"more words", DuplicateTolerance{}, NewFingerprint("COFFEE, Starbucks", "RUB", -30000, day), true},
+		{"other name", DuplicateTolerance{}, NewFingerprint("tea", "RUB", -30000, day), false},
+		{"other currency", DuplicateTolerance{}, NewFingerprint("coffee", "USD", -30000, day), false},
+		{"income", DuplicateTolerance{}, NewFingerprint("coffee", "RUB", 30000, day), false},
+		{"next day", DuplicateTolerance{}, NewFingerprint("coffee", "RUB", -30000, day.Add(time.Hour)), false},
+		{"next day within days", DuplicateTolerance{Days: 1}, NewFingerprint("coffee", "RUB", -30000, day.Add(time.Hour)), true},
+		{"other amount", DuplicateTolerance{Days: 1}, NewFingerprint("coffee", "RUB", -31000, day), false},
+		{"amount within percent", DuplicateTolerance{AmountPercent: 5}, NewFingerprint("coffee", "RUB", -31000, day), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.tol.Matches(fp, tt.fp))
+			require.Equal(t, tt.want, tt.tol.Matches(tt.fp, fp))
+		})
+	}
+}
+
+func TestDuplicateToleranceFindDuplicates(t *testing.T) {
+	day := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	op := func(id, name string, m int64, occuredAt time.Time, createdAt time.Duration) Operation {
+		return Operation{
+			ID:        id,
+			Type:      OperationTypeRegular,
+			Name:      name,
+			Currency:  "RUB",
+			Money:     money.Money(m),
+			OccuredAt: occuredAt,
+			CreatedAt: day.Add(createdAt),
+		}
+	}
+
+	kept := op("5", "taxi", -50000, day.AddDate(0, 0, 1), 5)
+	kept.DuplicateKept = true
+
+	ops := []Operation{
+		op("1", "coffee", -30000, day, 2),
+		op("2", "Coffee", -30000, day, 1),
+		op("3", "coffee", -30000, day, 3),
+		op("4", "taxi", -50000, day, 4),
+		kept,
+		op("6", "salary", 1000000, day.AddDate(0, 0, 2), 6),
+		op("7", "salary", 1000000, day.AddDate(0, 0, 5), 7),
+	}
+
+	pairs := DuplicateTolerance{Days: 1}.FindDuplicates(ops)
+	require.Len(t, pairs, 1)
+	require.Equal(t, "2", pairs[0].Original.ID)
+	require.Equal(t, "1", pairs[0].Duplicate.ID)
+}
+
+func TestDuplicateToleranceWindow(t *testing.T) {
+	from, to := DuplicateTolerance{Days: 1}.Window(
+		time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC),
+	)
+	require.Equal(t, time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC), from)
+	require.Equal(t, time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC), to)
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+var (
+	ErrExchangeSameCurrency = errors.New("exchange currencies must be different")
+	ErrExchangeAmount       = errors.New("exchange amounts must be positive")
+)
+
+// CurrencyExchange is purchase of money in currency of To account for money from account From.
+type CurrencyExchange struct {
+	From      Account
+	To        Account
+	Money     money.Money // positive, sold
+	ToMoney   money.Money // positive, bought
+	OccuredAt time.Time
+}
+
+func (e CurrencyExchange) Validate() error {
+	if e.From.Currency == e.To.Currency {
+		return ErrExchangeSameCurrency
+	}
+
+	if e.Money <= 0 || e.ToMoney <= 0 {
+		return ErrExchangeAmount
+	}
+
+	return nil
+}
+
+// Rate returns effective price of 1 bought currency unit in sold currency.
+func (e CurrencyExchange) Rate() float64 {
+	return e.Money.Float(e.From.Currency) / e.ToMoney.Float(e.To.Currency)
+}
+
+// Spread returns how much more was paid comparing to reference rate
+// in percent of reference rate and in sold currency, negative if exchange was better than reference.
+func (e CurrencyExchange) Spread(reference float64) (float64, money.Money) {
+	if reference <= 0 {
+		return 0, 0
+	}
+
+	fair, err := money.FromFloat(e.ToMoney.Float(e.To.Currency)*reference, e.From.Currency)
+	if err != nil {
+		return 0, 0
+	}
+
+	return (e.Rate() - reference) / reference * 100, e.Money - fair
+}
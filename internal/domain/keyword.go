@@ -0,0 +1,38 @@
+package domain
+
+import "strings"
+
+// Keyword is name of operation which user saved to category before.
+type Keyword struct {
+	Operation string
+	CatID     string
+	CatName   string
+	CatType   CatType
+}
+
+// SuggestCategory returns keyword which operation name is equal to the name or the longest keyword
+// contained in the name as whole words, e.g. "STARBUCKS #123 SEATTLE" matches "starbucks".
+func SuggestCategory(keywords []Keyword, name string, ct CatType) (Keyword, bool) {
+	var (
+		best  Keyword
+		found bool
+		words = " " + NormalizeOperationName(name) + " "
+	)
+
+	for _, kw := range keywords {
+		op := NormalizeOperationName(kw.Operation)
+		if kw.CatType != ct || op == "" {
+			continue
+		}
+
+		if " "+op+" " == words {
+			return kw, true
+		}
+
+		if strings.Contains(words, " "+op+" ") && (!found || len(op) > len(NormalizeOperationName(best.Operation))) {
+			best, found = kw, true
+		}
+	}
+
+	return best, found
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestCategory(t *testing.T) {
+	keywords := []Keyword{
+		{Operation: "starbucks", CatID: "cafe", CatType: CatTypeExpenses},
+		{Operation: "Starbucks Seattle", CatID: "trips", CatType: CatTypeExpenses},
+		{Operation: "acme", CatID: "salary", CatType: CatTypeIncome},
+		{Operation: "bar", CatID: "bars", CatType: CatTypeExpenses},
+	}
+
+	kw, ok := SuggestCategory(keywords, "STARBUCKS #123", CatTypeExpenses)
+	require.True(t, ok)
+	require.Equal(t, "cafe", kw.CatID)
+
+	kw, ok = SuggestCategory(keywords, "starbucks  seattle wa", CatTypeExpenses)
+	require.True(t, ok)
+	require.Equal(t, "trips", kw.CatID)
+
+	kw, ok = SuggestCategory(keywords, "ACME", CatTypeIncome)
+	require.True(t, ok)
+	require.Equal(t, "salary", kw.CatID)
+
+	_, ok = SuggestCategory(keywords, "ACME", CatTypeExpenses)
+	require.False(t, ok)
+
+	// keyword is matched by whole words only
+	_, ok = SuggestCategory(keywords, "barber shop", CatTypeExpenses)
+	require.False(t, ok)
+}
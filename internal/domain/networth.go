@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+// Movement is money put to or taken from account by operation.
+type Movement struct {
+	AccountID string
+	Currency  string
+	Money     money.Money
+	OccuredAt time.Time
+}
+
+// NetWorthSnapshot is net worth at the end of month.
+type NetWorthSnapshot struct {
+	Month       time.Time // first day of month
+	Money       money.Money
+	Approximate bool
+}
+
+// NetWorth is current net worth with monthly history ordered by month, current month is the last one.
+type NetWorth struct {
+	Currency string
+	History  []NetWorthSnapshot
+}
+
+// Current returns net worth of the current month.
+func (n NetWorth) Current() NetWorthSnapshot {
+	if len(n.History) == 0 {
+		return NetWorthSnapshot{}
+	}
+
+	return n.History[len(n.History)-1]
+}
+
+// Change returns change of net worth comparing to the end of month which was months ago,
+// returns false if there is no history for that month.
+func (n NetWorth) Change(months int) (money.Money, bool) {
+	cur := n.Current()
+	month := cur.Month.AddDate(0, -months, 0)
+
+	for _, s := range n.History {
+		if s.Month.Equal(month) {
+			return cur.Money - s.Money, true
+		}
+	}
+
+	return 0, false
+}
+
+type NetWorthParams struct {
+	To        time.Time // exclusive
+	Currency  string
+	Accounts  []Account
+	Movements []Movement
+	Debts     []Debt
+	Assets    []AssetMovement
+	Converter *Converter
+}
+
+// CalcNetWorth sums up balances of accounts, outstanding debts and assets held before p.To,
+// sums in every currency and asset are converted by rate on the last day before p.To.
+// Sums without exchange rate are not included and make result approximate.
+func CalcNetWorth(p NetWorthParams) (money.Money, bool) {
+	sums := make(map[string]money.Money)
+
+	for _, acc := range p.Accounts {
+		sums[acc.Currency] += acc.OpeningBalance
+	}
+
+	for _, m := range p.Movements {
+		if m.OccuredAt.Before(p.To) {
+			sums[m.Currency] += m.Money
+		}
+	}
+
+	for _, d := range p.Debts {
+		if d.OccuredAt.Before(p.To) {
+			sums[d.Currency] += d.Money
+		}
+	}
+
+	var (
+		total       money.Money
+		approximate bool
+		at          = p.To.AddDate(0, 0, -1)
+	)
+
+	for cur, sum := range sums {
+		m, exact, err := p.Converter.Convert(sum, cur, p.Currency, at)
+		if err != nil {
+			approximate = true
+			continue
+		}
+
+		total += m
+		approximate = approximate || !exact
+	}
+
+	for ticker, amount := range SumHoldings(p.Assets, p.To) {
+		m, exact, err := p.Converter.Value(amount, ticker, p.Currency, at)
+		if err != nil {
+			approximate = true
+			continue
+		}
+
+		total += m
+		approximate = approximate || !exact
+	}
+
+	return total, approximate
+}
+
+// MonthStart returns the first day of month of t.
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
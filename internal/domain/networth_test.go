@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalcNetWorth(t *testing.T) {
+	conv := NewConverter([]ExchangeRate{
+		{Date: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), Base: "USD", Quote: "RUB", Rate: 90},
+	})
+
+	p := NetWorthParams{
+		To:       time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		Currency: "RUB",
+		Accounts: []Account{
+			{ID: "cash", Currency: "RUB", OpeningBalance: 100000},
+			{ID: "usd", Currency: "USD", OpeningBalance: 10000},
+		},
+		Movements: []Movement{
+			{AccountID: "cash", Currency: "RUB", Money: -50000, OccuredAt: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)},
+			{AccountID: "cash", Currency: "RUB", Money: -10000, OccuredAt: time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)},
+		},
+		Debts: []Debt{
+			{Currency: "RUB", Money: 20000, OccuredAt: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		},
+		Converter: conv,
+	}
+
+	m, approximate := CalcNetWorth(p)
+	require.False(t, approximate)
+	require.EqualValues(t, 100000-50000+20000+900000, m)
+
+	nw := NetWorth{
+		Currency: "RUB",
+		History: []NetWorthSnapshot{
+			{Month: time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), Money: 500000},
+			{Month: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Money: m},
+		},
+	}
+
+	change, ok := nw.Change(1)
+	require.True(t, ok)
+	require.EqualValues(t, m-500000, change)
+
+	_, ok = nw.Change(6)
+	require.False(t, ok)
+}
@@ -1,3 +1,130 @@
 package domain
 
-type Operation struct{}
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+type OperationType string
+
+const (
+	// OperationTypeRegular is income or expense.
+	OperationTypeRegular OperationType = "REGULAR"
+	// OperationTypeTransfer moves money between accounts of user, it is not income nor expense.
+	OperationTypeTransfer OperationType = "TRANSFER"
+	// OperationTypeExchange sells money in one currency and buys in another, it is not income nor expense.
+	OperationTypeExchange OperationType = "EXCHANGE"
+	// OperationTypeAdjustment corrects account balance to the real one on reconciliation,
+	// it is not income nor expense.
+	OperationTypeAdjustment OperationType = "ADJUSTMENT"
+)
+
+func (t OperationType) String() string { return string(t) }
+
+var (
+	ErrTransferSameAccount = errors.New("transfer accounts must be different")
+	ErrTransferAmount      = errors.New("transfer amounts must be positive and equal for accounts in the same currency")
+)
+
+type Operation struct {
+	ID        string
+	UID       int64
+	Type      OperationType
+	AccountID string
+	CatID     string
+	Name      string
+	Currency  string
+	Money     money.Money
+	OccuredAt time.Time
+	CreatedAt time.Time
+	// DuplicateKept is true if user confirmed that operation is not a duplicate of a similar one.
+	DuplicateKept bool
+}
+
+// IsExpense returns true if operation money is negative.
+func (o Operation) IsExpense() bool {
+	return o.Money < 0
+}
+
+// AccountTransfer is a transfer of money between accounts of user.
+// Amounts differ only if accounts are in different currencies.
+type AccountTransfer struct {
+	From      Account
+	To        Account
+	Money     money.Money // positive, taken from account
+	ToMoney   money.Money // positive, put to account
+	OccuredAt time.Time
+}
+
+func (t AccountTransfer) Validate() error {
+	if t.From.ID == t.To.ID {
+		return ErrTransferSameAccount
+	}
+
+	if t.Money <= 0 || t.ToMoney <= 0 {
+		return ErrTransferAmount
+	}
+
+	if t.From.Currency == t.To.Currency && t.Money != t.ToMoney {
+		return ErrTransferAmount
+	}
+
+	return nil
+}
+
+// NormalizeOperationName lowers operation name and collapses whitespaces,
+// so "Netflix " and "netflix" are treated as the same operation.
+func NormalizeOperationName(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// ExportedOperation is operation of any type with names of its category and accounts.
+type ExportedOperation struct {
+	Operation
+	Category   string // empty if operation has no category
+	Account    string
+	ToAccount  string // empty if operation is not a transfer or exchange
+	ToCurrency string
+	ToMoney    money.Money
+}
+
+// Kind returns "expense" or "income" for regular operations and lower cased type for the rest.
+func (o ExportedOperation) Kind() string {
+	switch {
+	case o.Type != OperationTypeRegular:
+		return strings.ToLower(o.Type.String())
+	case o.IsExpense():
+		return "expense"
+	default:
+		return "income"
+	}
+}
+
+// ImportedOperation is income or expense read from file of bank or other finance software.
+type ImportedOperation struct {
+	Name      string
+	Category  string // empty if file has no category of operation
+	Currency  string
+	Money     money.Money
+	OccuredAt time.Time
+	// ExternalID is id of operation in bank statement, operations with the same id are imported once.
+	// Empty if file has no operation ids.
+	ExternalID string
+}
+
+// IsExpense returns true if operation money is negative.
+func (o ImportedOperation) IsExpense() bool {
+	return o.Money < 0
+}
+
+// CatType returns type of category the operation belongs to.
+func (o ImportedOperation) CatType() CatType {
+	if o.IsExpense() {
+		return CatTypeExpenses
+	}
+
+	return CatTypeIncome
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountTransferValidate(t *testing.T) {
+	card := Account{ID: "1", Currency: "RUB"}
+	cash := Account{ID: "2", Currency: "RUB"}
+	usd := Account{ID: "3", Currency: "USD"}
+
+	require.NoError(t, AccountTransfer{From: card, To: cash, Money: 100, ToMoney: 100}.Validate())
+	require.NoError(t, AccountTransfer{From: usd, To: card, Money: 100, ToMoney: 9000}.Validate())
+	require.ErrorIs(t, AccountTransfer{From: card, To: card, Money: 100, ToMoney: 100}.Validate(), ErrTransferSameAccount)
+	require.ErrorIs(t, AccountTransfer{From: card, To: cash, Money: 100, ToMoney: 90}.Validate(), ErrTransferAmount)
+	require.ErrorIs(t, AccountTransfer{From: usd, To: card, Money: 0, ToMoney: 9000}.Validate(), ErrTransferAmount)
+}
+
+func TestCurrencyExchange(t *testing.T) {
+	e := CurrencyExchange{
+		From:    Account{ID: "1", Currency: "RUB"},
+		To:      Account{ID: "2", Currency: "USD"},
+		Money:   9200000,
+		ToMoney: 100000,
+	}
+
+	require.NoError(t, e.Validate())
+	require.InDelta(t, 92, e.Rate(), 1e-9)
+
+	percent, loss := e.Spread(91.2829)
+	require.InDelta(t, 0.7855, percent, 1e-4)
+	require.EqualValues(t, 71710, loss)
+
+	e.To.Currency = "RUB"
+	require.ErrorIs(t, e.Validate(), ErrExchangeSameCurrency)
+}
@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// ExchangeRate is a price of 1 base currency unit in quote currency on date.
+type ExchangeRate struct {
+	Date   time.Time
+	Base   string
+	Quote  string
+	Rate   float64
+	Source string
+}
+
+type ratePair struct {
+	base  string
+	quote string
+}
+
+// Converter converts money between currencies using rate on the date of conversion,
+// if there is no rate on the date nearest earlier one is used and conversion is approximate.
+type Converter struct {
+	rates      map[ratePair][]ExchangeRate // sorted by date
+	currencies []string
+}
+
+func NewConverter(rates []ExchangeRate) *Converter {
+	c := &Converter{rates: make(map[ratePair][]ExchangeRate)}
+
+	for _, r := range rates {
+		if r.Rate <= 0 || r.Base == r.Quote {
+			continue
+		}
+
+		p := ratePair{base: r.Base, quote: r.Quote}
+		c.rates[p] = append(c.rates[p], r)
+
+		for _, cur := range [...]string{r.Base, r.Quote} {
+			if !slices.Contains(c.currencies, cur) {
+				c.currencies = append(c.currencies, cur)
+			}
+		}
+	}
+
+	for _, rr := range c.rates {
+		slices.SortFunc(rr, func(a, b ExchangeRate) int { return a.Date.Compare(b.Date) })
+	}
+
+	slices.Sort(c.currencies)
+
+	return c
+}
+
+// Rate returns rate of from currency in to currency on date and true if rate is exact.
+// Inverse rates and cross rates through a third currency are used if there is no direct rate.
+func (c *Converter) Rate(from, to string, at time.Time) (float64, bool, error) {
+	if from == to {
+		return 1, true, nil
+	}
+
+	if rate, exact, ok := c.pairRate(from, to, at); ok {
+		return rate, exact, nil
+	}
+
+	var (
+		best      float64
+		bestExact bool
+		found     bool
+	)
+
+	for _, cur := range c.currencies {
+		if cur == from || cur == to {
+			continue
+		}
+
+		r1, exact1, ok := c.pairRate(from, cur, at)
+		if !ok {
+			continue
+		}
+
+		r2, exact2, ok := c.pairRate(cur, to, at)
+		if !ok {
+			continue
+		}
+
+		// exact cross rate is preferred, otherwise the first one found is used
+		if !found || (!bestExact && exact1 && exact2) {
+			best, bestExact, found = r1*r2, exact1 && exact2, true
+		}
+	}
+
+	if !found {
+		return 0, false, ErrRateNotFound
+	}
+
+	return best, bestExact, nil
+}
+
+// pairRate returns direct or inverse rate of the pair, exact rate is preferred.
+func (c *Converter) pairRate(from, to string, at time.Time) (float64, bool, bool) {
+	direct, directExact, directOK := c.nearest(ratePair{base: from, quote: to}, at)
+	inverse, inverseExact, inverseOK := c.nearest(ratePair{base: to, quote: from}, at)
+
+	switch {
+	case directOK && (directExact || !inverseExact):
+		return direct.Rate, directExact, true
+	case inverseOK:
+		return 1 / inverse.Rate, inverseExact, true
+	default:
+		return 0, false, false
+	}
+}
+
+// nearest returns rate on date or nearest earlier one.
+func (c *Converter) nearest(p ratePair, at time.Time) (ExchangeRate, bool, bool) {
+	rates := c.rates[p]
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+
+	i, found := slices.BinarySearchFunc(rates, day, func(r ExchangeRate, t time.Time) int {
+		return time.Date(r.Date.Year(), r.Date.Month(), r.Date.Day(), 0, 0, 0, 0, time.UTC).Compare(t)
+	})
+	if found {
+		return rates[i], true, true
+	}
+
+	if i == 0 {
+		return ExchangeRate{}, false, false
+	}
+
+	return rates[i-1], false, true
+}
+
+// Convert converts money to currency using rate on date, returns false if conversion is approximate.
+func (c *Converter) Convert(m money.Money, from, to string, at time.Time) (money.Money, bool, error) {
+	rate, exact, err := c.Rate(from, to, at)
+	if err != nil {
+		return 0, false, err
+	}
+
+	res, err := money.FromFloat(m.Float(from)*rate, to)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return res, exact, nil
+}
+
+// Value values amount of asset in currency using rate of ticker on date, returns false if valuation is approximate.
+func (c *Converter) Value(amount money.Decimal, ticker, to string, at time.Time) (money.Money, bool, error) {
+	rate, exact, err := c.Rate(ticker, to, at)
+	if err != nil {
+		return 0, false, err
+	}
+
+	res, err := money.FromFloat(amount.Float()*rate, to)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return res, exact, nil
+}
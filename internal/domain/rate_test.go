@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConverter(t *testing.T) {
+	conv := NewConverter([]ExchangeRate{
+		{Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Base: "USD", Quote: "RUB", Rate: 90},
+		{Date: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), Base: "USD", Quote: "RUB", Rate: 92},
+		{Date: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), Base: "EUR", Quote: "RUB", Rate: 100},
+	})
+
+	m, exact, err := conv.Convert(1000, "USD", "RUB", time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.True(t, exact)
+	require.EqualValues(t, 92000, m)
+
+	// weekend falls back to friday rate
+	m, exact, err = conv.Convert(1000, "USD", "RUB", time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.False(t, exact)
+	require.EqualValues(t, 90000, m)
+
+	// inverse
+	m, exact, err = conv.Convert(9200, "RUB", "USD", time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.True(t, exact)
+	require.EqualValues(t, 100, m)
+
+	// cross through RUB
+	m, exact, err = conv.Convert(1000, "EUR", "USD", time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.True(t, exact)
+	require.EqualValues(t, 1087, m)
+
+	// currencies with different exponents, 10 USD is 1500 JPY
+	conv = NewConverter([]ExchangeRate{
+		{Date: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), Base: "USD", Quote: "JPY", Rate: 150},
+	})
+
+	m, _, err = conv.Convert(1000, "USD", "JPY", time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.EqualValues(t, 1500, m)
+
+	_, _, err = conv.Convert(1000, "USD", "RUB", time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC))
+	require.ErrorIs(t, err, ErrRateNotFound)
+
+	m, exact, err = conv.Convert(1000, "GBP", "GBP", time.Now())
+	require.NoError(t, err)
+	require.True(t, exact)
+	require.EqualValues(t, 1000, m)
+}
+
+func TestCalcReport(t *testing.T) {
+	conv := NewConverter([]ExchangeRate{
+		{Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Base: "USD", Quote: "RUB", Rate: 90},
+	})
+
+	r := CalcReport([]Operation{
+		{CatID: "food", Currency: "RUB", Money: -50000, OccuredAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{CatID: "food", Currency: "USD", Money: -1000, OccuredAt: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{CatID: "salary", Currency: "RUB", Money: 1000000, OccuredAt: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{CatID: "food", Currency: "GEL", Money: -1000, OccuredAt: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+	}, conv, "RUB", time.Time{}, time.Time{})
+
+	require.EqualValues(t, 1000000, r.Income)
+	require.EqualValues(t, -140000, r.Expenses)
+	require.True(t, r.Approximate)
+	require.Equal(t, []string{"GEL"}, r.Unconverted)
+	require.Equal(t, []CategoryTotal{
+		{CatID: "salary", Money: 1000000},
+		{CatID: "food", Money: -140000},
+	}, r.Categories)
+
+	r.AddDrift([]Reconciliation{
+		{Currency: "RUB", Recorded: 100000, Actual: 90000, CreatedAt: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{Currency: "USD", Recorded: 1000, Actual: 1100, CreatedAt: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+	}, conv)
+	require.EqualValues(t, -10000+9000, r.Drift)
+	require.Equal(t, 2, r.Reconciliations)
+}
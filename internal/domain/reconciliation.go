@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+// AdjustmentName is name of operation which corrects account balance on reconciliation.
+const AdjustmentName = "Balance adjustment"
+
+// Reconciliation is a point where user confirmed real balance of account.
+type Reconciliation struct {
+	ID           string
+	UID          int64
+	AccountID    string
+	Currency     string
+	Recorded     money.Money // balance calculated from operations
+	Actual       money.Money // balance provided by user
+	AdjustmentID string      // empty if adjustment is not posted
+	CreatedAt    time.Time
+}
+
+// Difference returns money missing in recorded history, negative if recorded balance is bigger than actual.
+func (r Reconciliation) Difference() money.Money {
+	return r.Actual - r.Recorded
+}
@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+// CategoryTotal is sum of operations in category.
+type CategoryTotal struct {
+	CatID string
+	Money money.Money
+}
+
+// Report is income and expenses of user for a period converted to a single currency.
+type Report struct {
+	From       time.Time
+	To         time.Time
+	Currency   string
+	Income     money.Money
+	Expenses   money.Money // negative
+	Categories []CategoryTotal
+	// Drift is sum of differences between actual and recorded balances found by reconciliations,
+	// it shows how much money was not recorded.
+	Drift           money.Money
+	Reconciliations int
+	// Assets is amount of every asset held at the end of period valued in report currency.
+	Assets []AssetValue
+	// Approximate is true if some operation was converted using rate of an earlier date.
+	Approximate bool
+	// Unconverted is list of currencies without any exchange rate, operations in them are not included.
+	Unconverted []string
+}
+
+// CalcReport sums up operations converted to currency using rate on operation date.
+// Categories are ordered by absolute sum descending.
+func CalcReport(ops []Operation, conv *Converter, currency string, from, to time.Time) Report {
+	r := Report{
+		From:     from,
+		To:       to,
+		Currency: currency,
+	}
+
+	totals := make(map[string]money.Money)
+
+	for _, op := range ops {
+		m, exact, err := conv.Convert(op.Money, op.Currency, currency, op.OccuredAt)
+		if err != nil {
+			if !slices.Contains(r.Unconverted, op.Currency) {
+				r.Unconverted = append(r.Unconverted, op.Currency)
+			}
+
+			continue
+		}
+
+		if !exact {
+			r.Approximate = true
+		}
+
+		if m > 0 {
+			r.Income += m
+		} else {
+			r.Expenses += m
+		}
+
+		if _, ok := totals[op.CatID]; !ok {
+			r.Categories = append(r.Categories, CategoryTotal{CatID: op.CatID})
+		}
+
+		totals[op.CatID] += m
+	}
+
+	for i := range r.Categories {
+		r.Categories[i].Money = totals[r.Categories[i].CatID]
+	}
+
+	slices.SortStableFunc(r.Categories, func(a, b CategoryTotal) int {
+		switch {
+		case a.Money.Abs() > b.Money.Abs():
+			return -1
+		case a.Money.Abs() < b.Money.Abs():
+			return 1
+		default:
+			return strings.Compare(a.CatID, b.CatID)
+		}
+	})
+
+	slices.Sort(r.Unconverted)
+
+	return r
+}
+
+// AddDrift adds differences of reconciliations converted to report currency by rate on reconciliation date.
+func (r *Report) AddDrift(recs []Reconciliation, conv *Converter) {
+	for _, rec := range recs {
+		m, exact, err := conv.Convert(rec.Difference(), rec.Currency, r.Currency, rec.CreatedAt)
+		if err != nil {
+			if !slices.Contains(r.Unconverted, rec.Currency) {
+				r.Unconverted = append(r.Unconverted, rec.Currency)
+				slices.Sort(r.Unconverted)
+			}
+
+			continue
+		}
+
+		if !exact {
+			r.Approximate = true
+		}
+
+		r.Drift += m
+		r.Reconciliations++
+	}
+}
+
+// AddAssets adds assets held at the end of report period valued by rate on the last day of period.
+// Assets without rate are reported as unconverted.
+func (r *Report) AddAssets(movements []AssetMovement, conv *Converter) {
+	holdings := SumHoldings(movements, r.To)
+	tickers := make([]string, 0, len(holdings))
+
+	for ticker := range holdings {
+		tickers = append(tickers, ticker)
+	}
+
+	slices.Sort(tickers)
+
+	for _, ticker := range tickers {
+		m, exact, err := conv.Value(holdings[ticker], ticker, r.Currency, r.To.AddDate(0, 0, -1))
+		if err != nil {
+			if !slices.Contains(r.Unconverted, ticker) {
+				r.Unconverted = append(r.Unconverted, ticker)
+				slices.Sort(r.Unconverted)
+			}
+
+			continue
+		}
+
+		if !exact {
+			r.Approximate = true
+		}
+
+		r.Assets = append(r.Assets, AssetValue{Ticker: ticker, Amount: holdings[ticker], Money: m})
+	}
+}
@@ -0,0 +1,323 @@
+package domain
+
+import (
+	"errors"
+	"math/bits"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+// SplitSelf is a participant name of the user who records the split.
+const SplitSelf = ""
+
+var (
+	ErrSplitNoParticipants = errors.New("split must have at least one participant except user")
+	ErrSplitExceedsTotal   = errors.New("exact shares exceed split total")
+	ErrSplitNotAllocated   = errors.New("split total is not allocated to participants")
+	ErrSplitInvalidWeight  = errors.New("split share weight must be positive")
+)
+
+// SplitShare is a part of split bill which is owed by participant.
+// Share is either exact or calculated from the rest of total according to weight.
+type SplitShare struct {
+	Participant string
+	Weight      int64
+	Exact       bool
+	Money       money.Money // positive
+}
+
+// Split is a bill paid by payer and shared between participants.
+type Split struct {
+	ID          string
+	UID         int64
+	OperationID string
+	Name        string
+	Currency    string
+	Payer       string
+	Money       money.Money // positive
+	Shares      []SplitShare
+	OccuredAt   time.Time
+}
+
+// Allocate calculates money of not exact shares, so all shares sum up to split money.
+// The rest of exact shares is allocated by weights with money.Allocate.
+func (s *Split) Allocate() error {
+	var (
+		participants int
+		rest         = s.Money
+		weights      = make([]int64, 0, len(s.Shares))
+		weighted     = make([]int, 0, len(s.Shares))
+	)
+
+	for i, share := range s.Shares {
+		if share.Participant != SplitSelf {
+			participants++
+		}
+
+		if share.Exact {
+			rest -= share.Money
+			continue
+		}
+
+		if share.Weight <= 0 {
+			return ErrSplitInvalidWeight
+		}
+
+		weights = append(weights, share.Weight)
+		weighted = append(weighted, i)
+	}
+
+	if participants == 0 {
+		return ErrSplitNoParticipants
+	}
+
+	if rest < 0 {
+		return ErrSplitExceedsTotal
+	}
+
+	if len(weighted) == 0 {
+		if rest != 0 {
+			return ErrSplitNotAllocated
+		}
+
+		return nil
+	}
+
+	parts, err := rest.Allocate(weights...)
+	if err != nil {
+		return err
+	}
+
+	for j, i := range weighted {
+		s.Shares[i].Money = parts[j]
+	}
+
+	return nil
+}
+
+// OwnShare returns money owed by user.
+func (s Split) OwnShare() money.Money {
+	for _, share := range s.Shares {
+		if share.Participant == SplitSelf {
+			return share.Money
+		}
+	}
+
+	return 0
+}
+
+// Transfer is a payment which must be made to settle up splits.
+type Transfer struct {
+	From     string
+	To       string
+	Currency string
+	Money    money.Money
+}
+
+// SettleUp returns the minimum number of transfers which settle up balances of all split participants
+// in every currency. Participants are divided into the most groups with zero sum of balances and every group
+// of k participants is settled by k-1 transfers, so n participants are settled by n minus number of groups
+// transfers. Currencies with more than maxExactSettleUp participants are settled greedily in one group.
+func SettleUp(splits []Split) []Transfer {
+	balances := make(map[string]map[string]money.Money)
+	currencies := make([]string, 0)
+
+	for _, s := range splits {
+		if _, ok := balances[s.Currency]; !ok {
+			balances[s.Currency] = make(map[string]money.Money)
+			currencies = append(currencies, s.Currency)
+		}
+
+		balances[s.Currency][normalizeParticipant(s.Payer)] += s.Money
+
+		for _, share := range s.Shares {
+			balances[s.Currency][normalizeParticipant(share.Participant)] -= share.Money
+		}
+	}
+
+	slices.Sort(currencies)
+
+	transfers := make([]Transfer, 0)
+
+	for _, cur := range currencies {
+		transfers = append(transfers, settleUp(cur, balances[cur])...)
+	}
+
+	return transfers
+}
+
+// maxExactSettleUp is max number of participants with non-zero balance in one currency which are divided into
+// zero sum groups, search takes 2^n steps.
+const maxExactSettleUp = 16
+
+type participantBalance struct {
+	name  string
+	money money.Money // positive
+}
+
+func settleUp(currency string, balances map[string]money.Money) []Transfer {
+	names := make([]string, 0, len(balances))
+
+	for name, m := range balances {
+		if m != 0 {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	amounts := make([]money.Money, len(names))
+	for i, name := range names {
+		amounts[i] = balances[name]
+	}
+
+	groups := [][]int{make([]int, len(names))}
+	for i := range names {
+		groups[0][i] = i
+	}
+
+	if len(names) <= maxExactSettleUp {
+		groups = zeroSumGroups(amounts)
+	}
+
+	transfers := make([]Transfer, 0, len(names))
+
+	for _, g := range groups {
+		var debtors, creditors []participantBalance
+
+		for _, i := range g {
+			if amounts[i] > 0 {
+				creditors = append(creditors, participantBalance{name: names[i], money: amounts[i]})
+			} else {
+				debtors = append(debtors, participantBalance{name: names[i], money: -amounts[i]})
+			}
+		}
+
+		transfers = append(transfers, settleGreedily(currency, debtors, creditors)...)
+	}
+
+	slices.SortFunc(transfers, func(x, y Transfer) int {
+		if c := strings.Compare(x.From, y.From); c != 0 {
+			return c
+		}
+
+		return strings.Compare(x.To, y.To)
+	})
+
+	return transfers
+}
+
+// zeroSumGroups divides indexes of amounts which sum is zero into the most groups with zero sum.
+// Partition is found by dynamic programming over subsets: the best number of groups of subset is the best number
+// of its subset without one element plus one if the subset itself has zero sum.
+func zeroSumGroups(amounts []money.Money) [][]int {
+	full := 1<<len(amounts) - 1
+	sums := make([]money.Money, full+1)
+	best := make([]int, full+1)
+
+	for mask := 1; mask <= full; mask++ {
+		sums[mask] = sums[mask&(mask-1)] + amounts[bits.TrailingZeros(uint(mask))]
+
+		for i := range amounts {
+			if mask&(1<<i) != 0 {
+				best[mask] = max(best[mask], best[mask&^(1<<i)])
+			}
+		}
+
+		if sums[mask] == 0 {
+			best[mask]++
+		}
+	}
+
+	var groups [][]int
+
+	// subsets with zero sum on the path from full set to empty one bound the groups
+	for mask, last := full, full; mask != 0; {
+		want := best[mask]
+		if sums[mask] == 0 {
+			want--
+		}
+
+		for i := range amounts {
+			next := mask &^ (1 << i)
+			if mask&(1<<i) == 0 || best[next] != want {
+				continue
+			}
+
+			if sums[next] == 0 {
+				var g []int
+
+				for j := range amounts {
+					if (last&^next)&(1<<j) != 0 {
+						g = append(g, j)
+					}
+				}
+
+				groups = append(groups, g)
+				last = next
+			}
+
+			mask = next
+
+			break
+		}
+	}
+
+	return groups
+}
+
+// settleGreedily settles balances from the biggest debtor to the biggest creditor,
+// group of k participants with zero sum is settled by k-1 transfers at most.
+func settleGreedily(currency string, debtors, creditors []participantBalance) []Transfer {
+	sortBalances(debtors)
+	sortBalances(creditors)
+
+	transfers := make([]Transfer, 0, len(debtors)+len(creditors))
+
+	for i, j := 0, 0; i < len(debtors) && j < len(creditors); {
+		if debtors[i].money == 0 {
+			i++
+			continue
+		}
+
+		if creditors[j].money == 0 {
+			j++
+			continue
+		}
+
+		m := min(debtors[i].money, creditors[j].money)
+
+		transfers = append(transfers, Transfer{
+			From:     debtors[i].name,
+			To:       creditors[j].name,
+			Currency: currency,
+			Money:    m,
+		})
+
+		debtors[i].money -= m
+		creditors[j].money -= m
+	}
+
+	return transfers
+}
+
+// sortBalances sorts balances by money descending and by name to be deterministic.
+func sortBalances(b []participantBalance) {
+	slices.SortFunc(b, func(x, y participantBalance) int {
+		switch {
+		case x.money > y.money:
+			return -1
+		case x.money < y.money:
+			return 1
+		default:
+			return strings.Compare(x.name, y.name)
+		}
+	})
+}
+
+func normalizeParticipant(s string) string {
+	return strings.ToLower(s)
+}
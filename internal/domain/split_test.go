@@ -0,0 +1,173 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitAllocateEqual(t *testing.T) {
+	s := Split{
+		Money: 100000,
+		Shares: []SplitShare{
+			{Participant: SplitSelf, Weight: 1},
+			{Participant: "@a", Weight: 1},
+			{Participant: "@b", Weight: 1},
+		},
+	}
+
+	require.NoError(t, s.Allocate())
+	require.EqualValues(t, 33334, s.Shares[0].Money)
+	require.EqualValues(t, 33333, s.Shares[1].Money)
+	require.EqualValues(t, 33333, s.Shares[2].Money)
+	require.EqualValues(t, 33334, s.OwnShare())
+}
+
+func TestSplitAllocateWeights(t *testing.T) {
+	s := Split{
+		Money: 1001,
+		Shares: []SplitShare{
+			{Participant: SplitSelf, Weight: 1},
+			{Participant: "@a", Weight: 2},
+			{Participant: "@b", Weight: 3},
+		},
+	}
+
+	require.NoError(t, s.Allocate())
+	// 166.83, 333.67, 500.5
+	require.EqualValues(t, 167, s.Shares[0].Money)
+	require.EqualValues(t, 334, s.Shares[1].Money)
+	require.EqualValues(t, 500, s.Shares[2].Money)
+}
+
+func TestSplitAllocateExact(t *testing.T) {
+	s := Split{
+		Money: 300000,
+		Shares: []SplitShare{
+			{Participant: SplitSelf, Weight: 1},
+			{Participant: "@a", Exact: true, Money: 100000},
+			{Participant: "@b", Exact: true, Money: 50000},
+		},
+	}
+
+	require.NoError(t, s.Allocate())
+	require.EqualValues(t, 150000, s.OwnShare())
+}
+
+func TestSplitAllocateErrors(t *testing.T) {
+	s := Split{
+		Money:  1000,
+		Shares: []SplitShare{{Participant: SplitSelf, Weight: 1}},
+	}
+	require.ErrorIs(t, s.Allocate(), ErrSplitNoParticipants)
+
+	s = Split{
+		Money: 1000,
+		Shares: []SplitShare{
+			{Participant: SplitSelf, Weight: 1},
+			{Participant: "@a", Exact: true, Money: 2000},
+		},
+	}
+	require.ErrorIs(t, s.Allocate(), ErrSplitExceedsTotal)
+
+	s = Split{
+		Money: 1000,
+		Shares: []SplitShare{
+			{Participant: SplitSelf, Exact: true, Money: 100},
+			{Participant: "@a", Exact: true, Money: 100},
+		},
+	}
+	require.ErrorIs(t, s.Allocate(), ErrSplitNotAllocated)
+}
+
+func TestSettleUp(t *testing.T) {
+	splits := []Split{
+		{
+			Currency: "RUB",
+			Payer:    SplitSelf,
+			Money:    3000,
+			Shares: []SplitShare{
+				{Participant: SplitSelf, Money: 1000},
+				{Participant: "@a", Money: 1000},
+				{Participant: "@b", Money: 1000},
+			},
+		},
+		{
+			Currency: "RUB",
+			Payer:    "@a",
+			Money:    1500,
+			Shares: []SplitShare{
+				{Participant: SplitSelf, Money: 500},
+				{Participant: "@A", Money: 500},
+				{Participant: "@b", Money: 500},
+			},
+		},
+	}
+
+	// user: +2000 -500 = 1500, @a: -1000 +1000 = 0, @b: -1500
+	require.Equal(t, []Transfer{
+		{From: "@b", To: SplitSelf, Currency: "RUB", Money: 1500},
+	}, SettleUp(splits))
+}
+
+func TestSettleUpGreedy(t *testing.T) {
+	splits := []Split{
+		{
+			Currency: "EUR",
+			Payer:    SplitSelf,
+			Money:    900,
+			Shares: []SplitShare{
+				{Participant: "@a", Money: 400},
+				{Participant: "@b", Money: 300},
+				{Participant: "@c", Money: 200},
+			},
+		},
+		{
+			Currency: "EUR",
+			Payer:    "@c",
+			Money:    300,
+			Shares: []SplitShare{
+				{Participant: "@a", Money: 300},
+			},
+		},
+	}
+
+	// user: +900, @a: -700, @b: -300, @c: +100
+	require.Equal(t, []Transfer{
+		{From: "@a", To: SplitSelf, Currency: "EUR", Money: 700},
+		{From: "@b", To: SplitSelf, Currency: "EUR", Money: 200},
+		{From: "@b", To: "@c", Currency: "EUR", Money: 100},
+	}, SettleUp(splits))
+}
+
+func TestSettleUpZeroSumGroups(t *testing.T) {
+	splits := []Split{
+		{
+			Currency: "RUB",
+			Payer:    SplitSelf,
+			Money:    500,
+			Shares: []SplitShare{
+				{Participant: "@b", Money: 300},
+				{Participant: "@c", Money: 200},
+			},
+		},
+		{
+			Currency: "RUB",
+			Payer:    "@e",
+			Money:    600,
+			Shares: []SplitShare{
+				{Participant: "@a", Money: 400},
+				{Participant: "@d", Money: 200},
+			},
+		},
+	}
+
+	// user: +500, @e: +600, @a: -400, @b: -300, @c: -200, @d: -200,
+	// greedy matching of the biggest balances needs 5 transfers
+	require.Equal(t, []Transfer{
+		{From: "@a", To: "@e", Currency: "RUB", Money: 400},
+		{From: "@b", To: SplitSelf, Currency: "RUB", Money: 300},
+		{From: "@c", To: SplitSelf, Currency: "RUB", Money: 200},
+		{From: "@d", To: "@e", Currency: "RUB", Money: 200},
+	}, SettleUp(splits))
+}
@@ -0,0 +1,179 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+const (
+	minSubscriptionCharges  = 3
+	minSubscriptionInterval = 7  // days
+	subscriptionAmountDelta = 25 // percent from median amount
+)
+
+const day = time.Hour * 24
+
+type Subscription struct {
+	ID       string
+	UID      int64
+	CatID    string
+	Name     string
+	Currency string
+	Money    money.Money // last charged amount, negative as any expense
+	Interval int         // days between charges
+	NextAt   time.Time
+
+	// PrevMoney is amount charged before the last one, zero if unknown.
+	PrevMoney money.Money
+	// Tracked is true if subscription is saved by user.
+	Tracked bool
+}
+
+// MonthlyCost returns estimated positive cost of the subscription per 30 days.
+func (s Subscription) MonthlyCost() money.Money {
+	return s.cost(30)
+}
+
+// YearlyCost returns estimated positive cost of the subscription per 365 days.
+func (s Subscription) YearlyCost() money.Money {
+	return s.cost(365)
+}
+
+func (s Subscription) cost(days int) money.Money {
+	if s.Interval <= 0 {
+		return 0
+	}
+
+	return money.Money(int64(-s.Money) * int64(days) / int64(s.Interval))
+}
+
+// PriceIncreased returns true if the last charge is bigger than the previous one.
+func (s Subscription) PriceIncreased() bool {
+	return s.PrevMoney != 0 && s.Money < s.PrevMoney
+}
+
+// DetectSubscriptions finds expenses which are repeated with similar amount at regular intervals.
+// Operations with the same normalized name and currency are grouped together,
+// subscriptions which were not charged for longer than its interval since now are skipped.
+func DetectSubscriptions(ops []Operation, now time.Time) []Subscription {
+	groups := make(map[string][]Operation)
+	keys := make([]string, 0)
+
+	for _, op := range ops {
+		if !op.IsExpense() {
+			continue
+		}
+
+		key := NormalizeOperationName(op.Name) + "|" + op.Currency
+
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		groups[key] = append(groups[key], op)
+	}
+
+	subs := make([]Subscription, 0)
+
+	for _, key := range keys {
+		if sub, ok := detectSubscription(groups[key], now); ok {
+			subs = append(subs, sub)
+		}
+	}
+
+	slices.SortStableFunc(subs, func(a, b Subscription) int {
+		return a.NextAt.Compare(b.NextAt)
+	})
+
+	return subs
+}
+
+func detectSubscription(ops []Operation, now time.Time) (Subscription, bool) {
+	slices.SortStableFunc(ops, func(a, b Operation) int {
+		return a.OccuredAt.Compare(b.OccuredAt)
+	})
+
+	// only one charge per day is counted
+	ops = slices.CompactFunc(ops, func(a, b Operation) bool {
+		return daysBetween(a.OccuredAt, b.OccuredAt) == 0
+	})
+
+	if len(ops) < minSubscriptionCharges {
+		return Subscription{}, false
+	}
+
+	intervals := make([]int, 0, len(ops)-1)
+	amounts := make([]int64, 0, len(ops))
+
+	for i, op := range ops {
+		amounts = append(amounts, int64(-op.Money))
+
+		if i > 0 {
+			intervals = append(intervals, daysBetween(ops[i-1].OccuredAt, op.OccuredAt))
+		}
+	}
+
+	interval := median(intervals)
+	if interval < minSubscriptionInterval {
+		return Subscription{}, false
+	}
+
+	tolerance := max(3, interval/10)
+
+	for _, d := range intervals {
+		if abs(d-interval) > tolerance {
+			return Subscription{}, false
+		}
+	}
+
+	amount := median(amounts)
+
+	for _, a := range amounts {
+		if abs(a-amount)*100 > amount*subscriptionAmountDelta {
+			return Subscription{}, false
+		}
+	}
+
+	last := ops[len(ops)-1]
+	nextAt := last.OccuredAt.AddDate(0, 0, interval)
+
+	// subscription was canceled
+	if daysBetween(nextAt, now) > tolerance {
+		return Subscription{}, false
+	}
+
+	return Subscription{
+		UID:       last.UID,
+		CatID:     last.CatID,
+		Name:      last.Name,
+		Currency:  last.Currency,
+		Money:     last.Money,
+		PrevMoney: ops[len(ops)-2].Money,
+		Interval:  interval,
+		NextAt:    nextAt,
+	}, true
+}
+
+func daysBetween(from, to time.Time) int {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+
+	return int(to.Sub(from) / day)
+}
+
+func median[T int | int64](s []T) T {
+	sorted := slices.Clone(s)
+	slices.Sort(sorted)
+
+	return sorted[len(sorted)/2]
+}
+
+func abs[T int | int64](n T) T {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+func newOperation(name string, m money.Money, occuredAt time.Time) Operation {
+	return Operation{Name: name, Currency: "RUB", Money: m, OccuredAt: occuredAt}
+}
+
+func TestDetectSubscriptions(t *testing.T) {
+	start := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)
+
+	ops := []Operation{
+		newOperation("Netflix", -59900, start),
+		newOperation("coffee", -35000, start),
+		newOperation("netflix ", -59900, start.AddDate(0, 0, 30)),
+		newOperation("coffee", -35000, start.AddDate(0, 0, 2)),
+		newOperation("netflix", -69900, start.AddDate(0, 0, 61)),
+		newOperation("coffee", -35000, start.AddDate(0, 0, 3)),
+		newOperation("salary", 10000000, start.AddDate(0, 0, 30)),
+		newOperation("netflix", -69900, start.AddDate(0, 0, 91)),
+	}
+
+	subs := DetectSubscriptions(ops, now)
+	require.Len(t, subs, 1)
+
+	sub := subs[0]
+	require.Equal(t, "netflix", sub.Name)
+	require.Equal(t, 30, sub.Interval)
+	require.EqualValues(t, -69900, sub.Money)
+	require.Equal(t, start.AddDate(0, 0, 121), sub.NextAt)
+	require.EqualValues(t, 69900, sub.MonthlyCost())
+	require.EqualValues(t, 850450, sub.YearlyCost())
+	require.False(t, sub.PriceIncreased())
+}
+
+func TestDetectSubscriptionsPriceIncreased(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	ops := []Operation{
+		newOperation("gym", -300000, start),
+		newOperation("gym", -300000, start.AddDate(0, 0, 31)),
+		newOperation("gym", -330000, start.AddDate(0, 0, 60)),
+	}
+
+	subs := DetectSubscriptions(ops, start.AddDate(0, 0, 70))
+	require.Len(t, subs, 1)
+	require.True(t, subs[0].PriceIncreased())
+	require.EqualValues(t, -300000, subs[0].PrevMoney)
+}
+
+func TestDetectSubscriptionsCanceled(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	ops := []Operation{
+		newOperation("spotify", -16900, start),
+		newOperation("spotify", -16900, start.AddDate(0, 1, 0)),
+		newOperation("spotify", -16900, start.AddDate(0, 2, 0)),
+	}
+
+	require.Empty(t, DetectSubscriptions(ops, start.AddDate(0, 6, 0)))
+}
+
+func TestDetectSubscriptionsIrregular(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	ops := []Operation{
+		newOperation("taxi", -50000, start),
+		newOperation("taxi", -50000, start.AddDate(0, 0, 9)),
+		newOperation("taxi", -50000, start.AddDate(0, 0, 40)),
+	}
+
+	require.Empty(t, DetectSubscriptions(ops, start.AddDate(0, 0, 45)))
+}
@@ -15,12 +15,17 @@ var supportedLanguages = []string{"en", "ru"}
 var (
 	ErrUnsupportedLanguage = errors.New("unsupported language")
 	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrInvalidPayDay       = errors.New("pay day must be between 1 and 31")
 )
 
 type User struct {
 	ID       int64
 	Currency string
 	Language string
+	PayDay   int
+
+	// AllowanceFooter is true if safe to spend today amount must be shown after each expense.
+	AllowanceFooter bool
 }
 
 func (u *User) Validate() error {
@@ -33,13 +38,23 @@ func (u *User) Validate() error {
 
 	code, _ := iso4217.ByName(u.Currency)
 	if code == 0 {
-		slog.Info(u.Currency, code)
+		slog.Info(u.Currency, "code", code)
 		return ErrUnsupportedCurrency
 	}
 
+	if u.PayDay < 1 || u.PayDay > 31 {
+		return ErrInvalidPayDay
+	}
+
 	return nil
 }
 
 func (u *User) IDString() string {
 	return strconv.FormatInt(u.ID, 10)
 }
+
+// IsCurrency returns true if s is ISO-4217 currency code.
+func IsCurrency(s string) bool {
+	code, _ := iso4217.ByName(s)
+	return code != 0
+}
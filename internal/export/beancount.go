@@ -0,0 +1,131 @@
+package export
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/ysomad/financer/internal/domain"
+)
+
+// beancountReplacer escapes narration string of beancount transaction.
+var beancountReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r", " ", "\n", " ")
+
+// BeancountWriter writes operations as beancount transactions. Operations are written in order of date,
+// so account is opened by open directive at date of its first transaction.
+// Hashtags of operation names are written as tags of transactions.
+type BeancountWriter struct {
+	w      *bufio.Writer
+	opened map[string]struct{}
+}
+
+func NewBeancountWriter(w io.Writer) *BeancountWriter {
+	return &BeancountWriter{
+		w:      bufio.NewWriter(w),
+		opened: make(map[string]struct{}),
+	}
+}
+
+func (w *BeancountWriter) Write(op domain.ExportedOperation) error {
+	var (
+		sb       strings.Builder
+		date     = op.OccuredAt.Format(dateLayout)
+		postings = journalPostings(op)
+		accounts = make([]string, len(postings))
+	)
+
+	for i, p := range postings {
+		accounts[i] = p.root + ":" + beancountAccount(p.account)
+
+		if _, ok := w.opened[accounts[i]]; !ok {
+			w.opened[accounts[i]] = struct{}{}
+			sb.WriteString(date + " open " + accounts[i] + "\n")
+		}
+	}
+
+	sb.WriteString(date + ` * "` + beancountReplacer.Replace(op.Name) + `"`)
+
+	for _, tag := range journalTags(op.Name) {
+		if tag = beancountTag(tag); tag != "" {
+			sb.WriteString(" #" + tag)
+		}
+	}
+
+	sb.WriteString("\n")
+
+	for i, p := range postings {
+		sb.WriteString("  " + accounts[i] + "  " + journalAmount(p.money, p.currency) + " " + beancountCommodity(p.currency))
+
+		if p.priceCurrency != "" {
+			sb.WriteString(" @@ " + journalAmount(p.price, p.priceCurrency) + " " + beancountCommodity(p.priceCurrency))
+		}
+
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+
+	_, err := w.w.WriteString(sb.String())
+
+	return err
+}
+
+// Flush writes buffered transactions to the underlying writer.
+func (w *BeancountWriter) Flush() error {
+	return w.w.Flush()
+}
+
+// beancountAccount returns account name component which starts with capital letter or digit and
+// contains only letters, digits and dashes, e.g. "Food-Delivery" of "food: delivery".
+func beancountAccount(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	if len(words) == 0 {
+		return journalUncategorized
+	}
+
+	for i, word := range words {
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		words[i] = string(r)
+	}
+
+	return strings.Join(words, "-")
+}
+
+// beancountTag returns tag with ASCII letters, digits, dashes and underscores only.
+func beancountTag(tag string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_') {
+			return r
+		}
+
+		return -1
+	}, tag)
+}
+
+// beancountCommodity returns upper cased currency with characters allowed in beancount commodities,
+// commodity of one character is padded because commodities have at least two characters.
+func beancountCommodity(currency string) string {
+	c := strings.Map(func(r rune) rune {
+		r = unicode.ToUpper(r)
+		if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-' || r == '\'' {
+			return r
+		}
+
+		return -1
+	}, currency)
+
+	// commodity starts with letter and ends with letter or digit
+	c = strings.TrimLeft(c, "0123456789.'_-")
+	c = strings.TrimRight(c, ".'_-")
+
+	for len(c) < 2 {
+		c += "X"
+	}
+
+	return c
+}
@@ -0,0 +1,48 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBeancountWriter(t *testing.T) {
+	sb := &strings.Builder{}
+	w := NewBeancountWriter(sb)
+
+	for _, op := range journalOperations {
+		require.NoError(t, w.Write(op))
+	}
+
+	require.NoError(t, w.Flush())
+	require.Equal(t, `2024-03-01 open Expenses:Transport-Taxi
+2024-03-01 open Assets:Main-Card
+2024-03-01 * "Taxi \"Yellow\"; airport #work #trip!" #work #trip
+  Expenses:Transport-Taxi  45.50 USD
+  Assets:Main-Card  -45.50 USD
+
+2024-03-02 open Assets:Euro
+2024-03-02 * "Main card → Euro"
+  Assets:Main-Card  -100.00 USD @@ 92.00 EUR
+  Assets:Euro  92.00 EUR
+
+2024-03-03 open Income:Other
+2024-03-03 open Assets:Yen
+2024-03-03 * "Salary"
+  Income:Other  -300000 JPY
+  Assets:Yen  300000 JPY
+
+2024-03-04 open Equity:Adjustments
+2024-03-04 * "Reconciliation"
+  Assets:Euro  -1.50 EUR
+  Equity:Adjustments  1.50 EUR
+
+`, sb.String())
+}
+
+func TestBeancountAccount(t *testing.T) {
+	require.Equal(t, "Продукты-И-Кафе", beancountAccount("продукты и кафе"))
+	require.Equal(t, "2024-Trip", beancountAccount("2024 (trip)"))
+	require.Equal(t, "Other", beancountAccount(" :: "))
+}
@@ -0,0 +1,68 @@
+// Package export writes operations to files in formats of spreadsheets and other finance software.
+package export
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+)
+
+const dateLayout = "2006-01-02"
+
+// utf8BOM makes Excel read file in UTF-8 instead of system code page.
+const utf8BOM = "\ufeff"
+
+var csvHeader = []string{"date", "amount", "currency", "category", "type", "name"}
+
+// CSVConfig is number format of spreadsheet locale, Excel splits columns by list separator of the locale,
+// which is semicolon when comma is decimal separator.
+type CSVConfig struct {
+	Delimiter rune
+	Decimal   string
+}
+
+var (
+	CSVComma     = CSVConfig{Delimiter: ',', Decimal: "."}
+	CSVSemicolon = CSVConfig{Delimiter: ';', Decimal: ","}
+)
+
+// CSVWriter writes operations as CSV rows.
+type CSVWriter struct {
+	w      *csv.Writer
+	config CSVConfig
+}
+
+// NewCSVWriter writes byte order mark and header to w and returns writer of operations.
+func NewCSVWriter(w io.Writer, config CSVConfig) (*CSVWriter, error) {
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return nil, err
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Comma = config.Delimiter
+
+	if err := cw.Write(csvHeader); err != nil {
+		return nil, err
+	}
+
+	return &CSVWriter{w: cw, config: config}, nil
+}
+
+func (w *CSVWriter) Write(op domain.ExportedOperation) error {
+	return w.w.Write([]string{
+		op.OccuredAt.Format(dateLayout),
+		op.Money.Format(op.Currency, money.FormatConfig{Decimal: w.config.Decimal, ForceDecimals: true}),
+		op.Currency,
+		op.Category,
+		op.Kind(),
+		op.Name,
+	})
+}
+
+// Flush writes buffered rows to the underlying writer.
+func (w *CSVWriter) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
@@ -0,0 +1,49 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ysomad/financer/internal/domain"
+)
+
+func TestCSVWriter(t *testing.T) {
+	sb := &strings.Builder{}
+
+	w, err := NewCSVWriter(sb, CSVSemicolon)
+	require.NoError(t, err)
+
+	ops := []domain.ExportedOperation{
+		{
+			Operation: domain.Operation{
+				Type:      domain.OperationTypeRegular,
+				Name:      "кофе; с собой",
+				Currency:  "RUB",
+				Money:     -35050,
+				OccuredAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			},
+			Category: "Еда",
+		},
+		{
+			Operation: domain.Operation{
+				Type:      domain.OperationTypeTransfer,
+				Name:      "Cash → Card",
+				Currency:  "JPY",
+				Money:     -1500,
+				OccuredAt: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, op := range ops {
+		require.NoError(t, w.Write(op))
+	}
+
+	require.NoError(t, w.Flush())
+	require.Equal(t, "\ufeffdate;amount;currency;category;type;name\n"+
+		"2024-03-01;-350,50;RUB;Еда;expense;\"кофе; с собой\"\n"+
+		"2024-03-02;-1500;JPY;;transfer;Cash → Card\n", sb.String())
+}
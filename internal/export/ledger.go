@@ -0,0 +1,184 @@
+package export
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+)
+
+// Top level accounts of plain text accounting journals.
+const (
+	journalAssets   = "Assets"
+	journalExpenses = "Expenses"
+	journalIncome   = "Income"
+	journalEquity   = "Equity"
+
+	journalUncategorized = "Other"
+	journalAdjustments   = "Adjustments"
+)
+
+// journalReplacer removes line breaks and comment and code marks from ledger transaction description.
+var journalReplacer = strings.NewReplacer("\r", " ", "\n", " ", ";", ",")
+
+// journalPosting is posting of transaction in plain text accounting journal,
+// price is total cost in other currency and is empty for postings in the same currency.
+type journalPosting struct {
+	account       string // account name without top level account
+	root          string
+	money         money.Money
+	currency      string
+	price         money.Money
+	priceCurrency string
+}
+
+// journalPostings returns balanced postings of operation, regular operations move money between
+// asset account and category, transfers and exchanges between asset accounts with total price
+// if currencies differ, adjustments between asset account and equity.
+func journalPostings(op domain.ExportedOperation) []journalPosting {
+	asset := journalPosting{account: op.Account, root: journalAssets, money: op.Money, currency: op.Currency}
+
+	switch op.Type {
+	case domain.OperationTypeTransfer, domain.OperationTypeExchange:
+		if op.ToCurrency != op.Currency {
+			asset.price, asset.priceCurrency = op.ToMoney, op.ToCurrency
+		}
+
+		return []journalPosting{
+			asset,
+			{account: op.ToAccount, root: journalAssets, money: op.ToMoney, currency: op.ToCurrency},
+		}
+	case domain.OperationTypeAdjustment:
+		return []journalPosting{
+			asset,
+			{account: journalAdjustments, root: journalEquity, money: -op.Money, currency: op.Currency},
+		}
+	}
+
+	category := journalPosting{account: op.Category, root: journalIncome, money: -op.Money, currency: op.Currency}
+	if op.IsExpense() {
+		category.root = journalExpenses
+	}
+
+	if strings.TrimSpace(category.account) == "" {
+		category.account = journalUncategorized
+	}
+
+	return []journalPosting{category, asset}
+}
+
+// journalTags returns hashtags of operation name without hash sign, e.g. "work" of "Taxi #work",
+// tags contain only letters, digits, dashes and underscores.
+func journalTags(name string) []string {
+	var tags []string
+
+	for _, word := range strings.Fields(name) {
+		tag, ok := strings.CutPrefix(word, "#")
+		if !ok {
+			continue
+		}
+
+		tag = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+				return r
+			}
+
+			return -1
+		}, tag)
+
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// journalAmount formats money without thousand separators, e.g. -1234.50.
+func journalAmount(m money.Money, currency string) string {
+	return m.Format(currency, money.FormatConfig{ForceDecimals: true})
+}
+
+// LedgerWriter writes operations as transactions of ledger journal which is read by ledger and hledger.
+// Hashtags of operation names are written as tags of transactions in ledger format, e.g. "; :work:trip:".
+type LedgerWriter struct {
+	w *bufio.Writer
+}
+
+func NewLedgerWriter(w io.Writer) *LedgerWriter {
+	return &LedgerWriter{w: bufio.NewWriter(w)}
+}
+
+func (w *LedgerWriter) Write(op domain.ExportedOperation) error {
+	sb := strings.Builder{}
+
+	// status mark goes before description, so description may not start with code in parentheses
+	desc := strings.TrimLeft(strings.Join(strings.Fields(journalReplacer.Replace(op.Name)), " "), "(*! ")
+
+	sb.WriteString(op.OccuredAt.Format(dateLayout) + " * " + desc)
+
+	if tags := journalTags(op.Name); len(tags) > 0 {
+		sb.WriteString("  ; :" + strings.Join(tags, ":") + ":")
+	}
+
+	sb.WriteString("\n")
+
+	for _, p := range journalPostings(op) {
+		sb.WriteString("    " + p.root + ":" + ledgerAccount(p.account) + "  " +
+			journalAmount(p.money, p.currency) + " " + ledgerCommodity(p.currency))
+
+		if p.priceCurrency != "" {
+			sb.WriteString(" @@ " + journalAmount(p.price, p.priceCurrency) + " " + ledgerCommodity(p.priceCurrency))
+		}
+
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+
+	_, err := w.w.WriteString(sb.String())
+
+	return err
+}
+
+// Flush writes buffered transactions to the underlying writer.
+func (w *LedgerWriter) Flush() error {
+	return w.w.Flush()
+}
+
+// ledgerAccount returns account name without separator of subaccounts, comment marks, brackets of virtual
+// postings and repeated spaces which end account name.
+func ledgerAccount(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case ':', ';', '(', ')', '[', ']', '@', '=', '*', '!':
+			return ' '
+		}
+
+		if unicode.IsSpace(r) {
+			return ' '
+		}
+
+		return r
+	}, name)
+
+	if name = strings.Join(strings.Fields(name), " "); name == "" {
+		return journalUncategorized
+	}
+
+	return name
+}
+
+// ledgerCommodity returns commodity in quotes if it has characters other than letters.
+func ledgerCommodity(currency string) string {
+	for _, r := range currency {
+		if !unicode.IsLetter(r) {
+			return `"` + strings.ReplaceAll(currency, `"`, "") + `"`
+		}
+	}
+
+	return currency
+}
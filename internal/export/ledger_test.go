@@ -0,0 +1,87 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ysomad/financer/internal/domain"
+)
+
+// journalOperations are operations of every type for journal writers tests.
+var journalOperations = []domain.ExportedOperation{
+	{
+		Operation: domain.Operation{
+			Type:      domain.OperationTypeRegular,
+			Name:      `Taxi "Yellow"; airport #work #trip!`,
+			Currency:  "USD",
+			Money:     -4550,
+			OccuredAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Category: "Transport: taxi",
+		Account:  "Main card",
+	},
+	{
+		Operation: domain.Operation{
+			Type:      domain.OperationTypeExchange,
+			Name:      "Main card → Euro",
+			Currency:  "USD",
+			Money:     -10000,
+			OccuredAt: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+		},
+		Account:    "Main card",
+		ToAccount:  "Euro",
+		ToCurrency: "EUR",
+		ToMoney:    9200,
+	},
+	{
+		Operation: domain.Operation{
+			Type:      domain.OperationTypeRegular,
+			Name:      "Salary",
+			Currency:  "JPY",
+			Money:     300000,
+			OccuredAt: time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC),
+		},
+		Account: "Yen",
+	},
+	{
+		Operation: domain.Operation{
+			Type:      domain.OperationTypeAdjustment,
+			Name:      "Reconciliation",
+			Currency:  "EUR",
+			Money:     -150,
+			OccuredAt: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+		},
+		Account: "Euro",
+	},
+}
+
+func TestLedgerWriter(t *testing.T) {
+	sb := &strings.Builder{}
+	w := NewLedgerWriter(sb)
+
+	for _, op := range journalOperations {
+		require.NoError(t, w.Write(op))
+	}
+
+	require.NoError(t, w.Flush())
+	require.Equal(t, `2024-03-01 * Taxi "Yellow", airport #work #trip!  ; :work:trip:
+    Expenses:Transport taxi  45.50 USD
+    Assets:Main card  -45.50 USD
+
+2024-03-02 * Main card → Euro
+    Assets:Main card  -100.00 USD @@ 92.00 EUR
+    Assets:Euro  92.00 EUR
+
+2024-03-03 * Salary
+    Income:Other  -300000 JPY
+    Assets:Yen  300000 JPY
+
+2024-03-04 * Reconciliation
+    Assets:Euro  -1.50 EUR
+    Equity:Adjustments  1.50 EUR
+
+`, sb.String())
+}
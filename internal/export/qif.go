@@ -0,0 +1,62 @@
+package export
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+)
+
+const qifDateLayout = "01/02/2006"
+
+// qifReplacer removes line breaks and separators of category, subcategory and class from QIF fields.
+var qifReplacer = strings.NewReplacer("\r", " ", "\n", " ", ":", "-", "/", "-")
+
+// QIFWriter writes operations as transactions of QIF bank sections, every account has its own section.
+// Transfers and exchanges are written with destination account in brackets as category.
+type QIFWriter struct {
+	w       *bufio.Writer
+	account string // account of the current section
+	started bool
+}
+
+func NewQIFWriter(w io.Writer) *QIFWriter {
+	return &QIFWriter{w: bufio.NewWriter(w)}
+}
+
+func (w *QIFWriter) Write(op domain.ExportedOperation) error {
+	sb := strings.Builder{}
+
+	if !w.started || op.Account != w.account {
+		w.started, w.account = true, op.Account
+		sb.WriteString("!Account\nN" + qifReplacer.Replace(op.Account) + "\nTBank\n^\n!Type:Bank\n")
+	}
+
+	sb.WriteString("D" + op.OccuredAt.Format(qifDateLayout) + "\n")
+	sb.WriteString("T" + op.Money.Format(op.Currency, money.FormatConfig{ForceDecimals: true}) + "\n")
+	sb.WriteString("P" + qifReplacer.Replace(op.Name) + "\n")
+
+	switch {
+	case op.ToAccount != "":
+		sb.WriteString("L[" + qifReplacer.Replace(op.ToAccount) + "]\n")
+	case op.Category != "":
+		sb.WriteString("L" + qifReplacer.Replace(op.Category) + "\n")
+	}
+
+	if op.Type != domain.OperationTypeRegular {
+		sb.WriteString("M" + op.Kind() + "\n")
+	}
+
+	sb.WriteString("^\n")
+
+	_, err := w.w.WriteString(sb.String())
+
+	return err
+}
+
+// Flush writes buffered transactions to the underlying writer.
+func (w *QIFWriter) Flush() error {
+	return w.w.Flush()
+}
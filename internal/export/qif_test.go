@@ -0,0 +1,62 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ysomad/financer/internal/domain"
+)
+
+func TestQIFWriter(t *testing.T) {
+	sb := &strings.Builder{}
+	w := NewQIFWriter(sb)
+
+	ops := []domain.ExportedOperation{
+		{
+			Operation: domain.Operation{
+				Type:      domain.OperationTypeRegular,
+				Name:      "Whole Foods",
+				Currency:  "USD",
+				Money:     -123450,
+				OccuredAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			},
+			Category: "Food/Groceries",
+			Account:  "Card",
+		},
+		{
+			Operation: domain.Operation{
+				Type:      domain.OperationTypeTransfer,
+				Name:      "Card → Cash",
+				Currency:  "USD",
+				Money:     -10000,
+				OccuredAt: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+			},
+			Account:   "Card",
+			ToAccount: "Cash",
+		},
+		{
+			Operation: domain.Operation{
+				Type:      domain.OperationTypeRegular,
+				Name:      "Salary",
+				Currency:  "JPY",
+				Money:     300000,
+				OccuredAt: time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC),
+			},
+			Account: "Yen",
+		},
+	}
+
+	for _, op := range ops {
+		require.NoError(t, w.Write(op))
+	}
+
+	require.NoError(t, w.Flush())
+	require.Equal(t, "!Account\nNCard\nTBank\n^\n!Type:Bank\n"+
+		"D03/01/2024\nT-1234.50\nPWhole Foods\nLFood-Groceries\n^\n"+
+		"D03/02/2024\nT-100.00\nPCard → Cash\nL[Cash]\nMtransfer\n^\n"+
+		"!Account\nNYen\nTBank\n^\n!Type:Bank\n"+
+		"D03/03/2024\nT300000\nPSalary\n^\n", sb.String())
+}
@@ -0,0 +1,157 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/ysomad/financer/internal/money"
+)
+
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// ParseAmount parses amount in currency written with any thousands and decimal separators,
+// e.g. "1 234,56", "1,234.56", "-1.234,56 ₽" or "USD 12". If both comma and dot are used the last one
+// is decimal separator, a single separator followed by exactly three digits is treated as
+// thousands separator in currencies with less than three minor unit digits unless integer part is zero.
+// Amounts of a column are parsed by parseAmount with decimal separator detected by all of them.
+func ParseAmount(s, currency string) (money.Money, error) {
+	return parseAmount(s, currency, 0)
+}
+
+// parseAmount parses amount with decimal separator '.' or ',', separator is guessed by ParseAmount rules
+// if decimal is zero.
+func parseAmount(s, currency string, decimal byte) (money.Money, error) {
+	s, err := normalizeAmount(s)
+	if err != nil {
+		return 0, err
+	}
+
+	if decimal == 0 {
+		decimal = amountDecimal(s, currency)
+	}
+
+	if decimal != 0 {
+		if strings.Count(s, string(decimal)) > 1 {
+			return 0, fmt.Errorf("%w: %s", ErrInvalidAmount, s)
+		}
+
+		thousand := ","
+		if decimal == ',' {
+			thousand = "."
+		}
+
+		s = strings.ReplaceAll(s, thousand, "")
+	} else {
+		s = strings.NewReplacer(".", "", ",", "").Replace(s)
+	}
+
+	m, err := money.Parse(s, currency)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidAmount, err)
+	}
+
+	return m, nil
+}
+
+// amountDecimal returns decimal separator of normalized amount or zero if amount has no decimal separator.
+func amountDecimal(s, currency string) byte {
+	dot, comma := strings.Count(s, "."), strings.Count(s, ",")
+
+	switch {
+	case dot > 0 && comma > 0:
+		return s[strings.LastIndexAny(s, ".,")]
+	case dot+comma == 1:
+		i := strings.IndexAny(s, ".,")
+		integer := strings.TrimPrefix(s[:i], "-")
+
+		// thousands are not grouped after zero
+		if len(s)-i-1 == 3 && money.Exponent(currency) < 3 && integer != "" && integer != "0" {
+			return 0
+		}
+
+		return s[i]
+	}
+
+	return 0
+}
+
+// detectAmountDecimal returns decimal separator of amounts of a column which is unambiguous by some of them,
+// e.g. "1.500" has decimal dot if column has "12.5" and "1,250.00". It returns zero if no amount shows
+// the separator or amounts show different ones.
+func detectAmountDecimal(values []string) byte {
+	var found byte
+
+	for _, v := range values {
+		s, err := normalizeAmount(v)
+		if err != nil {
+			continue
+		}
+
+		var decimal byte
+
+		dot, comma := strings.Count(s, "."), strings.Count(s, ",")
+
+		switch {
+		case dot > 0 && comma > 0:
+			decimal = s[strings.LastIndexAny(s, ".,")]
+		case dot > 1:
+			decimal = ','
+		case comma > 1:
+			decimal = '.'
+		case dot+comma == 1:
+			i := strings.IndexAny(s, ".,")
+			integer := strings.TrimPrefix(s[:i], "-")
+
+			if len(s)-i-1 != 3 || integer == "" || integer == "0" {
+				decimal = s[i]
+			}
+		}
+
+		if decimal == 0 {
+			continue
+		}
+
+		if found != 0 && found != decimal {
+			return 0
+		}
+
+		found = decimal
+	}
+
+	return found
+}
+
+// normalizeAmount removes spaces and currency symbols or codes around the amount
+// and returns sign, digits and separators.
+func normalizeAmount(s string) (string, error) {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '\'' {
+			return -1
+		}
+
+		return r
+	}, s)
+
+	start := strings.IndexFunc(s, unicode.IsDigit)
+	if start < 0 {
+		return "", ErrInvalidAmount
+	}
+
+	// sign may be put before or after currency symbol, e.g. -$5 and $-5
+	neg := strings.ContainsAny(s[:start], "-−")
+	s = strings.TrimFunc(s[start:], func(r rune) bool { return !unicode.IsDigit(r) })
+
+	for _, r := range s {
+		if !unicode.IsDigit(r) && r != '.' && r != ',' {
+			return "", fmt.Errorf("%w: %s", ErrInvalidAmount, s)
+		}
+	}
+
+	if neg {
+		s = "-" + s
+	}
+
+	return s, nil
+}
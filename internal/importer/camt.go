@@ -0,0 +1,263 @@
+package importer
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+)
+
+const (
+	camtCredit      = "CRDT"
+	camtBooked      = "BOOK"
+	camtNotProvided = "NOTPROVIDED"
+)
+
+var (
+	ErrInvalidCAMT = errors.New("invalid camt.053")
+	ErrNotBooked   = errors.New("entry is not booked")
+)
+
+// camtDocument is ISO 20022 Bank to Customer Statement, element names are the same
+// in all versions of camt.053, so namespace is not checked.
+type camtDocument struct {
+	XMLName    xml.Name        `xml:"Document"`
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtStatement struct {
+	ID       string      `xml:"Id"`
+	IBAN     string      `xml:"Acct>Id>IBAN"`
+	Other    string      `xml:"Acct>Id>Othr>Id"`
+	Currency string      `xml:"Acct>Ccy"`
+	Entries  []camtEntry `xml:"Ntry"`
+}
+
+type camtEntry struct {
+	Ref    string `xml:"NtryRef"`
+	Amount struct {
+		Value    string `xml:",chardata"`
+		Currency string `xml:"Ccy,attr"`
+	} `xml:"Amt"`
+	CreditDebit string `xml:"CdtDbtInd"`
+	// status is element text before version 8 and Cd element since
+	Status struct {
+		Value string `xml:",chardata"`
+		Code  string `xml:"Cd"`
+	} `xml:"Sts"`
+	BookingDate    camtDate        `xml:"BookgDt"`
+	ValueDate      camtDate        `xml:"ValDt"`
+	ServicerRef    string          `xml:"AcctSvcrRef"`
+	Transactions   []camtTxDetails `xml:"NtryDtls>TxDtls"`
+	AdditionalInfo string          `xml:"AddtlNtryInf"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+type camtTxDetails struct {
+	ServicerRef string    `xml:"Refs>AcctSvcrRef"`
+	TxID        string    `xml:"Refs>TxId"`
+	EndToEndID  string    `xml:"Refs>EndToEndId"`
+	Debtor      camtParty `xml:"RltdPties>Dbtr"`
+	Creditor    camtParty `xml:"RltdPties>Cdtr"`
+	Remittance  []string  `xml:"RmtInf>Ustrd"`
+	Structured  []string  `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+	Additional  string    `xml:"AddtlTxInf"`
+}
+
+// camtParty name is Nm element before version 8 and Pty>Nm since.
+type camtParty struct {
+	Name      string `xml:"Nm"`
+	PartyName string `xml:"Pty>Nm"`
+}
+
+func (p camtParty) name() string {
+	if p.Name != "" {
+		return p.Name
+	}
+
+	return p.PartyName
+}
+
+// ReadCAMT053 reads booked entries of ISO 20022 camt.053 statements. Operation name is counterparty name
+// or remittance information, external id is account servicer reference or transaction reference prefixed
+// with account id. Entry reference is unique within statement only, so it is used with statement id
+// if entry has no other reference.
+func ReadCAMT053(r io.Reader, _ string) ([]domain.ImportedOperation, []RowError, error) {
+	var doc camtDocument
+
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidCAMT, err)
+	}
+
+	var (
+		ops  []domain.ImportedOperation
+		errs []RowError
+		n    int
+	)
+
+	for _, stmt := range doc.Statements {
+		account := stmt.IBAN
+		if account == "" {
+			account = stmt.Other
+		}
+
+		for _, e := range stmt.Entries {
+			n++
+
+			op, err := e.operation(account, stmt.ID, stmt.Currency)
+			if err != nil {
+				errs = append(errs, RowError{Row: n, Err: err})
+				continue
+			}
+
+			ops = append(ops, op)
+		}
+	}
+
+	if n == 0 {
+		return nil, nil, ErrEmptyFile
+	}
+
+	return ops, errs, nil
+}
+
+func (e camtEntry) operation(account, statementID, accountCurrency string) (domain.ImportedOperation, error) {
+	if status := strings.TrimSpace(e.Status.Value + e.Status.Code); status != camtBooked {
+		return domain.ImportedOperation{}, fmt.Errorf("%w: %s", ErrNotBooked, status)
+	}
+
+	op := domain.ImportedOperation{
+		Name:     truncate(e.name(), domain.MaxNameLen),
+		Currency: strings.ToUpper(strings.TrimSpace(e.Amount.Currency)),
+	}
+
+	if op.Name == "" {
+		return domain.ImportedOperation{}, ErrEmptyName
+	}
+
+	ref := e.ref(statementID)
+	if ref == "" {
+		return domain.ImportedOperation{}, ErrNoTransactionID
+	}
+
+	op.ExternalID = ref
+	if account != "" {
+		op.ExternalID = account + ":" + ref
+	}
+
+	if op.Currency == "" {
+		op.Currency = accountCurrency
+	}
+
+	if !domain.IsCurrency(op.Currency) {
+		return domain.ImportedOperation{}, fmt.Errorf("%w: %s", ErrInvalidCurrency, op.Currency)
+	}
+
+	date := e.BookingDate.value()
+	if date == "" {
+		date = e.ValueDate.value()
+	}
+
+	var err error
+
+	op.OccuredAt, err = time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return domain.ImportedOperation{}, fmt.Errorf("%w: %s", ErrInvalidDate, date)
+	}
+
+	op.Money, err = money.Parse(strings.TrimSpace(e.Amount.Value), op.Currency)
+	if err != nil || op.Money < 0 {
+		return domain.ImportedOperation{}, fmt.Errorf("%w: %s", ErrInvalidAmount, e.Amount.Value)
+	}
+
+	if op.Money == 0 {
+		return domain.ImportedOperation{}, ErrZeroAmount
+	}
+
+	if strings.TrimSpace(e.CreditDebit) != camtCredit {
+		op.Money = -op.Money
+	}
+
+	return op, nil
+}
+
+// value returns date part of date or date time.
+func (d camtDate) value() string {
+	v := strings.TrimSpace(d.Date)
+	if v == "" {
+		v = strings.TrimSpace(d.DateTime)
+	}
+
+	if len(v) > 10 {
+		v = v[:10]
+	}
+
+	return v
+}
+
+// name returns counterparty of entry, which is creditor for debit and debtor for credit entries,
+// or remittance information if counterparty is unknown.
+func (e camtEntry) name() string {
+	for _, tx := range e.Transactions {
+		party := tx.Creditor
+		if strings.TrimSpace(e.CreditDebit) == camtCredit {
+			party = tx.Debtor
+		}
+
+		if name := strings.TrimSpace(party.name()); name != "" {
+			return name
+		}
+	}
+
+	for _, tx := range e.Transactions {
+		if info := strings.TrimSpace(strings.Join(slices.Concat(tx.Remittance, tx.Structured), " ")); info != "" {
+			return info
+		}
+
+		if info := strings.TrimSpace(tx.Additional); info != "" {
+			return info
+		}
+	}
+
+	return strings.TrimSpace(e.AdditionalInfo)
+}
+
+// ref returns the first of account servicer and transaction references which is provided.
+// Entry reference is numbered by many banks within statement only, so it is prefixed
+// with statement id or booking date if statement has no id.
+func (e camtEntry) ref(statementID string) string {
+	refs := []string{e.ServicerRef}
+
+	if len(e.Transactions) == 1 {
+		tx := e.Transactions[0]
+		refs = append(refs, tx.ServicerRef, tx.TxID, tx.EndToEndID)
+	}
+
+	for _, ref := range refs {
+		if ref = strings.TrimSpace(ref); ref != "" && ref != camtNotProvided {
+			return ref
+		}
+	}
+
+	ref := strings.TrimSpace(e.Ref)
+	if ref == "" || ref == camtNotProvided {
+		return ""
+	}
+
+	scope := strings.TrimSpace(statementID)
+	if scope == "" {
+		scope = e.BookingDate.value()
+	}
+
+	return scope + "/" + ref
+}
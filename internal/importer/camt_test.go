@@ -0,0 +1,104 @@
+package importer
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ysomad/financer/internal/domain"
+)
+
+func TestReadCAMT053(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    []domain.ImportedOperation
+		errs    []error
+	}{
+		{
+			fixture: "testdata/camt053_v02.xml",
+			want: []domain.ImportedOperation{
+				{
+					Name:       "Hausverwaltung GmbH",
+					Currency:   "EUR",
+					Money:      -123456,
+					OccuredAt:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local),
+					ExternalID: "DE89370400440532013000:STMT-1/E-1",
+				},
+				{
+					Name:       "Refund order 42",
+					Currency:   "CHF",
+					Money:      5000,
+					OccuredAt:  time.Date(2024, 3, 3, 0, 0, 0, 0, time.Local),
+					ExternalID: "DE89370400440532013000:SVC-2",
+				},
+			},
+			errs: []error{ErrNotBooked, ErrNoTransactionID},
+		},
+		{
+			fixture: "testdata/camt053_v08.xml",
+			want: []domain.ImportedOperation{
+				{
+					Name:       "ACME B.V.",
+					Currency:   "EUR",
+					Money:      250000,
+					OccuredAt:  time.Date(2024, 3, 8, 0, 0, 0, 0, time.Local),
+					ExternalID: "NL91ABNA0417164300:EUR-STMT/EUR-1",
+				},
+				{
+					Name:       "Netflix",
+					Currency:   "USD",
+					Money:      -1999,
+					OccuredAt:  time.Date(2024, 3, 9, 0, 0, 0, 0, time.Local),
+					ExternalID: "USD-0042:USD-STMT/USD-1",
+				},
+				{
+					Name:       "Tokyo Metro",
+					Currency:   "JPY",
+					Money:      -1500,
+					OccuredAt:  time.Date(2024, 3, 9, 0, 0, 0, 0, time.Local),
+					ExternalID: "USD-0042:USD-STMT/JPY-1",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			f, err := os.Open(tt.fixture)
+			require.NoError(t, err)
+			defer f.Close()
+
+			ops, errs, err := ReadCAMT053(f, "RUB")
+			require.NoError(t, err)
+			require.Equal(t, tt.want, ops)
+			require.Len(t, errs, len(tt.errs))
+
+			for i, err := range tt.errs {
+				require.ErrorIs(t, errs[i], err)
+			}
+		})
+	}
+}
+
+func TestReadCAMT053Invalid(t *testing.T) {
+	_, _, err := ReadCAMT053(strings.NewReader("<OFX></OFX>"), "RUB")
+	require.ErrorIs(t, err, ErrInvalidCAMT)
+
+	_, _, err = ReadCAMT053(strings.NewReader("<Document><BkToCstmrStmt></BkToCstmrStmt></Document>"), "RUB")
+	require.ErrorIs(t, err, ErrEmptyFile)
+}
+
+func TestCAMTEntryRef(t *testing.T) {
+	e := camtEntry{Ref: "1", BookingDate: camtDate{DateTime: "2024-03-01T10:00:00"}}
+	require.Equal(t, "STMT-1/1", e.ref("STMT-1"))
+	require.Equal(t, "2024-03-01/1", e.ref(""))
+
+	e.Transactions = []camtTxDetails{{TxID: "TX-1", EndToEndID: camtNotProvided}}
+	require.Equal(t, "TX-1", e.ref("STMT-1"))
+
+	e.ServicerRef = "SVC-1"
+	require.Equal(t, "SVC-1", e.ref("STMT-1"))
+}
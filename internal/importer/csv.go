@@ -0,0 +1,264 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ysomad/financer/internal/domain"
+)
+
+// delimiterSampleRows is number of rows used to detect delimiter.
+const delimiterSampleRows = 20
+
+var csvDelimiters = []rune{',', ';', '\t', '|'}
+
+var ErrNoColumn = errors.New("column is not in row")
+
+// CSVFile is a table read from CSV file of unknown layout.
+type CSVFile struct {
+	Encoding  string
+	Delimiter rune
+	Header    []string // nil if the first row is not a header
+	Rows      [][]string
+	Columns   int // max number of fields in a row
+
+	lines []int // line numbers of rows in file
+}
+
+// ReadCSV reads CSV file in UTF-8 or windows-1251 encoding with one of
+// comma, semicolon, tab or pipe delimiters. The first row is treated as header
+// if none of its fields is an amount or a date.
+func ReadCSV(r io.Reader) (CSVFile, error) {
+	text, encoding, err := readText(r)
+	if err != nil {
+		return CSVFile{}, err
+	}
+
+	f := CSVFile{Encoding: encoding, Delimiter: detectDelimiter(text)}
+
+	var (
+		cr    = newCSVReader(text, f.Delimiter)
+		rows  [][]string
+		lines []int
+	)
+
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return CSVFile{}, fmt.Errorf("csv: %w", err)
+		}
+
+		// rows of empty fields only
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+
+		line, _ := cr.FieldPos(0)
+		rows, lines = append(rows, row), append(lines, line)
+	}
+
+	if len(rows) > 0 && isHeader(rows[0]) {
+		f.Header, rows, lines = rows[0], rows[1:], lines[1:]
+	}
+
+	if len(rows) == 0 {
+		return CSVFile{}, ErrEmptyFile
+	}
+
+	f.Rows = rows
+	f.lines = lines
+	f.Columns = len(f.Header)
+
+	for _, row := range rows {
+		f.Columns = max(f.Columns, len(row))
+	}
+
+	return f, nil
+}
+
+func newCSVReader(text string, delimiter rune) *csv.Reader {
+	r := csv.NewReader(strings.NewReader(text))
+	r.Comma = delimiter
+	r.LazyQuotes = true
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	return r
+}
+
+// detectDelimiter returns delimiter which splits the first rows to the same and the biggest number of fields.
+func detectDelimiter(text string) rune {
+	best, bestScore := csvDelimiters[0], 0
+
+	for _, d := range csvDelimiters {
+		r := newCSVReader(text, d)
+		fields, consistent := 0, true
+
+		for i := 0; i < delimiterSampleRows; i++ {
+			row, err := r.Read()
+			if err != nil {
+				break
+			}
+
+			if i == 0 {
+				fields = len(row)
+			} else if len(row) != fields {
+				consistent = false
+			}
+		}
+
+		score := fields
+		if consistent {
+			score *= 2
+		}
+
+		if fields > 1 && score > bestScore {
+			best, bestScore = d, score
+		}
+	}
+
+	return best
+}
+
+func isHeader(row []string) bool {
+	for _, field := range row {
+		if _, err := normalizeAmount(field); err == nil {
+			return false
+		}
+
+		if _, ok := detectDateLayout(dateLayouts, []string{field}); ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Column returns header of column or its value in the first row if file has no header.
+func (f CSVFile) Column(i int) string {
+	if i < len(f.Header) && strings.TrimSpace(f.Header[i]) != "" {
+		return strings.TrimSpace(f.Header[i])
+	}
+
+	if i < len(f.Rows[0]) {
+		return strings.TrimSpace(f.Rows[0][i])
+	}
+
+	return ""
+}
+
+// CSVMapping is index of column for every operation field, optional fields are -1 if not mapped.
+type CSVMapping struct {
+	Date     int
+	Amount   int
+	Name     int
+	Category int // optional
+	Currency int // optional
+}
+
+// Operations converts rows to operations, rows which cannot be converted are returned as errors.
+// Date format and decimal separator are detected by values of date and amount columns, currency is
+// defaultCurrency if currency column is not mapped or empty.
+func (f CSVFile) Operations(m CSVMapping, defaultCurrency string) ([]domain.ImportedOperation, []RowError) {
+	var (
+		dates   = make([]string, 0, len(f.Rows))
+		amounts = make([]string, 0, len(f.Rows))
+	)
+
+	for _, row := range f.Rows {
+		if m.Date < len(row) {
+			dates = append(dates, row[m.Date])
+		}
+
+		if m.Amount < len(row) {
+			amounts = append(amounts, row[m.Amount])
+		}
+	}
+
+	layout, _ := detectDateLayout(dateLayouts, dates)
+	decimal := detectAmountDecimal(amounts)
+
+	var (
+		ops  = make([]domain.ImportedOperation, 0, len(f.Rows))
+		errs []RowError
+	)
+
+	for i, row := range f.Rows {
+		op, err := parseRow(row, m, layout, decimal, defaultCurrency)
+		if err != nil {
+			errs = append(errs, RowError{Row: f.lines[i], Err: err})
+			continue
+		}
+
+		ops = append(ops, op)
+	}
+
+	return ops, errs
+}
+
+func parseRow(row []string, m CSVMapping, dateLayout string, decimal byte, defaultCurrency string,
+) (domain.ImportedOperation, error) {
+	field := func(i int) string {
+		if i < 0 || i >= len(row) {
+			return ""
+		}
+
+		return strings.TrimSpace(row[i])
+	}
+
+	if m.Date >= len(row) || m.Amount >= len(row) || m.Name >= len(row) {
+		return domain.ImportedOperation{}, ErrNoColumn
+	}
+
+	op := domain.ImportedOperation{
+		Name:     truncate(field(m.Name), domain.MaxNameLen),
+		Category: truncate(field(m.Category), domain.MaxNameLen),
+		Currency: strings.ToUpper(field(m.Currency)),
+	}
+
+	if op.Name == "" {
+		return domain.ImportedOperation{}, ErrEmptyName
+	}
+
+	if op.Currency == "" {
+		op.Currency = defaultCurrency
+	}
+
+	if !domain.IsCurrency(op.Currency) {
+		return domain.ImportedOperation{}, fmt.Errorf("%w: %s", ErrInvalidCurrency, op.Currency)
+	}
+
+	var err error
+
+	op.OccuredAt, err = parseDate(field(m.Date), dateLayout)
+	if err != nil {
+		return domain.ImportedOperation{}, err
+	}
+
+	op.Money, err = parseAmount(field(m.Amount), op.Currency, decimal)
+	if err != nil {
+		return domain.ImportedOperation{}, err
+	}
+
+	if op.Money == 0 {
+		return domain.ImportedOperation{}, ErrZeroAmount
+	}
+
+	return op, nil
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,149 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+)
+
+func TestReadCSV(t *testing.T) {
+	text := "Дата;Сумма;Описание;Категория\r\n" +
+		"01.03.2024;-1 234,50;Пятерочка;Продукты\r\n" +
+		"02.03.2024;90 000;Зарплата;\r\n" +
+		"\r\n" +
+		"Итого;88 765,50;;\r\n"
+
+	cp1251, err := charmap.Windows1251.NewEncoder().String(text)
+	require.NoError(t, err)
+
+	inputs := map[string]string{
+		EncodingUTF8:        "\ufeff" + text,
+		EncodingWindows1251: cp1251,
+	}
+
+	for encoding, input := range inputs {
+		f, err := ReadCSV(strings.NewReader(input))
+		require.NoError(t, err)
+		require.Equal(t, encoding, f.Encoding)
+		require.Equal(t, ';', f.Delimiter)
+		require.Equal(t, []string{"Дата", "Сумма", "Описание", "Категория"}, f.Header)
+		require.Len(t, f.Rows, 3)
+		require.Equal(t, 4, f.Columns)
+		require.Equal(t, "Сумма", f.Column(1))
+
+		ops, errs := f.Operations(CSVMapping{Date: 0, Amount: 1, Name: 2, Category: 3, Currency: -1}, "RUB")
+		require.Equal(t, []domain.ImportedOperation{
+			{
+				Name:      "Пятерочка",
+				Category:  "Продукты",
+				Currency:  "RUB",
+				Money:     -123450,
+				OccuredAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local),
+			},
+			{
+				Name:      "Зарплата",
+				Currency:  "RUB",
+				Money:     9000000,
+				OccuredAt: time.Date(2024, 3, 2, 0, 0, 0, 0, time.Local),
+			},
+		}, ops)
+		require.Len(t, errs, 1)
+		require.Equal(t, 5, errs[0].Row)
+		require.ErrorIs(t, errs[0], ErrEmptyName)
+	}
+}
+
+func TestReadCSVWithoutHeader(t *testing.T) {
+	f, err := ReadCSV(strings.NewReader("2024-03-01,\"1,234.50\",Salary,USD\n2024-03-02,-3.99,\"Coffee, large\",EUR\n"))
+	require.NoError(t, err)
+	require.Equal(t, ',', f.Delimiter)
+	require.Equal(t, EncodingUTF8, f.Encoding)
+	require.Nil(t, f.Header)
+	require.Equal(t, "2024-03-01", f.Column(0))
+
+	ops, errs := f.Operations(CSVMapping{Date: 0, Amount: 1, Name: 2, Category: -1, Currency: 3}, "RUB")
+	require.Empty(t, errs)
+	require.Len(t, ops, 2)
+	require.EqualValues(t, 123450, ops[0].Money)
+	require.Equal(t, "USD", ops[0].Currency)
+	require.Equal(t, "Coffee, large", ops[1].Name)
+	require.EqualValues(t, -399, ops[1].Money)
+}
+
+func TestReadCSVEmpty(t *testing.T) {
+	_, err := ReadCSV(strings.NewReader("date;amount;name\n\n"))
+	require.ErrorIs(t, err, ErrEmptyFile)
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in       string
+		currency string
+		want     money.Money
+		err      bool
+	}{
+		{in: "12", currency: "USD", want: 1200},
+		{in: "-12,5", currency: "RUB", want: -1250},
+		{in: "1 234,56", currency: "RUB", want: 123456},
+		{in: "1 234,56 ₽", currency: "RUB", want: 123456},
+		{in: "1,234.56", currency: "USD", want: 123456},
+		{in: "1.234,56", currency: "EUR", want: 123456},
+		{in: "-$5.10", currency: "USD", want: -510},
+		{in: "$-5.10", currency: "USD", want: -510},
+		{in: "USD 1,000", currency: "USD", want: 100000},
+		{in: "1,234,567", currency: "USD", want: 123456700},
+		{in: "1.500", currency: "KWD", want: 1500},
+		{in: "1.500", currency: "EUR", want: 150000},
+		{in: "0.125", currency: "USD", want: 12},
+		{in: "-0,125", currency: "RUB", want: -12},
+		{in: "1'000.5", currency: "CHF", want: 100050},
+		{in: "1.000.000,5.1", currency: "USD", err: true},
+		{in: "abc", currency: "USD", err: true},
+		{in: "12/03", currency: "USD", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.in, tt.currency)
+		if tt.err {
+			require.ErrorIs(t, err, ErrInvalidAmount, tt.in)
+			continue
+		}
+
+		require.NoError(t, err, tt.in)
+		require.Equal(t, tt.want, got, tt.in)
+	}
+}
+
+func TestDetectAmountDecimal(t *testing.T) {
+	require.Equal(t, byte('.'), detectAmountDecimal([]string{"1.500", "-12.5", "1,250.00", "Total"}))
+	require.Equal(t, byte(','), detectAmountDecimal([]string{"1.500", "1.234.567"}))
+	require.Equal(t, byte(0), detectAmountDecimal([]string{"1.500", "2.000"}))
+	require.Equal(t, byte(0), detectAmountDecimal([]string{"12.5", "12,5"}))
+
+	m, err := parseAmount("1.500", "USD", '.')
+	require.NoError(t, err)
+	require.EqualValues(t, 150, m)
+}
+
+func TestDetectDateLayout(t *testing.T) {
+	layout, ok := detectDateLayout(dateLayouts, []string{"01/02/2024", "13/02/2024", "Total"})
+	require.True(t, ok)
+	require.Equal(t, "02/01/2006", layout)
+
+	layout, ok = detectDateLayout(dateLayouts, []string{"02/13/2024", "01/02/2024"})
+	require.True(t, ok)
+	require.Equal(t, "01/02/2006", layout)
+
+	layout, ok = detectDateLayout(dateLayouts, []string{"2024-03-01 10:15:00"})
+	require.True(t, ok)
+	require.Equal(t, "2006-01-02 15:04:05", layout)
+
+	_, ok = detectDateLayout(dateLayouts, []string{"Total"})
+	require.False(t, ok)
+}
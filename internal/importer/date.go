@@ -0,0 +1,74 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidDate = errors.New("invalid date")
+
+// dateLayouts are checked in order, so day goes before month in ambiguous dates like 01/02/2024.
+var dateLayouts = withTime(
+	"2006-01-02",
+	"02.01.2006",
+	"02/01/2006",
+	"01/02/2006",
+	"2006/01/02",
+	"2006.01.02",
+	"02-01-2006",
+	"02.01.06",
+	"2.1.2006",
+	"1/2/2006",
+)
+
+var timeLayouts = []string{"", " 15:04:05", " 15:04", "T15:04:05", "T15:04:05Z07:00", " 15:04:05 -0700"}
+
+func withTime(dates ...string) []string {
+	layouts := make([]string, 0, len(dates)*len(timeLayouts))
+
+	for _, d := range dates {
+		for _, t := range timeLayouts {
+			layouts = append(layouts, d+t)
+		}
+	}
+
+	return layouts
+}
+
+// detectDateLayout returns the first of layouts which parses the most of values,
+// it returns false if no value is a date.
+func detectDateLayout(layouts, values []string) (string, bool) {
+	best, bestParsed := "", 0
+
+	for _, layout := range layouts {
+		parsed := 0
+
+		for _, v := range values {
+			if _, err := time.Parse(layout, strings.TrimSpace(v)); err == nil {
+				parsed++
+			}
+		}
+
+		if parsed > bestParsed {
+			best, bestParsed = layout, parsed
+		}
+	}
+
+	return best, bestParsed > 0
+}
+
+// parseDate parses date in local time zone.
+func parseDate(s, layout string) (time.Time, error) {
+	if layout == "" {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidDate, s)
+	}
+
+	t, err := time.ParseInLocation(layout, s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidDate, s)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,63 @@
+// Package importer reads operations from files of banks and other finance software.
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	EncodingUTF8        = "UTF-8"
+	EncodingWindows1251 = "windows-1251"
+)
+
+const utf8BOM = "\ufeff"
+
+var (
+	ErrEmptyFile       = errors.New("file has no rows")
+	ErrEmptyName       = errors.New("empty name")
+	ErrZeroAmount      = errors.New("zero amount")
+	ErrInvalidCurrency = errors.New("invalid currency")
+)
+
+// RowError is error of a file row, rows are lines of file or numbers of transactions
+// in statement starting from 1.
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("row %d: %s", e.Row, e.Err)
+}
+
+func (e RowError) Unwrap() error {
+	return e.Err
+}
+
+// readText reads text in UTF-8 or windows-1251 encoding, files which are not valid UTF-8
+// are treated as windows-1251.
+func readText(r io.Reader) (string, string, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", "", err
+	}
+
+	b = bytes.TrimPrefix(b, []byte(utf8BOM))
+
+	if utf8.Valid(b) {
+		return string(b), EncodingUTF8, nil
+	}
+
+	b, err = charmap.Windows1251.NewDecoder().Bytes(b)
+	if err != nil {
+		return "", "", fmt.Errorf("windows-1251: %w", err)
+	}
+
+	return string(b), EncodingWindows1251, nil
+}
@@ -0,0 +1,335 @@
+package importer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+)
+
+const mt940NoRef = "NONREF"
+
+var (
+	ErrInvalidMT940 = errors.New("invalid mt940")
+
+	// mt940Tag is field tag at the start of line, e.g. :61: or :60F:
+	mt940Tag = regexp.MustCompile(`^:(\d{2}[A-Z]?):(.*)$`)
+	// mt940Line is :61: statement line: value date, optional entry date, debit or credit mark with optional
+	// reversal, optional funds code, amount, transaction type, customer and bank references.
+	mt940Line = regexp.MustCompile(`^(\d{6})(\d{4})?(R?[CD])([A-Z])?(\d+,\d*)([SNF][A-Z0-9]{3})([^/\n]*)(?://([^\n]*))?`)
+	// mt940Subfields is structured :86: field, e.g. 166?00SEPA-UEBERWEISUNG?20SVWZ+Invoice, the first
+	// non digit character after transaction code separates subfields which start with 2 digit code.
+	mt940Subfields = regexp.MustCompile(`^\d{3}([^\d\s])\d{2}`)
+	// mt940SEPAIdentifier is SEPA identifier in :86: purpose subfields, e.g. EREF+ is end to end reference
+	// and SVWZ+ is remittance information.
+	mt940SEPAIdentifier = regexp.MustCompile(`(EREF|KREF|MREF|CRED|DEBT|COAM|OAMT|SVWZ|ABWA|ABWE|IBAN|BIC)\+`)
+)
+
+// mt940Entry is :61: statement line with :86: information to account owner.
+type mt940Entry struct {
+	line      int
+	statement string
+	index     int // number of entry in statement
+	account   string
+	currency  string
+	raw       string
+	details   []string
+}
+
+// ReadMT940 reads statement lines of SWIFT MT940 file, currency of entries is currency of opening balance.
+// Operation name is counterparty name or purpose of :86: field, structured subfields of German
+// (?20 purpose, ?32 name) and Polish banks and Dutch /NAME/ and /REMI/ layouts are supported,
+// other details are used as is. External id is bank reference of the entry or its customer reference
+// with value date and amount prefixed with account, statement number and entry number are used
+// if entry has no references.
+func ReadMT940(r io.Reader, defaultCurrency string) ([]domain.ImportedOperation, []RowError, error) {
+	text, _, err := readText(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		entries   []mt940Entry
+		entry     *mt940Entry
+		field     string // tag of the current field
+		account   string
+		statement string
+		index     int
+		currency  = defaultCurrency
+		tagged    = false
+		sc        = bufio.NewScanner(strings.NewReader(text))
+	)
+
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimRight(sc.Text(), "\r ")
+
+		m := mt940Tag.FindStringSubmatch(line)
+		if m == nil {
+			// continuation of the previous field, SWIFT envelope and statement end are skipped
+			if line == "" || line == "-" || strings.HasPrefix(line, "{") || strings.HasPrefix(line, "-}") {
+				continue
+			}
+
+			switch {
+			case entry != nil && field == "61":
+				entry.raw += "\n" + line
+			case entry != nil && field == "86":
+				entry.details = append(entry.details, line)
+			}
+
+			continue
+		}
+
+		tagged = true
+		field = m[1]
+		value := strings.TrimSpace(m[2])
+
+		switch field {
+		case "25":
+			account = value
+		case "28", "28C":
+			statement, index = value, 0
+		case "60F", "60M":
+			// C240301EUR1234,56
+			if len(value) >= 10 {
+				currency = value[7:10]
+			}
+		case "61":
+			index++
+			entries = append(entries, mt940Entry{
+				line:      n,
+				statement: statement,
+				index:     index,
+				account:   account,
+				currency:  currency,
+				raw:       value,
+			})
+			entry = &entries[len(entries)-1]
+		case "86":
+			if entry != nil {
+				entry.details = append(entry.details, value)
+			}
+		default:
+			entry = nil
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if !tagged {
+		return nil, nil, ErrInvalidMT940
+	}
+
+	if len(entries) == 0 {
+		return nil, nil, ErrEmptyFile
+	}
+
+	var (
+		ops  = make([]domain.ImportedOperation, 0, len(entries))
+		errs []RowError
+	)
+
+	for _, e := range entries {
+		op, err := e.operation()
+		if err != nil {
+			errs = append(errs, RowError{Row: e.line, Err: err})
+			continue
+		}
+
+		ops = append(ops, op)
+	}
+
+	return ops, errs, nil
+}
+
+func (e mt940Entry) operation() (domain.ImportedOperation, error) {
+	m := mt940Line.FindStringSubmatch(e.raw)
+	if m == nil {
+		return domain.ImportedOperation{}, fmt.Errorf("%w: %s", ErrInvalidMT940, e.raw)
+	}
+
+	valueDate, entryDate, mark, amount, customerRef, bankRef := m[1], m[2], m[3], m[5], m[7], m[8]
+
+	op := domain.ImportedOperation{Currency: strings.ToUpper(e.currency)}
+
+	if !domain.IsCurrency(op.Currency) {
+		return domain.ImportedOperation{}, fmt.Errorf("%w: %s", ErrInvalidCurrency, op.Currency)
+	}
+
+	var err error
+
+	op.OccuredAt, err = mt940Date(valueDate, entryDate)
+	if err != nil {
+		return domain.ImportedOperation{}, err
+	}
+
+	op.Money, err = money.Parse(amount, op.Currency)
+	if err != nil {
+		return domain.ImportedOperation{}, fmt.Errorf("%w: %s", ErrInvalidAmount, amount)
+	}
+
+	if op.Money == 0 {
+		return domain.ImportedOperation{}, ErrZeroAmount
+	}
+
+	// reversal of credit is debit and reversal of debit is credit
+	if mark == "D" || mark == "RC" {
+		op.Money = -op.Money
+	}
+
+	op.Name = truncate(parseMT940Details(strings.Join(e.details, "\n")), domain.MaxNameLen)
+	if op.Name == "" && customerRef != mt940NoRef {
+		op.Name = strings.TrimSpace(customerRef)
+	}
+
+	if op.Name == "" {
+		return domain.ImportedOperation{}, ErrEmptyName
+	}
+
+	// customer reference is often repeated, e.g. mandate reference of monthly direct debit,
+	// so it is used with value date and amount
+	ref := strings.TrimSpace(bankRef)
+	if ref == "" || ref == mt940NoRef {
+		ref = strings.TrimSpace(customerRef)
+		if ref != "" && ref != mt940NoRef {
+			ref += "/" + valueDate + "/" + mark + amount
+		}
+	}
+
+	if ref == "" || ref == mt940NoRef {
+		ref = e.statement + "/" + strconv.Itoa(e.index)
+	}
+
+	op.ExternalID = e.account + ":" + ref
+
+	return op, nil
+}
+
+// mt940Date returns entry date if it is set or value date otherwise, entry date has no year
+// and may be in the next or previous year than value date.
+func mt940Date(valueDate, entryDate string) (time.Time, error) {
+	value, err := time.ParseInLocation("060102", valueDate, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidDate, valueDate)
+	}
+
+	if entryDate == "" {
+		return value, nil
+	}
+
+	entry, err := time.ParseInLocation("0102", entryDate, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidDate, entryDate)
+	}
+
+	year := value.Year()
+
+	switch {
+	case value.Month() == time.December && entry.Month() == time.January:
+		year++
+	case value.Month() == time.January && entry.Month() == time.December:
+		year--
+	}
+
+	return time.Date(year, entry.Month(), entry.Day(), 0, 0, 0, 0, time.Local), nil
+}
+
+// parseMT940Details returns counterparty name or purpose of :86: field.
+func parseMT940Details(details string) string {
+	if m := mt940Subfields.FindStringSubmatch(details); m != nil {
+		// subfields are split to lines at any position
+		return parseMT940Subfields(strings.ReplaceAll(details, "\n", ""), m[1])
+	}
+
+	if strings.HasPrefix(details, "/") {
+		return parseMT940Codes(strings.ReplaceAll(details, "\n", ""))
+	}
+
+	return strings.Join(strings.Fields(details), " ")
+}
+
+// parseMT940Subfields parses subfields separated by sep, ?20-?29 and ?60-?63 are purpose,
+// ?32-?33 are counterparty name.
+func parseMT940Subfields(details, sep string) string {
+	var name, purpose strings.Builder
+
+	for _, sub := range strings.Split(details, sep)[1:] {
+		if len(sub) < 2 {
+			continue
+		}
+
+		code, err := strconv.Atoi(sub[:2])
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case code == 32 || code == 33:
+			name.WriteString(sub[2:])
+		case code >= 20 && code <= 29, code >= 60 && code <= 63:
+			purpose.WriteString(sub[2:])
+		}
+	}
+
+	if s := strings.Join(strings.Fields(name.String()), " "); s != "" {
+		return s
+	}
+
+	return strings.Join(strings.Fields(sepaPurpose(purpose.String())), " ")
+}
+
+// sepaPurpose returns remittance information of purpose with SEPA identifiers, e.g.
+// "Invoice 1" of "EREF+REF-1SVWZ+Invoice 1", or purpose as is if it has no identifiers.
+func sepaPurpose(purpose string) string {
+	idx := mt940SEPAIdentifier.FindAllStringSubmatchIndex(purpose, -1)
+	if idx == nil {
+		return purpose
+	}
+
+	values := make(map[string]string, len(idx))
+
+	for i, m := range idx {
+		end := len(purpose)
+		if i+1 < len(idx) {
+			end = idx[i+1][0]
+		}
+
+		values[purpose[m[2]:m[3]]] = purpose[m[1]:end]
+	}
+
+	for _, id := range []string{"SVWZ", "ABWA"} {
+		if v := strings.TrimSpace(values[id]); v != "" {
+			return v
+		}
+	}
+
+	return strings.TrimSpace(purpose[:idx[0][0]])
+}
+
+// parseMT940Codes parses /CODE/value/ layout of Dutch banks, NAME is counterparty name and
+// REMI is purpose of transaction.
+func parseMT940Codes(details string) string {
+	parts := strings.Split(strings.Trim(details, "/"), "/")
+	values := make(map[string]string)
+
+	for i := 0; i+1 < len(parts); i += 2 {
+		values[parts[i]] = parts[i+1]
+	}
+
+	for _, code := range []string{"NAME", "REMI"} {
+		if v := strings.Join(strings.Fields(values[code]), " "); v != "" {
+			return v
+		}
+	}
+
+	return strings.Join(strings.Fields(details), " ")
+}
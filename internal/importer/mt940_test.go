@@ -0,0 +1,68 @@
+package importer
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ysomad/financer/internal/domain"
+)
+
+func TestReadMT940(t *testing.T) {
+	f, err := os.Open("testdata/statement.sta")
+	require.NoError(t, err)
+	t.Cleanup(func() { f.Close() })
+
+	ops, errs, err := ReadMT940(f, "RUB")
+	require.NoError(t, err)
+
+	require.Equal(t, []domain.ImportedOperation{
+		{
+			Name:       "REWE Markt GmbH Berlin",
+			Currency:   "EUR",
+			Money:      -1250,
+			OccuredAt:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local),
+			ExternalID: "10020030/1234567:POS-7781",
+		},
+		{
+			Name:       "Gehalt Maerz 2024",
+			Currency:   "EUR",
+			Money:      250000,
+			OccuredAt:  time.Date(2024, 3, 2, 0, 0, 0, 0, time.Local),
+			ExternalID: "10020030/1234567:SAL-2024-03/240302/C2500,",
+		},
+		{
+			Name:       "Returned direct debit insurance premium",
+			Currency:   "EUR",
+			Money:      -4000,
+			OccuredAt:  time.Date(2024, 3, 3, 0, 0, 0, 0, time.Local),
+			ExternalID: "10020030/1234567:00042/001/3",
+		},
+		{
+			Name:       "Albert Heijn",
+			Currency:   "EUR",
+			Money:      -750,
+			OccuredAt:  time.Date(2023, 12, 31, 0, 0, 0, 0, time.Local),
+			ExternalID: "NL91ABNA0417164300:7/1/1",
+		},
+		{
+			Name:       "Mobile plan",
+			Currency:   "EUR",
+			Money:      -1800,
+			OccuredAt:  time.Date(2024, 3, 12, 0, 0, 0, 0, time.Local),
+			ExternalID: "NL91ABNA0417164300:1234/240312/D18,",
+		},
+	}, ops)
+
+	require.Len(t, errs, 1)
+	require.Equal(t, 14, errs[0].Row)
+	require.ErrorIs(t, errs[0], ErrZeroAmount)
+}
+
+func TestReadMT940_Invalid(t *testing.T) {
+	_, _, err := ReadMT940(strings.NewReader("date,amount\n2024-03-01,10\n"), "RUB")
+	require.ErrorIs(t, err, ErrInvalidMT940)
+}
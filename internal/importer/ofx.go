@@ -0,0 +1,179 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+)
+
+var (
+	ErrInvalidOFX      = errors.New("invalid ofx")
+	ErrNoTransactionID = errors.New("transaction has no id")
+)
+
+// ofxTransaction is STMTTRN aggregate of OFX statement.
+type ofxTransaction struct {
+	fitID    string
+	posted   string
+	amount   string
+	name     string
+	memo     string
+	account  string
+	currency string
+}
+
+// ReadOFX reads transactions of bank and credit card statements from OFX or QFX file,
+// both SGML (OFX 1.x) and XML (OFX 2.x) files are supported. Operation name is NAME of transaction
+// or MEMO if name is empty, external id is FITID prefixed with account id.
+// Currency is defaultCurrency if statement has no CURDEF.
+func ReadOFX(r io.Reader, defaultCurrency string) ([]domain.ImportedOperation, []RowError, error) {
+	text, _, err := readText(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	start := strings.Index(strings.ToUpper(text), "<OFX>")
+	if start < 0 {
+		return nil, nil, ErrInvalidOFX
+	}
+
+	var (
+		txs      []ofxTransaction
+		tx       *ofxTransaction
+		account  string
+		currency = defaultCurrency
+	)
+
+	// SGML leaf elements have no end tags, so value of element is text till the next tag
+	for text = text[start:]; ; {
+		lt := strings.IndexByte(text, '<')
+		if lt < 0 {
+			break
+		}
+
+		gt := strings.IndexByte(text[lt:], '>')
+		if gt < 0 {
+			break
+		}
+
+		tag := strings.ToUpper(strings.TrimSpace(text[lt+1 : lt+gt]))
+		text = text[lt+gt+1:]
+
+		end := strings.IndexByte(text, '<')
+		if end < 0 {
+			end = len(text)
+		}
+
+		value := strings.TrimSpace(html.UnescapeString(text[:end]))
+
+		switch {
+		case tag == "STMTTRN":
+			tx = &ofxTransaction{account: account, currency: currency}
+		case tag == "/STMTTRN":
+			if tx != nil {
+				txs = append(txs, *tx)
+			}
+
+			tx = nil
+		case tx != nil:
+			tx.set(tag, value)
+		case tag == "CURDEF":
+			currency = value
+		case tag == "ACCTID":
+			account = value
+		}
+	}
+
+	if len(txs) == 0 {
+		return nil, nil, ErrEmptyFile
+	}
+
+	var (
+		ops  = make([]domain.ImportedOperation, 0, len(txs))
+		errs []RowError
+	)
+
+	for i, tx := range txs {
+		op, err := tx.operation()
+		if err != nil {
+			errs = append(errs, RowError{Row: i + 1, Err: err})
+			continue
+		}
+
+		ops = append(ops, op)
+	}
+
+	return ops, errs, nil
+}
+
+func (tx *ofxTransaction) set(tag, value string) {
+	switch tag {
+	case "FITID":
+		tx.fitID = value
+	case "DTPOSTED":
+		tx.posted = value
+	case "TRNAMT":
+		tx.amount = value
+	case "NAME":
+		tx.name = value
+	case "MEMO":
+		tx.memo = value
+	}
+}
+
+func (tx ofxTransaction) operation() (domain.ImportedOperation, error) {
+	op := domain.ImportedOperation{
+		Name:     truncate(tx.name, domain.MaxNameLen),
+		Currency: strings.ToUpper(tx.currency),
+	}
+
+	if op.Name == "" {
+		op.Name = truncate(tx.memo, domain.MaxNameLen)
+	}
+
+	if op.Name == "" {
+		return domain.ImportedOperation{}, ErrEmptyName
+	}
+
+	if tx.fitID == "" {
+		return domain.ImportedOperation{}, ErrNoTransactionID
+	}
+
+	op.ExternalID = tx.fitID
+	if tx.account != "" {
+		op.ExternalID = tx.account + ":" + tx.fitID
+	}
+
+	if !domain.IsCurrency(op.Currency) {
+		return domain.ImportedOperation{}, fmt.Errorf("%w: %s", ErrInvalidCurrency, op.Currency)
+	}
+
+	// date time is YYYYMMDDHHMMSS.XXX[offset:TZ], only date is used
+	if len(tx.posted) < 8 {
+		return domain.ImportedOperation{}, fmt.Errorf("%w: %s", ErrInvalidDate, tx.posted)
+	}
+
+	var err error
+
+	op.OccuredAt, err = time.ParseInLocation("20060102", tx.posted[:8], time.Local)
+	if err != nil {
+		return domain.ImportedOperation{}, fmt.Errorf("%w: %s", ErrInvalidDate, tx.posted)
+	}
+
+	op.Money, err = money.Parse(tx.amount, op.Currency)
+	if err != nil {
+		return domain.ImportedOperation{}, fmt.Errorf("%w: %s", ErrInvalidAmount, tx.amount)
+	}
+
+	if op.Money == 0 {
+		return domain.ImportedOperation{}, ErrZeroAmount
+	}
+
+	return op, nil
+}
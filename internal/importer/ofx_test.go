@@ -0,0 +1,65 @@
+package importer
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ysomad/financer/internal/domain"
+)
+
+func TestReadOFX(t *testing.T) {
+	f, err := os.Open("testdata/statement.ofx")
+	require.NoError(t, err)
+	defer f.Close()
+
+	ops, errs, err := ReadOFX(f, "RUB")
+	require.NoError(t, err)
+	require.Equal(t, []domain.ImportedOperation{
+		{
+			Name:       "STARBUCKS #123",
+			Currency:   "USD",
+			Money:      -1250,
+			OccuredAt:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local),
+			ExternalID: "1234567890:202403011",
+		},
+		{
+			Name:       "ACME Corp & Co payroll",
+			Currency:   "USD",
+			Money:      250000,
+			OccuredAt:  time.Date(2024, 3, 2, 0, 0, 0, 0, time.Local),
+			ExternalID: "1234567890:202403022",
+		},
+	}, ops)
+	require.Len(t, errs, 1)
+	require.Equal(t, 3, errs[0].Row)
+	require.ErrorIs(t, errs[0], ErrNoTransactionID)
+}
+
+func TestReadQFX(t *testing.T) {
+	f, err := os.Open("testdata/statement.qfx")
+	require.NoError(t, err)
+	defer f.Close()
+
+	ops, errs, err := ReadOFX(f, "RUB")
+	require.NoError(t, err)
+	require.Empty(t, errs)
+	require.Equal(t, []domain.ImportedOperation{{
+		Name:       "Lidl",
+		Currency:   "EUR",
+		Money:      -123456,
+		OccuredAt:  time.Date(2024, 3, 10, 0, 0, 0, 0, time.Local),
+		ExternalID: "4111-XXXX:TX-1",
+	}}, ops)
+}
+
+func TestReadOFXInvalid(t *testing.T) {
+	_, _, err := ReadOFX(strings.NewReader("date;amount\n"), "RUB")
+	require.ErrorIs(t, err, ErrInvalidOFX)
+
+	_, _, err = ReadOFX(strings.NewReader("<OFX><BANKMSGSRSV1></BANKMSGSRSV1></OFX>"), "RUB")
+	require.ErrorIs(t, err, ErrEmptyFile)
+}
@@ -0,0 +1,197 @@
+package importer
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/ysomad/financer/internal/domain"
+)
+
+var (
+	ErrInvalidQIF  = errors.New("invalid qif")
+	ErrQIFTransfer = errors.New("transfer between accounts is not imported")
+)
+
+// qifLayouts are date formats of QIF files, month goes before day in ambiguous dates as in US locale
+// which is default for QIF. Apostrophe before year is replaced with slash before parsing.
+var qifLayouts = []string{
+	"1/2/2006",
+	"1/2/06",
+	"2/1/2006",
+	"2/1/06",
+	"2.1.2006",
+	"2.1.06",
+	"2006-01-02",
+}
+
+// qifSections are types of QIF sections with bank and cash transactions.
+var qifSections = map[string]bool{
+	"BANK":  true,
+	"CASH":  true,
+	"CCARD": true,
+}
+
+// qifRecord is transaction of QIF file, fields are lines starting with field code.
+type qifRecord struct {
+	line     int
+	date     string
+	amount   string
+	payee    string
+	memo     string
+	category string
+}
+
+// ReadQIF reads transactions of bank, cash and credit card sections of QIF file in currency,
+// other sections are skipped. Operation name is payee or memo if there is no payee, category is
+// the last part of L field without class, e.g. "Groceries" of "Food:Groceries/Vacation".
+// Transfers between accounts are returned as errors.
+func ReadQIF(r io.Reader, currency string) ([]domain.ImportedOperation, []RowError, error) {
+	text, _, err := readText(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		records []qifRecord
+		rec     = qifRecord{}
+		section = ""
+		header  = false
+		sc      = bufio.NewScanner(strings.NewReader(text))
+	)
+
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if line[0] == '!' {
+			header = true
+
+			if t, ok := strings.CutPrefix(strings.ToUpper(strings.TrimSpace(line)), "!TYPE:"); ok {
+				section = strings.TrimSpace(t)
+			} else if strings.EqualFold(strings.TrimSpace(line), "!Account") {
+				section = ""
+			}
+
+			continue
+		}
+
+		if !qifSections[section] {
+			continue
+		}
+
+		if rec.line == 0 {
+			rec.line = n
+		}
+
+		value := strings.TrimSpace(line[1:])
+
+		switch line[0] {
+		case 'D':
+			rec.date = value
+		case 'T', 'U':
+			rec.amount = value
+		case 'P':
+			rec.payee = value
+		case 'M':
+			rec.memo = value
+		case 'L':
+			rec.category = value
+		case '^':
+			records = append(records, rec)
+			rec = qifRecord{}
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if !header {
+		return nil, nil, ErrInvalidQIF
+	}
+
+	if len(records) == 0 {
+		return nil, nil, ErrEmptyFile
+	}
+
+	dates := make([]string, len(records))
+	amounts := make([]string, len(records))
+
+	for i, rec := range records {
+		dates[i] = rec.normalizedDate()
+		amounts[i] = rec.amount
+	}
+
+	layout, _ := detectDateLayout(qifLayouts, dates)
+	decimal := detectAmountDecimal(amounts)
+
+	var (
+		ops  = make([]domain.ImportedOperation, 0, len(records))
+		errs []RowError
+	)
+
+	for _, rec := range records {
+		op, err := rec.operation(layout, decimal, currency)
+		if err != nil {
+			errs = append(errs, RowError{Row: rec.line, Err: err})
+			continue
+		}
+
+		ops = append(ops, op)
+	}
+
+	return ops, errs, nil
+}
+
+// normalizedDate returns date with slash instead of apostrophe before year and without spaces,
+// e.g. "1/ 2'24" is "1/2/24".
+func (rec qifRecord) normalizedDate() string {
+	return strings.ReplaceAll(strings.ReplaceAll(rec.date, "'", "/"), " ", "")
+}
+
+func (rec qifRecord) operation(layout string, decimal byte, currency string) (domain.ImportedOperation, error) {
+	if strings.HasPrefix(rec.category, "[") {
+		return domain.ImportedOperation{}, ErrQIFTransfer
+	}
+
+	category, _, _ := strings.Cut(rec.category, "/")
+	if i := strings.LastIndex(category, ":"); i >= 0 {
+		category = category[i+1:]
+	}
+
+	op := domain.ImportedOperation{
+		Name:     truncate(rec.payee, domain.MaxNameLen),
+		Category: truncate(strings.TrimSpace(category), domain.MaxNameLen),
+		Currency: currency,
+	}
+
+	if op.Name == "" {
+		op.Name = truncate(rec.memo, domain.MaxNameLen)
+	}
+
+	if op.Name == "" {
+		return domain.ImportedOperation{}, ErrEmptyName
+	}
+
+	var err error
+
+	op.OccuredAt, err = parseDate(rec.normalizedDate(), layout)
+	if err != nil {
+		return domain.ImportedOperation{}, err
+	}
+
+	op.Money, err = parseAmount(rec.amount, currency, decimal)
+	if err != nil {
+		return domain.ImportedOperation{}, err
+	}
+
+	if op.Money == 0 {
+		return domain.ImportedOperation{}, ErrZeroAmount
+	}
+
+	return op, nil
+}
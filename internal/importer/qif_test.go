@@ -0,0 +1,54 @@
+package importer
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ysomad/financer/internal/domain"
+)
+
+func TestReadQIF(t *testing.T) {
+	f, err := os.Open("testdata/statement.qif")
+	require.NoError(t, err)
+	defer f.Close()
+
+	ops, errs, err := ReadQIF(f, "USD")
+	require.NoError(t, err)
+	require.Equal(t, []domain.ImportedOperation{
+		{
+			Name:      "Whole Foods",
+			Category:  "Groceries",
+			Currency:  "USD",
+			Money:     -123450,
+			OccuredAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local),
+		},
+		{
+			Name:      "Payroll",
+			Category:  "Salary",
+			Currency:  "USD",
+			Money:     250000,
+			OccuredAt: time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local),
+		},
+		{
+			Name:      "Starbucks",
+			Currency:  "USD",
+			Money:     -425,
+			OccuredAt: time.Date(2024, 3, 21, 0, 0, 0, 0, time.Local),
+		},
+	}, ops)
+	require.Len(t, errs, 1)
+	require.Equal(t, 22, errs[0].Row)
+	require.ErrorIs(t, errs[0], ErrQIFTransfer)
+}
+
+func TestReadQIFInvalid(t *testing.T) {
+	_, _, err := ReadQIF(strings.NewReader("date;amount\n"), "USD")
+	require.ErrorIs(t, err, ErrInvalidQIF)
+
+	_, _, err = ReadQIF(strings.NewReader("!Type:Cat\nNFood\n^\n"), "USD")
+	require.ErrorIs(t, err, ErrEmptyFile)
+}
@@ -0,0 +1,143 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// MaxScale is maximal number of digits after decimal point of Decimal.
+const MaxScale = 18
+
+var ErrPrecision = errors.New("too many digits after decimal point")
+
+// Decimal is exact decimal number with up to MaxScale digits after decimal point,
+// it is used for amounts of assets which are not limited by minor units of currencies, e.g. BTC or ETH.
+// Zero value is 0.
+type Decimal struct {
+	units *big.Int // value × 10^MaxScale
+}
+
+var scaleFactor = new(big.Int).Exp(big.NewInt(10), big.NewInt(MaxScale), nil)
+
+// ParseDecimal parses a string in format `XX.YY` or `XX,YY` with up to scale digits after decimal point.
+func ParseDecimal(s string, scale int) (Decimal, error) {
+	if scale < 0 || scale > MaxScale {
+		return Decimal{}, fmt.Errorf("invalid decimal scale: %d", scale)
+	}
+
+	s = strings.Replace(s, ",", ".", 1)
+
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimLeft(s, "+-")
+
+	units, decimals, _ := strings.Cut(s, ".")
+	if units == "" && decimals == "" || strings.Contains(decimals, ".") {
+		return Decimal{}, fmt.Errorf("cannot parse decimal value: %v", s)
+	}
+
+	if units == "" {
+		units = "0"
+	}
+
+	if len(strings.TrimRight(decimals, "0")) > scale {
+		return Decimal{}, ErrPrecision
+	}
+
+	decimals = strings.TrimRight(decimals, "0")
+	decimals += strings.Repeat("0", MaxScale-len(decimals))
+
+	v, ok := new(big.Int).SetString(units+decimals, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("cannot parse decimal value: %v", s)
+	}
+
+	if neg {
+		v.Neg(v)
+	}
+
+	return Decimal{units: v}, nil
+}
+
+func (d Decimal) value() *big.Int {
+	if d.units == nil {
+		return new(big.Int)
+	}
+
+	return d.units
+}
+
+// Add returns sum of two decimals.
+func (d Decimal) Add(other Decimal) Decimal {
+	return Decimal{units: new(big.Int).Add(d.value(), other.value())}
+}
+
+// Neg returns decimal with opposite sign.
+func (d Decimal) Neg() Decimal {
+	return Decimal{units: new(big.Int).Neg(d.value())}
+}
+
+// Sign returns -1, 0 or 1 depending on sign of the decimal.
+func (d Decimal) Sign() int {
+	return d.value().Sign()
+}
+
+// IsZero returns true if decimal is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Float returns nearest float64 value of the decimal.
+func (d Decimal) Float() float64 {
+	f, _ := new(big.Rat).SetFrac(d.value(), scaleFactor).Float64()
+	return f
+}
+
+// String returns exact value without trailing zeros after decimal point.
+func (d Decimal) String() string {
+	return d.Format(MaxScale, FormatConfig{})
+}
+
+// Format the decimal value rounded half up to scale digits after decimal point, trailing zeros are trimmed.
+// Symbol is always placed after the value.
+func (d Decimal) Format(scale int, config FormatConfig) string {
+	if config.Decimal == "" {
+		config.Decimal = "."
+	}
+
+	scale = min(max(scale, 0), MaxScale)
+
+	// round half up to scale
+	v := new(big.Int).Abs(d.value())
+	if scale < MaxScale {
+		unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(MaxScale-scale)), nil)
+		v.Add(v, new(big.Int).Rsh(unit, 1))
+		v.Quo(v, unit)
+		v.Mul(v, unit)
+	}
+
+	value := fmt.Sprintf("%0*s", MaxScale+1, v.String())
+	units, decimals := value[:len(value)-MaxScale], strings.TrimRight(value[len(value)-MaxScale:], "0")
+
+	if config.Thousand != "" {
+		for i := len(units) - 3; i > 0; i -= 3 {
+			units = units[:i] + config.Thousand + units[i:]
+		}
+	}
+
+	value = units
+	if decimals != "" {
+		value += config.Decimal + decimals
+	}
+
+	if d.Sign() < 0 && v.Sign() != 0 {
+		value = "-" + value
+	}
+
+	if config.Symbol != "" {
+		value += " " + config.Symbol
+	}
+
+	return value
+}
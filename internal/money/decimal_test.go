@@ -0,0 +1,68 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimal(t *testing.T) {
+	d, err := ParseDecimal("0.123456789012345678", 18)
+	require.NoError(t, err)
+	require.Equal(t, "0.123456789012345678", d.String())
+
+	d, err = ParseDecimal("-1,50", 8)
+	require.NoError(t, err)
+	require.Equal(t, "-1.5", d.String())
+	require.Equal(t, -1, d.Sign())
+
+	d, err = ParseDecimal("12345678901234567890.1", 8)
+	require.NoError(t, err)
+	require.Equal(t, "12345678901234567890.1", d.String())
+
+	// trailing zeros do not exceed scale
+	d, err = ParseDecimal("1.1000", 2)
+	require.NoError(t, err)
+	require.Equal(t, "1.1", d.String())
+
+	_, err = ParseDecimal("0.000000001", 8)
+	require.ErrorIs(t, err, ErrPrecision)
+
+	_, err = ParseDecimal("1.2.3", 8)
+	require.Error(t, err)
+
+	_, err = ParseDecimal("abc", 8)
+	require.Error(t, err)
+}
+
+func TestDecimalAdd(t *testing.T) {
+	a, err := ParseDecimal("0.1", 18)
+	require.NoError(t, err)
+
+	b, err := ParseDecimal("0.2", 18)
+	require.NoError(t, err)
+
+	require.Equal(t, "0.3", a.Add(b).String())
+	require.True(t, a.Add(a.Neg()).IsZero())
+	require.True(t, Decimal{}.IsZero())
+	require.Equal(t, "0", Decimal{}.String())
+}
+
+func TestDecimalFormat(t *testing.T) {
+	d, err := ParseDecimal("1234.56789", 8)
+	require.NoError(t, err)
+
+	require.Equal(t, "1,234.5679 BTC", d.Format(4, FormatConfig{Symbol: "BTC", Thousand: ","}))
+	require.Equal(t, "1235", d.Format(0, FormatConfig{}))
+	require.Equal(t, "-1234,56789", d.Neg().Format(8, FormatConfig{Decimal: ","}))
+
+	d, err = ParseDecimal("-0.000001", 8)
+	require.NoError(t, err)
+	require.Equal(t, "0", d.Format(2, FormatConfig{}))
+}
+
+func TestDecimalFloat(t *testing.T) {
+	d, err := ParseDecimal("0.25", 8)
+	require.NoError(t, err)
+	require.InDelta(t, 0.25, d.Float(), 1e-12)
+}
@@ -1,9 +1,36 @@
 package money
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"slices"
 	"strconv"
 	"strings"
+
+	"github.com/rmg/iso4217"
+)
+
+// DefaultExponent is number of minor unit digits of unknown currencies.
+const DefaultExponent = 2
+
+var (
+	ErrOverflow       = errors.New("money overflow")
+	ErrDivisionByZero = errors.New("money division by zero")
+	ErrInvalidRatio   = errors.New("ratios must be non-negative and not all zero")
+)
+
+// RoundingMode is a way to round result of division to minor units.
+type RoundingMode uint8
+
+const (
+	// RoundHalfUp rounds half away from zero, 2.5 is 3 and -2.5 is -3.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds half to the nearest even value (banker's rounding), 2.5 is 2 and 3.5 is 4.
+	RoundHalfEven
+	// RoundDown truncates towards zero.
+	RoundDown
 )
 
 type FormatConfig struct {
@@ -14,66 +41,86 @@ type FormatConfig struct {
 	ForceDecimals bool
 }
 
-type Money int32
+// Money is amount in minor units of currency, e.g. cents for USD, yens for JPY and fils for KWD.
+type Money int64
+
+// Exponent returns number of minor unit digits of ISO 4217 currency.
+func Exponent(currency string) int {
+	code, minor := iso4217.ByName(strings.ToUpper(currency))
+	if code == 0 || minor < 0 {
+		return DefaultExponent
+	}
+
+	return minor
+}
 
-// Parse a string to create a new money value. It can read `XX.YY` and `XX,YY`.
-// An empty string is parsed as zero.
-func Parse(s string) (Money, error) {
+// Parse a string to create a new money value in currency. It can read `XX.YY` and `XX,YY`,
+// digits after currency exponent are truncated. An empty string is parsed as zero.
+func Parse(s, currency string) (Money, error) {
 	if len(s) == 0 {
 		return Money(0), nil
 	}
 
+	exp := Exponent(currency)
 	s = strings.Replace(s, ",", ".", -1)
 
-	var amount int32
-	switch parts := strings.Split(s, "."); len(parts) {
-	case 1:
-		var err error
-		units, err := strconv.ParseInt(parts[0], 10, 64)
-		if err != nil {
-			return Money(0), err
-		}
-		amount = int32(units) * 100
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimLeft(s, "+-")
 
-	case 2:
-		units, err := strconv.ParseInt(parts[0], 10, 64)
-		if err != nil {
-			return Money(0), err
-		}
-		if len(parts[1]) > 2 {
-			parts[1] = parts[1][:2]
-		}
-		decimals, err := strconv.ParseInt(parts[1], 10, 64)
-		if err != nil {
-			return Money(0), err
-		}
-		if len(parts[1]) == 1 {
-			amount = int32(units)*100 + int32(decimals)*10
-		} else {
-			amount = int32(units)*100 + int32(decimals)
+	units, decimals, _ := strings.Cut(s, ".")
+	if units == "" && decimals == "" || strings.Contains(decimals, ".") {
+		return Money(0), fmt.Errorf("cannot parse money value: %v", s)
+	}
+
+	if units == "" {
+		units = "0"
+	}
+
+	if len(decimals) > exp {
+		decimals = decimals[:exp]
+	}
+
+	decimals += strings.Repeat("0", exp-len(decimals))
+
+	amount, err := strconv.ParseInt(units+decimals, 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return Money(0), ErrOverflow
 		}
 
-	default:
-		return Money(0), fmt.Errorf("cannot parse money value: %v", s)
+		return Money(0), err
+	}
+
+	if neg {
+		amount = -amount
 	}
 
 	return Money(amount), nil
 }
 
-func (m Money) Format(config FormatConfig) string {
+// Format the money value in currency.
+func (m Money) Format(currency string, config FormatConfig) string {
 	if config.Decimal == "" {
 		config.Decimal = "."
 	}
 
-	value := strconv.FormatInt(int64(m), 10)
-	value = fmt.Sprintf("%03v", value)
+	exp := Exponent(currency)
+
+	value := strconv.FormatUint(uint64(m.abs()), 10)
+	value = fmt.Sprintf("%0*s", exp+1, value)
+
+	units, decimals := value[:len(value)-exp], value[len(value)-exp:]
+
 	if config.Thousand != "" {
-		for i := len(value) - 5; i > 0; i -= 3 {
-			value = value[:i] + config.Thousand + value[i:]
+		for i := len(units) - 3; i > 0; i -= 3 {
+			units = units[:i] + config.Thousand + units[i:]
 		}
 	}
 
-	value = value[:len(value)-2] + config.Decimal + value[len(value)-2:]
+	value = units
+	if exp > 0 && (config.ForceDecimals || strings.Trim(decimals, "0") != "") {
+		value += config.Decimal + decimals
+	}
 
 	if config.Symbol != "" {
 		if config.Prefix {
@@ -83,21 +130,37 @@ func (m Money) Format(config FormatConfig) string {
 		}
 	}
 
-	if !config.ForceDecimals {
-		value = strings.TrimSuffix(value, config.Decimal+"00")
+	// sign goes before prefix symbol, e.g. -$1.50
+	if m < 0 {
+		value = "-" + value
 	}
 
 	return value
 }
 
-// Format the money value with the default configuration
-func (m Money) String() string {
-	return m.Format(FormatConfig{})
+// Text formats the money value in currency with the default configuration.
+func (m Money) Text(currency string) string {
+	return m.Format(currency, FormatConfig{})
+}
+
+// Float returns money value in major units of currency.
+func (m Money) Float(currency string) float64 {
+	return float64(m) / math.Pow10(Exponent(currency))
 }
 
-// Cents returns the value with cents precision (2 decimal places) as a number.
-func (m Money) Cents() int32 {
-	return int32(m)
+// FromFloat returns money value in currency from major units rounded to minor units.
+func FromFloat(f float64, currency string) (Money, error) {
+	f = math.Round(f * math.Pow10(Exponent(currency)))
+	if f >= math.MaxInt64 || f < math.MinInt64 || math.IsNaN(f) {
+		return 0, ErrOverflow
+	}
+
+	return Money(f), nil
+}
+
+// MinorUnits returns the value in minor units of currency.
+func (m Money) MinorUnits() int64 {
+	return int64(m)
 }
 
 // IsZero returns true if there is no money.
@@ -105,32 +168,168 @@ func (m Money) IsZero() bool {
 	return m == 0
 }
 
+// Abs returns absolute money value.
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+
+	return m
+}
+
+// abs returns absolute value without overflow on minimal value.
+func (m Money) abs() uint64 {
+	if m < 0 {
+		return uint64(-(m + 1)) + 1
+	}
+
+	return uint64(m)
+}
+
 // LessThan returns true if a money value is less than the other.
 func (m Money) LessThan(other Money) bool {
 	return m < other
 }
 
 // Mul multiplies the money value n times and returns the result.
-func (m Money) Mul(n int32) Money {
-	return Money(int32(m) * n)
+func (m Money) Mul(n int64) (Money, error) {
+	if m == 0 || n == 0 {
+		return 0, nil
+	}
+
+	res := int64(m) * n
+	if res/n != int64(m) || (n == -1 && m == math.MinInt64) {
+		return 0, ErrOverflow
+	}
+
+	return Money(res), nil
 }
 
 // Add two money values together and returns the result.
-func (m Money) Add(other Money) Money {
-	return Money(int32(m) + int32(other))
+func (m Money) Add(other Money) (Money, error) {
+	res := m + other
+	if (other > 0 && res < m) || (other < 0 && res > m) {
+		return 0, ErrOverflow
+	}
+
+	return res, nil
 }
 
 // Sub subtracts two money values and returns the result.
-func (m Money) Sub(other Money) Money {
-	return Money(int32(m) - int32(other))
+func (m Money) Sub(other Money) (Money, error) {
+	res := m - other
+	if (other > 0 && res > m) || (other < 0 && res < m) {
+		return 0, ErrOverflow
+	}
+
+	return res, nil
+}
+
+// Div divides the money value by n and rounds the result with mode.
+func (m Money) Div(n int64, mode RoundingMode) (Money, error) {
+	return m.Ratio(1, n, mode)
+}
+
+// Ratio returns num/den part of the money value rounded with mode,
+// intermediate product is not limited by int64.
+func (m Money) Ratio(num, den int64, mode RoundingMode) (Money, error) {
+	if den == 0 {
+		return 0, ErrDivisionByZero
+	}
+
+	d := big.NewInt(den)
+	q, r := new(big.Int).QuoRem(new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(num)), d, new(big.Int))
+
+	if r.Sign() != 0 && mode != RoundDown {
+		// compare remainder with half of divisor
+		c := new(big.Int).Lsh(r.Abs(r), 1).CmpAbs(d)
+
+		if c > 0 || (c == 0 && (mode == RoundHalfUp || q.Bit(0) == 1)) {
+			// round away from zero, remainder has sign of the dividend
+			if (int64(m) < 0) != (num < 0) != (den < 0) {
+				q.Sub(q, big.NewInt(1))
+			} else {
+				q.Add(q, big.NewInt(1))
+			}
+		}
+	}
+
+	if !q.IsInt64() {
+		return 0, ErrOverflow
+	}
+
+	return Money(q.Int64()), nil
 }
 
-// Div divides two money values and returns the result.
-func (m Money) Div(other Money) Money {
-	return Money(int32(m) / int32(other))
+// Percent returns percent of the money value rounded with mode.
+func (m Money) Percent(percent int64, mode RoundingMode) (Money, error) {
+	return m.Ratio(percent, 100, mode)
 }
 
-// AddTaxPercent adds a percentage of the price to itself.
-func (m Money) AddTaxPercent(tax int32) Money {
-	return m.Mul(tax).Div(Money(100)).Add(m)
+// AddTaxPercent adds a percentage of the price to itself, tax is rounded with mode.
+func (m Money) AddTaxPercent(tax int64, mode RoundingMode) (Money, error) {
+	taxed, err := m.Percent(tax, mode)
+	if err != nil {
+		return 0, err
+	}
+
+	return m.Add(taxed)
+}
+
+// Allocate splits the money value in parts proportional to ratios, parts always sum up to the money value.
+// Remainder is distributed by one minor unit to parts with the biggest fraction,
+// ties are resolved by order of ratios.
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	var total int64
+
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, ErrInvalidRatio
+		}
+
+		total += r
+		if total < 0 {
+			return nil, ErrOverflow
+		}
+	}
+
+	if total == 0 {
+		return nil, ErrInvalidRatio
+	}
+
+	var (
+		parts     = make([]Money, len(ratios))
+		fractions = make([]*big.Int, len(ratios))
+		order     = make([]int, len(ratios))
+		allocated = m.abs()
+		t         = big.NewInt(total)
+		amount    = new(big.Int).SetUint64(m.abs())
+	)
+
+	for i, r := range ratios {
+		q, rem := new(big.Int).QuoRem(new(big.Int).Mul(amount, big.NewInt(r)), t, new(big.Int))
+
+		// part is not bigger than the money value
+		parts[i] = Money(q.Uint64())
+		fractions[i] = rem
+		order[i] = i
+		allocated -= q.Uint64()
+	}
+
+	// stable sort keeps order of parts with the same fraction
+	slices.SortStableFunc(order, func(a, b int) int {
+		return fractions[b].Cmp(fractions[a])
+	})
+
+	for _, i := range order[:allocated] {
+		parts[i]++
+	}
+
+	if m < 0 {
+		for i := range parts {
+			parts[i] = -parts[i]
+		}
+	}
+
+	return parts, nil
 }
@@ -2,48 +2,53 @@ package money
 
 import (
 	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
 	"testing"
 
+	"github.com/rmg/iso4217"
 	"github.com/stretchr/testify/require"
 )
 
 func TestParse(t *testing.T) {
-	money, err := Parse("125,79")
+	money, err := Parse("125,79", "USD")
 	require.NoError(t, err)
 
 	require.EqualValues(t, money, 12579)
 }
 
 func TestParseMultipleDecimals(t *testing.T) {
-	money, err := Parse("125.7923")
+	money, err := Parse("125.7923", "USD")
 	require.NoError(t, err)
 
 	require.EqualValues(t, money, 12579)
 }
 
 func TestParseMultipleDecimalsNoRound(t *testing.T) {
-	money, err := Parse("125.7963")
+	money, err := Parse("125.7963", "USD")
 	require.NoError(t, err)
 
 	require.EqualValues(t, money, 12579)
 }
 
 func TestParseFloatError(t *testing.T) {
-	money, err := Parse("10.03")
+	money, err := Parse("10.03", "USD")
 	require.NoError(t, err)
 
 	require.EqualValues(t, money, 1003)
 }
 
 func TestParseOneDecimal(t *testing.T) {
-	money, err := Parse("10.3")
+	money, err := Parse("10.3", "USD")
 	require.NoError(t, err)
 
 	require.EqualValues(t, money, 1030)
 }
 
 func TestParseOneDecimalWithZero(t *testing.T) {
-	money, err := Parse("10.30")
+	money, err := Parse("10.30", "USD")
 	require.NoError(t, err)
 
 	require.EqualValues(t, money, 1030)
@@ -56,7 +61,7 @@ func TestParseFloatErrorIterative(t *testing.T) {
 			prefix = "0"
 		}
 		s := fmt.Sprintf("%v.%s%v", i/100, prefix, i%100)
-		money, err := Parse(s)
+		money, err := Parse(s, "USD")
 		require.NoError(t, err)
 
 		require.EqualValues(t, money, i, "i: %v; s: %v", i, s)
@@ -64,7 +69,7 @@ func TestParseFloatErrorIterative(t *testing.T) {
 }
 
 func TestParseWithoutDecimals(t *testing.T) {
-	money, err := Parse("10")
+	money, err := Parse("10", "USD")
 	require.NoError(t, err)
 
 	require.EqualValues(t, money, 1000)
@@ -73,13 +78,13 @@ func TestParseWithoutDecimals(t *testing.T) {
 func TestFormatDefault(t *testing.T) {
 	money := Money(12345)
 
-	require.EqualValues(t, money.Format(FormatConfig{}), "123.45")
+	require.EqualValues(t, money.Format("USD", FormatConfig{}), "123.45")
 }
 
 func TestFormatSymbol(t *testing.T) {
 	money := Money(12345)
 
-	require.EqualValues(t, money.Format(FormatConfig{
+	require.EqualValues(t, money.Format("USD", FormatConfig{
 		Symbol:        "€",
 		Thousand:      ",",
 		ForceDecimals: false,
@@ -89,7 +94,7 @@ func TestFormatSymbol(t *testing.T) {
 func TestFormatSymbolPrefix(t *testing.T) {
 	money := Money(12345)
 
-	require.EqualValues(t, money.Format(FormatConfig{
+	require.EqualValues(t, money.Format("USD", FormatConfig{
 		Symbol:   "$",
 		Prefix:   true,
 		Thousand: ",",
@@ -103,13 +108,13 @@ func TestFormatNegativeSymbolPrefix(t *testing.T) {
 		Symbol: "$",
 		Prefix: true,
 	}
-	require.EqualValues(t, money.Format(cnf), "$-123.45")
+	require.EqualValues(t, money.Format("USD", cnf), "-$123.45")
 }
 
 func TestFormatThousand(t *testing.T) {
 	money := Money(123456)
 
-	require.EqualValues(t, money.Format(FormatConfig{
+	require.EqualValues(t, money.Format("USD", FormatConfig{
 		Symbol:        "€",
 		Thousand:      ",",
 		ForceDecimals: false,
@@ -119,13 +124,13 @@ func TestFormatThousand(t *testing.T) {
 func TestFormatCents(t *testing.T) {
 	money := Money(12)
 
-	require.EqualValues(t, money.Format(FormatConfig{}), "0.12")
+	require.EqualValues(t, money.Format("USD", FormatConfig{}), "0.12")
 }
 
 func TestFormatZeroDecimals(t *testing.T) {
 	money := Money(100)
 
-	require.EqualValues(t, money.Format(FormatConfig{}), "1")
+	require.EqualValues(t, money.Format("USD", FormatConfig{}), "1")
 }
 
 func TestFormatForceDecimals(t *testing.T) {
@@ -134,7 +139,7 @@ func TestFormatForceDecimals(t *testing.T) {
 	cnf := FormatConfig{
 		ForceDecimals: true,
 	}
-	require.EqualValues(t, money.Format(cnf), "1.00")
+	require.EqualValues(t, money.Format("USD", cnf), "1.00")
 }
 
 func TestLessThan(t *testing.T) {
@@ -154,31 +159,229 @@ func TestIsZero(t *testing.T) {
 }
 
 func TestMul(t *testing.T) {
-	money := Money(1000)
+	money, err := Money(1000).Mul(3)
+	require.NoError(t, err)
+	require.EqualValues(t, money, 3000)
+
+	_, err = Money(math.MaxInt64 / 2).Mul(3)
+	require.ErrorIs(t, err, ErrOverflow)
 
-	require.EqualValues(t, money.Mul(3), 3000)
+	_, err = Money(math.MinInt64).Mul(-1)
+	require.ErrorIs(t, err, ErrOverflow)
 }
 
 func TestAdd(t *testing.T) {
-	money := Money(10000)
+	money, err := Money(10000).Add(Money(5000))
+	require.NoError(t, err)
+	require.EqualValues(t, money, 15000)
+
+	_, err = Money(math.MaxInt64).Add(Money(1))
+	require.ErrorIs(t, err, ErrOverflow)
 
-	require.EqualValues(t, money.Add(Money(5000)), 15000)
+	_, err = Money(math.MinInt64).Add(Money(-1))
+	require.ErrorIs(t, err, ErrOverflow)
 }
 
 func TestSub(t *testing.T) {
-	money := Money(10000)
+	money, err := Money(10000).Sub(Money(4000))
+	require.NoError(t, err)
+	require.EqualValues(t, money, 6000)
 
-	require.EqualValues(t, money.Sub(Money(4000)), 6000)
+	_, err = Money(math.MinInt64).Sub(Money(1))
+	require.ErrorIs(t, err, ErrOverflow)
 }
 
 func TestDiv(t *testing.T) {
-	money := Money(1234)
+	money, err := Money(1234).Div(2, RoundHalfUp)
+	require.NoError(t, err)
+	require.EqualValues(t, money, 617)
 
-	require.EqualValues(t, money.Div(Money(2)), int32(617))
+	_, err = Money(1234).Div(0, RoundHalfUp)
+	require.ErrorIs(t, err, ErrDivisionByZero)
+}
+
+func TestRatioRounding(t *testing.T) {
+	tests := []struct {
+		money Money
+		den   int64
+		mode  RoundingMode
+		want  Money
+	}{
+		{25, 10, RoundHalfUp, 3},
+		{25, 10, RoundHalfEven, 2},
+		{35, 10, RoundHalfEven, 4},
+		{26, 10, RoundHalfEven, 3},
+		{29, 10, RoundDown, 2},
+		{-25, 10, RoundHalfUp, -3},
+		{-25, 10, RoundHalfEven, -2},
+		{-26, 10, RoundHalfEven, -3},
+		{25, -10, RoundHalfUp, -3},
+	}
+
+	for _, tt := range tests {
+		money, err := tt.money.Ratio(1, tt.den, tt.mode)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, money, "%d / %d", tt.money, tt.den)
+	}
+}
+
+func TestRatioOverflow(t *testing.T) {
+	// intermediate product does not overflow
+	money, err := Money(math.MaxInt64).Ratio(3, 4, RoundDown)
+	require.NoError(t, err)
+	require.EqualValues(t, money, math.MaxInt64/4*3+2)
+
+	_, err = Money(math.MaxInt64).Ratio(3, 2, RoundDown)
+	require.ErrorIs(t, err, ErrOverflow)
 }
 
 func TestAddTaxPercent(t *testing.T) {
-	money := Money(10000)
+	money, err := Money(10000).AddTaxPercent(20, RoundHalfUp)
+	require.NoError(t, err)
+	require.EqualValues(t, money, 12000)
+
+	money, err = Money(1250).AddTaxPercent(10, RoundHalfUp)
+	require.NoError(t, err)
+	require.EqualValues(t, money, 1375)
+
+	money, err = Money(1250).AddTaxPercent(10, RoundHalfEven)
+	require.NoError(t, err)
+	require.EqualValues(t, money, 1375)
+
+	money, err = Money(1150).AddTaxPercent(10, RoundHalfEven)
+	require.NoError(t, err)
+	require.EqualValues(t, money, 1265)
+}
+
+func TestAllocate(t *testing.T) {
+	parts, err := Money(100).Allocate(1, 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, []Money{34, 33, 33}, parts)
+
+	parts, err = Money(-100).Allocate(1, 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, []Money{-34, -33, -33}, parts)
+
+	parts, err = Money(5).Allocate(3, 7)
+	require.NoError(t, err)
+	require.Equal(t, []Money{2, 3}, parts)
+
+	parts, err = Money(1000).Allocate(0, 1)
+	require.NoError(t, err)
+	require.Equal(t, []Money{0, 1000}, parts)
+
+	parts, err = Money(math.MaxInt64).Allocate(1, 1)
+	require.NoError(t, err)
+	require.Equal(t, []Money{math.MaxInt64/2 + 1, math.MaxInt64 / 2}, parts)
+
+	_, err = Money(100).Allocate()
+	require.ErrorIs(t, err, ErrInvalidRatio)
+
+	_, err = Money(100).Allocate(1, -1)
+	require.ErrorIs(t, err, ErrInvalidRatio)
+}
+
+func TestAbs(t *testing.T) {
+	require.EqualValues(t, Money(-1234).Abs(), 1234)
+	require.EqualValues(t, Money(1234).Abs(), 1234)
+}
 
-	require.EqualValues(t, money.AddTaxPercent(20), 12000)
+func TestParseNegative(t *testing.T) {
+	money, err := Parse("-10.5", "USD")
+	require.NoError(t, err)
+
+	require.EqualValues(t, money, -1050)
+}
+
+func TestParseLarge(t *testing.T) {
+	money, err := Parse("250000000.50", "RUB")
+	require.NoError(t, err)
+
+	require.EqualValues(t, money, 25000000050)
+
+	_, err = Parse("100000000000000000000", "RUB")
+	require.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestParseExponent(t *testing.T) {
+	money, err := Parse("1500.7", "JPY")
+	require.NoError(t, err)
+	require.EqualValues(t, money, 1500)
+
+	money, err = Parse("12.3456", "KWD")
+	require.NoError(t, err)
+	require.EqualValues(t, money, 12345)
+
+	money, err = Parse("1", "CLF")
+	require.NoError(t, err)
+	require.EqualValues(t, money, 10000)
+
+	_, err = Parse("1.2.3", "USD")
+	require.Error(t, err)
+}
+
+func TestFormatExponent(t *testing.T) {
+	require.Equal(t, "1500", Money(1500).Text("JPY"))
+	require.Equal(t, "12.345", Money(12345).Text("KWD"))
+	require.Equal(t, "12.300", Money(12300).Format("KWD", FormatConfig{ForceDecimals: true}))
+	require.Equal(t, "-0.05", Money(-5).Text("EUR"))
+	require.Equal(t, "-92233720368547758.08", Money(math.MinInt64).Text("USD"))
+}
+
+func TestExponent(t *testing.T) {
+	require.Equal(t, 2, Exponent("USD"))
+	require.Equal(t, 0, Exponent("JPY"))
+	require.Equal(t, 3, Exponent("KWD"))
+	require.Equal(t, 2, Exponent("XYZ"))
+}
+
+func TestFloat(t *testing.T) {
+	require.InDelta(t, 12.345, Money(12345).Float("KWD"), 1e-9)
+
+	money, err := FromFloat(12.3456, "USD")
+	require.NoError(t, err)
+	require.EqualValues(t, money, 1235)
+}
+
+func TestSymbol(t *testing.T) {
+	s, ok := Symbol("rub")
+	require.True(t, ok)
+	require.Equal(t, "₽", s)
+
+	s, ok = Symbol("CZK")
+	require.False(t, ok)
+	require.Equal(t, "CZK", s)
+}
+
+// TestExponentMigration checks that migration of money to minor units uses the same exponents as Exponent.
+func TestExponentMigration(t *testing.T) {
+	sql, err := os.ReadFile("../../migrations/20261019210000_money_bigint.sql")
+	require.NoError(t, err)
+
+	shifts := make(map[string]int)
+	re := regexp.MustCompile(`WHEN currency IN \(([^)]*)\) THEN (-?\d+)`)
+
+	for _, m := range re.FindAllStringSubmatch(string(sql), -1) {
+		shift, err := strconv.Atoi(m[2])
+		require.NoError(t, err)
+
+		for _, cur := range regexp.MustCompile(`'([A-Z]{3})'`).FindAllStringSubmatch(m[1], -1) {
+			if prev, ok := shifts[cur[1]]; ok {
+				require.Equal(t, prev, shift, cur[1])
+			}
+
+			shifts[cur[1]] = shift
+		}
+	}
+
+	require.NotEmpty(t, shifts)
+
+	for n := 0; n < 1000; n++ {
+		cur, _ := iso4217.ByCode(n)
+		if cur == "" {
+			continue
+		}
+
+		require.Equal(t, Exponent(cur)-DefaultExponent, shifts[cur], cur)
+	}
 }
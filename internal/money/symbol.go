@@ -0,0 +1,36 @@
+package money
+
+import "strings"
+
+var symbols = map[string]string{
+	"AMD": "֏",
+	"AZN": "₼",
+	"BYN": "Br",
+	"CNY": "¥",
+	"EUR": "€",
+	"GBP": "£",
+	"GEL": "₾",
+	"ILS": "₪",
+	"INR": "₹",
+	"JPY": "¥",
+	"KRW": "₩",
+	"KZT": "₸",
+	"PLN": "zł",
+	"RUB": "₽",
+	"THB": "฿",
+	"TRY": "₺",
+	"UAH": "₴",
+	"USD": "$",
+	"VND": "₫",
+}
+
+// Symbol returns currency symbol, currency code is returned for currencies without widely known symbol.
+func Symbol(currency string) (string, bool) {
+	currency = strings.ToUpper(currency)
+
+	if s, ok := symbols[currency]; ok {
+		return s, true
+	}
+
+	return currency, false
+}
@@ -0,0 +1,259 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+	"github.com/ysomad/financer/internal/postgres/pgclient"
+)
+
+type AccountStorage struct {
+	*pgclient.Client
+}
+
+type account struct {
+	ID             string      `db:"id"`
+	UID            int64       `db:"user_id"`
+	Name           string      `db:"name"`
+	Currency       string      `db:"currency"`
+	OpeningBalance money.Money `db:"opening_balance"`
+	IsDefault      bool        `db:"is_default"`
+}
+
+const accountColumns = "a.id id, a.user_id user_id, a.name name, a.currency currency, " +
+	"a.opening_balance opening_balance, a.is_default is_default"
+
+// usedAccount is account with flag of operations or reconciliations in it.
+type usedAccount struct {
+	account
+	Used bool `db:"used"`
+}
+
+const accountUsed = "EXISTS (SELECT 1 FROM operations o WHERE o.account_id = a.id OR o.to_account_id = a.id) " +
+	"OR EXISTS (SELECT 1 FROM reconciliations r WHERE r.account_id = a.id) used"
+
+type SaveAccountParams struct {
+	ID             string
+	UID            int64
+	Name           string
+	Currency       string
+	OpeningBalance money.Money
+	IsDefault      bool
+	CreatedAt      time.Time
+}
+
+func (p SaveAccountParams) insert(b sq.StatementBuilderType) (string, []any, error) {
+	return b.
+		Insert("accounts").
+		Columns("id, user_id, name, currency, opening_balance, is_default, created_at").
+		Values(p.ID, p.UID, p.Name, p.Currency, p.OpeningBalance, p.IsDefault, p.CreatedAt).
+		ToSql()
+}
+
+func (s *AccountStorage) Save(ctx context.Context, p SaveAccountParams) error {
+	sql, args, err := p.insert(s.Builder)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.Pool.Exec(ctx, sql, args...); err != nil {
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return ErrAlreadyExists
+		}
+
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+func (s *AccountStorage) ListByUserID(ctx context.Context, uid int64) ([]domain.Account, error) {
+	return s.list(ctx, sq.Eq{"a.user_id": uid})
+}
+
+// FindByName finds account of user by case insensitive name.
+func (s *AccountStorage) FindByName(ctx context.Context, uid int64, name string) (domain.Account, error) {
+	return s.find(ctx, sq.And{
+		sq.Eq{"a.user_id": uid},
+		sq.Expr("lower(a.name) = lower(?)", name),
+	})
+}
+
+func (s *AccountStorage) FindByID(ctx context.Context, uid int64, accountID string) (domain.Account, error) {
+	return s.find(ctx, sq.Eq{"a.user_id": uid, "a.id": accountID})
+}
+
+func (s *AccountStorage) FindDefault(ctx context.Context, uid int64) (domain.Account, error) {
+	return s.find(ctx, sq.Eq{"a.user_id": uid, "a.is_default": true})
+}
+
+func (s *AccountStorage) find(ctx context.Context, pred sq.Sqlizer) (domain.Account, error) {
+	accs, err := s.list(ctx, pred)
+	if err != nil {
+		return domain.Account{}, err
+	}
+
+	if len(accs) == 0 {
+		return domain.Account{}, ErrNotFound
+	}
+
+	return accs[0], nil
+}
+
+func (s *AccountStorage) list(ctx context.Context, pred sq.Sqlizer) ([]domain.Account, error) {
+	sql, args, err := s.Builder.
+		Select(accountColumns).
+		From("accounts a").
+		Where(pred).
+		Where(sq.Eq{"a.deleted_at": nil}).
+		OrderBy("a.is_default DESC, a.created_at").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[account])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	accs := make([]domain.Account, len(res))
+	for i, a := range res {
+		accs[i] = domain.Account(a)
+	}
+
+	return accs, nil
+}
+
+// SetDefault makes account default one for operations without explicitly mentioned account.
+func (s *AccountStorage) SetDefault(ctx context.Context, uid int64, accountID string) error {
+	sql1, args1, err := s.Builder.
+		Update("accounts").
+		Set("is_default", false).
+		Where(sq.Eq{"user_id": uid, "is_default": true}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	sql2, args2, err := s.Builder.
+		Update("accounts").
+		Set("is_default", true).
+		Where(sq.Eq{"user_id": uid, "id": accountID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	return pgx.BeginTxFunc(ctx, s.Pool, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, sql1, args1...); err != nil {
+			return fmt.Errorf("default account not reset: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, sql2, args2...); err != nil {
+			return fmt.Errorf("default account not set: %w", err)
+		}
+
+		return nil
+	})
+}
+
+type accountBalance struct {
+	account
+	Balance money.Money `db:"balance"`
+}
+
+// accountMovements is money movements of accounts, transfer operation moves money out of
+// account_id and into to_account_id.
+const accountMovements = "(SELECT account_id, money FROM operations WHERE deleted_at IS NULL " +
+	"UNION ALL SELECT to_account_id, to_money FROM operations WHERE deleted_at IS NULL AND to_account_id IS NOT NULL) m " +
+	"ON m.account_id = a.id"
+
+// ListBalances returns accounts of user with balances which include all not deleted operations.
+func (s *AccountStorage) ListBalances(ctx context.Context, uid int64) ([]domain.AccountBalance, error) {
+	sql, args, err := s.Builder.
+		Select(accountColumns, "(a.opening_balance + coalesce(sum(m.money), 0))::bigint balance").
+		From("accounts a").
+		LeftJoin(accountMovements).
+		Where(sq.Eq{"a.user_id": uid, "a.deleted_at": nil}).
+		GroupBy("a.id").
+		OrderBy("a.is_default DESC, a.created_at").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[accountBalance])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	balances := make([]domain.AccountBalance, len(res))
+	for i, b := range res {
+		balances[i] = domain.AccountBalance{Account: domain.Account(b.account), Balance: b.Balance}
+	}
+
+	return balances, nil
+}
+
+type movement struct {
+	AccountID string      `db:"account_id"`
+	Currency  string      `db:"currency"`
+	Money     money.Money `db:"money"`
+	OccuredAt time.Time   `db:"occured_at"`
+}
+
+// ListMovements returns money movements of all not deleted accounts of user.
+func (s *AccountStorage) ListMovements(ctx context.Context, uid int64) ([]domain.Movement, error) {
+	sql, args, err := s.Builder.
+		Select("m.account_id::text account_id, m.currency, m.money, m.occured_at").
+		FromSelect(s.Builder.
+			Select("account_id, currency, money, occured_at").
+			From("operations").
+			Where(sq.Eq{"user_id": uid, "deleted_at": nil}).
+			Suffix("UNION ALL SELECT to_account_id, to_currency, to_money, occured_at FROM operations "+
+				"WHERE user_id = ? AND deleted_at IS NULL AND to_account_id IS NOT NULL", uid), "m").
+		Join("accounts a ON a.id = m.account_id AND a.deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[movement])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	movements := make([]domain.Movement, len(res))
+	for i, m := range res {
+		movements[i] = domain.Movement(m)
+	}
+
+	return movements, nil
+}
@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+	"github.com/ysomad/financer/internal/postgres/pgclient"
+)
+
+type AssetStorage struct {
+	*pgclient.Client
+}
+
+type asset struct {
+	ID        string    `db:"id"`
+	UID       int64     `db:"user_id"`
+	Ticker    string    `db:"ticker"`
+	Decimals  int       `db:"decimals"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+const assetColumns = "a.id id, a.user_id user_id, a.ticker ticker, a.decimals decimals, a.created_at created_at"
+
+// Save saves asset, returns ErrAlreadyExists if user already has asset with the same ticker.
+func (s *AssetStorage) Save(ctx context.Context, a domain.Asset) error {
+	sql, args, err := s.Builder.
+		Insert("assets").
+		Columns("id, user_id, ticker, decimals, created_at").
+		Values(a.ID, a.UID, a.Ticker, a.Decimals, a.CreatedAt).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.Pool.Exec(ctx, sql, args...); err != nil {
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return ErrAlreadyExists
+		}
+
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+// SavePrice saves price of asset set by user, price of the same date is replaced.
+// Price is rate with ticker as base which is used by user only.
+func (s *AssetStorage) SavePrice(ctx context.Context, uid int64, r domain.ExchangeRate, createdAt time.Time) error {
+	sql, args, err := s.Builder.
+		Insert("asset_prices").
+		Columns("user_id, ticker, quote, date, price, created_at").
+		Values(uid, r.Base, r.Quote, r.Date, r.Rate, createdAt).
+		Suffix("ON CONFLICT (user_id, ticker, quote, date) DO UPDATE SET price = excluded.price, created_at = excluded.created_at").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.Pool.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+func (s *AssetStorage) FindByTicker(ctx context.Context, uid int64, ticker string) (domain.Asset, error) {
+	sql, args, err := s.Builder.
+		Select(assetColumns).
+		From("assets a").
+		Where(sq.Eq{"a.user_id": uid, "a.ticker": ticker}).
+		ToSql()
+	if err != nil {
+		return domain.Asset{}, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return domain.Asset{}, fmt.Errorf("query: %w", err)
+	}
+
+	a, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[asset])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Asset{}, ErrNotFound
+		}
+
+		return domain.Asset{}, fmt.Errorf("scan: %w", err)
+	}
+
+	return domain.Asset(a), nil
+}
+
+type SaveAssetOperationParams struct {
+	ID        string
+	UID       int64
+	AssetID   string
+	Amount    money.Decimal
+	Note      string
+	OccuredAt time.Time
+	CreatedAt time.Time
+}
+
+func (s *AssetStorage) SaveOperation(ctx context.Context, p SaveAssetOperationParams) error {
+	sql, args, err := s.Builder.
+		Insert("asset_operations").
+		Columns("id, user_id, asset_id, amount, note, occured_at, created_at").
+		// decimal is passed as text to keep it exact
+		Values(p.ID, p.UID, p.AssetID, sq.Expr("?::numeric", p.Amount.String()), p.Note, p.OccuredAt, p.CreatedAt).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.Pool.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+type holding struct {
+	asset
+	Amount string `db:"amount"`
+}
+
+// ListHoldings returns all assets of user with amount held.
+func (s *AssetStorage) ListHoldings(ctx context.Context, uid int64) ([]domain.Holding, error) {
+	sql, args, err := s.Builder.
+		Select(assetColumns, "coalesce(sum(o.amount), 0)::text amount").
+		From("assets a").
+		LeftJoin("asset_operations o ON o.asset_id = a.id").
+		Where(sq.Eq{"a.user_id": uid}).
+		GroupBy("a.id").
+		OrderBy("a.ticker").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[holding])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	holdings := make([]domain.Holding, len(res))
+
+	for i, h := range res {
+		amount, err := money.ParseDecimal(h.Amount, money.MaxScale)
+		if err != nil {
+			return nil, fmt.Errorf("amount of %s: %w", h.Ticker, err)
+		}
+
+		holdings[i] = domain.Holding{Asset: domain.Asset(h.asset), Amount: amount}
+	}
+
+	return holdings, nil
+}
+
+type assetMovement struct {
+	Ticker    string    `db:"ticker"`
+	Amount    string    `db:"amount"`
+	OccuredAt time.Time `db:"occured_at"`
+}
+
+// ListMovements returns all asset operations of user.
+func (s *AssetStorage) ListMovements(ctx context.Context, uid int64) ([]domain.AssetMovement, error) {
+	sql, args, err := s.Builder.
+		Select("a.ticker, o.amount::text amount, o.occured_at").
+		From("asset_operations o").
+		Join("assets a ON a.id = o.asset_id").
+		Where(sq.Eq{"o.user_id": uid}).
+		OrderBy("o.occured_at").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[assetMovement])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	movements := make([]domain.AssetMovement, len(res))
+
+	for i, m := range res {
+		amount, err := money.ParseDecimal(m.Amount, money.MaxScale)
+		if err != nil {
+			return nil, fmt.Errorf("amount of %s: %w", m.Ticker, err)
+		}
+
+		movements[i] = domain.AssetMovement{Ticker: m.Ticker, Amount: amount, OccuredAt: m.OccuredAt}
+	}
+
+	return movements, nil
+}
@@ -0,0 +1,663 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+	"github.com/ysomad/financer/internal/postgres/pgclient"
+)
+
+type BackupStorage struct {
+	*pgclient.Client
+}
+
+type backupUser struct {
+	Currency        string `db:"currency"`
+	Language        string `db:"language"`
+	PayDay          int    `db:"pay_day"`
+	AllowanceFooter bool   `db:"allowance_footer"`
+}
+
+type backupCategory struct {
+	ID        string         `db:"id"`
+	Name      string         `db:"name"`
+	Type      domain.CatType `db:"type"`
+	CreatedAt time.Time      `db:"created_at"`
+	DeletedAt *time.Time     `db:"deleted_at"`
+}
+
+type backupKeyword struct {
+	Operation string `db:"operation"`
+	CatID     string `db:"category_id"`
+}
+
+type backupAccount struct {
+	ID             string      `db:"id"`
+	Name           string      `db:"name"`
+	Currency       string      `db:"currency"`
+	OpeningBalance money.Money `db:"opening_balance"`
+	IsDefault      bool        `db:"is_default"`
+	CreatedAt      time.Time   `db:"created_at"`
+	DeletedAt      *time.Time  `db:"deleted_at"`
+}
+
+type backupOperation struct {
+	ID            string               `db:"id"`
+	Type          domain.OperationType `db:"type"`
+	AccountID     string               `db:"account_id"`
+	CatID         string               `db:"category_id"`
+	Name          string               `db:"name"`
+	Currency      string               `db:"currency"`
+	Money         money.Money          `db:"money"`
+	ToAccountID   string               `db:"to_account_id"`
+	ToCurrency    string               `db:"to_currency"`
+	ToMoney       money.Money          `db:"to_money"`
+	Rate          *float64             `db:"rate"`
+	ReferenceRate *float64             `db:"reference_rate"`
+	ExternalID    string               `db:"external_id"`
+	OccuredAt     time.Time            `db:"occured_at"`
+	CreatedAt     time.Time            `db:"created_at"`
+	DuplicateKept bool                 `db:"duplicate_kept"`
+}
+
+// Dump returns backup of all user data, deleted operations are not included.
+func (s *BackupStorage) Dump(ctx context.Context, uid int64, now time.Time) (domain.Backup, error) {
+	b := domain.Backup{
+		Format:    domain.BackupFormat,
+		Version:   domain.BackupVersion,
+		CreatedAt: now,
+	}
+
+	usr, err := backupQuery[backupUser](ctx, s, s.Builder.
+		Select("currency, language, pay_day, allowance_footer").
+		From("users").
+		Where(sq.Eq{"id": uid}))
+	if err != nil {
+		return domain.Backup{}, fmt.Errorf("user: %w", err)
+	}
+
+	if len(usr) != 1 {
+		return domain.Backup{}, ErrNotFound
+	}
+
+	b.User = domain.BackupUser(usr[0])
+
+	cats, err := backupQuery[backupCategory](ctx, s, s.Builder.
+		Select("id::text id, name, type, created_at, deleted_at").
+		From("categories").
+		Where(sq.Eq{"author": uid}).
+		OrderBy("created_at"))
+	if err != nil {
+		return domain.Backup{}, fmt.Errorf("categories: %w", err)
+	}
+
+	b.Categories = make([]domain.BackupCategory, len(cats))
+	for i, c := range cats {
+		b.Categories[i] = domain.BackupCategory(c)
+	}
+
+	rows, err := s.query(ctx, s.Builder.
+		Select("category_id::text").
+		From("user_categories").
+		Where(sq.Eq{"user_id": uid}))
+	if err != nil {
+		return domain.Backup{}, fmt.Errorf("user categories: %w", err)
+	}
+
+	b.UserCategories, err = pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return domain.Backup{}, fmt.Errorf("user categories: scan: %w", err)
+	}
+
+	keywords, err := backupQuery[backupKeyword](ctx, s, s.Builder.
+		Select("operation, category_id::text category_id").
+		From("user_keywords").
+		Where(sq.Eq{"user_id": uid}))
+	if err != nil {
+		return domain.Backup{}, fmt.Errorf("keywords: %w", err)
+	}
+
+	b.Keywords = make([]domain.BackupKeyword, len(keywords))
+	for i, kw := range keywords {
+		b.Keywords[i] = domain.BackupKeyword(kw)
+	}
+
+	accounts, err := backupQuery[backupAccount](ctx, s, s.Builder.
+		Select("id::text id, name, currency, opening_balance, is_default, created_at, deleted_at").
+		From("accounts").
+		Where(sq.Eq{"user_id": uid}).
+		OrderBy("created_at"))
+	if err != nil {
+		return domain.Backup{}, fmt.Errorf("accounts: %w", err)
+	}
+
+	b.Accounts = make([]domain.BackupAccount, len(accounts))
+	for i, acc := range accounts {
+		b.Accounts[i] = domain.BackupAccount(acc)
+	}
+
+	ops, err := backupQuery[backupOperation](ctx, s, s.Builder.
+		Select("id::text id, type, account_id::text account_id, coalesce(category_id::text, '') category_id",
+			"name, currency, money, coalesce(to_account_id::text, '') to_account_id",
+			"coalesce(to_currency, '') to_currency, coalesce(to_money, 0) to_money, rate, reference_rate",
+			"coalesce(external_id, '') external_id, occured_at, created_at, duplicate_kept").
+		From("operations").
+		Where(sq.Eq{"user_id": uid, "deleted_at": nil}).
+		OrderBy("occured_at, created_at"))
+	if err != nil {
+		return domain.Backup{}, fmt.Errorf("operations: %w", err)
+	}
+
+	b.Operations = make([]domain.BackupOperation, len(ops))
+	for i, op := range ops {
+		b.Operations[i] = domain.BackupOperation(op)
+	}
+
+	return b, nil
+}
+
+func (s *BackupStorage) query(ctx context.Context, b sq.SelectBuilder) (pgx.Rows, error) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return rows, nil
+}
+
+func backupQuery[T any](ctx context.Context, s *BackupStorage, b sq.SelectBuilder) ([]T, error) {
+	rows, err := s.query(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[T])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	return res, nil
+}
+
+// RestoreResult is number of restored records, records which already exist are not counted.
+type RestoreResult struct {
+	Categories int64
+	Accounts   int64
+	Operations int64
+	Keywords   int64
+}
+
+// Restore saves backup to user in one transaction and replaces user settings with settings of backup.
+// Records get ids by domain.BackupID, so backup may be restored to the same or to another user
+// and records which were already restored are skipped. Common categories which are not found are
+// replaced by OTHER category in operations and skipped in keywords. Account with the same name as
+// active account of user is merged with it if they are in the same currency and renamed otherwise.
+// Unused default account, e.g. the one created for every new user, is replaced by default account of backup.
+func (s *BackupStorage) Restore(ctx context.Context, uid int64, b domain.Backup, now time.Time) (RestoreResult, error) {
+	var res RestoreResult
+
+	err := pgx.BeginTxFunc(ctx, s.Pool, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		sql, args, err := s.Builder.
+			Update("users").
+			SetMap(map[string]any{
+				"currency":         b.User.Currency,
+				"language":         b.User.Language,
+				"pay_day":          b.User.PayDay,
+				"allowance_footer": b.User.AllowanceFooter,
+				"updated_at":       now,
+			}).
+			Where(sq.Eq{"id": uid}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return fmt.Errorf("user not updated: %w", err)
+		}
+
+		cats, err := s.restoreCategories(ctx, tx, uid, b, &res)
+		if err != nil {
+			return err
+		}
+
+		accounts, err := s.restoreAccounts(ctx, tx, uid, b.Accounts, now, &res)
+		if err != nil {
+			return err
+		}
+
+		if err := s.restoreOperations(ctx, tx, uid, b.Operations, cats, accounts, &res); err != nil {
+			return err
+		}
+
+		keywords := s.Builder.
+			Insert("user_keywords").
+			Columns("operation, user_id, category_id").
+			Suffix("ON CONFLICT DO NOTHING")
+
+		n := 0
+
+		for _, kw := range b.Keywords {
+			if catID, ok := cats[kw.CatID]; ok {
+				keywords = keywords.Values(kw.Operation, uid, catID)
+				n++
+			}
+		}
+
+		if n == 0 {
+			return nil
+		}
+
+		res.Keywords, err = execInsert(ctx, tx, keywords)
+		if err != nil {
+			return fmt.Errorf("keywords not saved: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return RestoreResult{}, fmt.Errorf("tx: %w", err)
+	}
+
+	return res, nil
+}
+
+// restoreCategories saves own categories of backup and attaches categories to user,
+// it returns restored ids of own categories and ids of existing common categories.
+func (s *BackupStorage) restoreCategories(ctx context.Context, tx pgx.Tx, uid int64, b domain.Backup,
+	res *RestoreResult,
+) (map[string]string, error) {
+	own := make([]string, len(b.Categories))
+	for i, c := range b.Categories {
+		own[i] = c.ID
+	}
+
+	cats, err := s.restoreIDs(ctx, tx, "categories", "author", uid, own)
+	if err != nil {
+		return nil, fmt.Errorf("categories: %w", err)
+	}
+
+	common := make([]string, 0, len(b.UserCategories)+len(b.Keywords)+len(b.Operations))
+	common = append(common, b.UserCategories...)
+
+	for _, kw := range b.Keywords {
+		common = append(common, kw.CatID)
+	}
+
+	for _, op := range b.Operations {
+		if op.CatID != "" {
+			common = append(common, op.CatID)
+		}
+	}
+
+	rows, err := s.query(ctx, s.Builder.
+		Select("id::text").
+		From("categories").
+		Where(sq.Eq{"author": nil}).
+		Where(sq.Expr("id = ANY(?)", common)))
+	if err != nil {
+		return nil, fmt.Errorf("common categories: %w", err)
+	}
+
+	found, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("common categories: scan: %w", err)
+	}
+
+	for _, id := range found {
+		cats[id] = id
+	}
+
+	for _, c := range b.Categories {
+		sql, args, err := s.Builder.
+			Insert("categories").
+			Columns("id, name, type, author, created_at, deleted_at").
+			Values(cats[c.ID], c.Name, c.Type, uid, c.CreatedAt, c.DeletedAt).
+			Suffix("ON CONFLICT (id) DO NOTHING").
+			ToSql()
+		if err != nil {
+			return nil, err
+		}
+
+		tag, err := tx.Exec(ctx, sql, args...)
+		if err != nil {
+			return nil, fmt.Errorf("category not saved: %w", err)
+		}
+
+		res.Categories += tag.RowsAffected()
+	}
+
+	for _, id := range b.UserCategories {
+		catID, ok := cats[id]
+		if !ok {
+			continue
+		}
+
+		sql, args, err := s.Builder.
+			Insert("user_categories").
+			Columns("user_id, category_id").
+			Values(uid, catID).
+			Suffix("ON CONFLICT DO NOTHING").
+			ToSql()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return nil, fmt.Errorf("category not attached to user: %w", err)
+		}
+	}
+
+	return cats, nil
+}
+
+// restoreAccounts saves accounts of backup and returns their restored ids, restored account
+// is default only if user has no default account.
+func (s *BackupStorage) restoreAccounts(ctx context.Context, tx pgx.Tx, uid int64, accounts []domain.BackupAccount,
+	now time.Time, res *RestoreResult,
+) (map[string]string, error) {
+	ids := make([]string, len(accounts))
+	for i, acc := range accounts {
+		ids[i] = acc.ID
+	}
+
+	restored, err := s.restoreIDs(ctx, tx, "accounts", "user_id", uid, ids)
+	if err != nil {
+		return nil, fmt.Errorf("accounts: %w", err)
+	}
+
+	sql, args, err := s.Builder.
+		Select(accountColumns, accountUsed).
+		From("accounts a").
+		Where(sq.Eq{"a.user_id": uid, "a.deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("active accounts: query: %w", err)
+	}
+
+	active, err := pgx.CollectRows(rows, pgx.RowToStructByName[usedAccount])
+	if err != nil {
+		return nil, fmt.Errorf("active accounts: scan: %w", err)
+	}
+
+	byName := make(map[string]usedAccount, len(active))
+	for _, acc := range active {
+		byName[strings.ToLower(acc.Name)] = acc
+	}
+
+	if err := s.replaceDefaultAccount(ctx, tx, active, accounts, restored, byName, now); err != nil {
+		return nil, err
+	}
+
+	for _, acc := range accounts {
+		id, name := restored[acc.ID], acc.Name
+
+		if existing, ok := byName[strings.ToLower(name)]; ok && acc.DeletedAt == nil && existing.ID != id {
+			if existing.Currency == acc.Currency {
+				restored[acc.ID] = existing.ID
+
+				if err := s.mergeAccount(ctx, tx, uid, existing, acc); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+
+			name = freeAccountName(byName, name, acc.Currency)
+		}
+
+		if acc.DeletedAt == nil {
+			byName[strings.ToLower(name)] = usedAccount{account: account{ID: id, Name: name, Currency: acc.Currency}}
+		}
+
+		sql, args, err := s.Builder.
+			Insert("accounts").
+			Columns("id, user_id, name, currency, opening_balance, is_default, created_at, deleted_at").
+			Values(id, uid, name, acc.Currency, acc.OpeningBalance,
+				sq.Expr("? AND NOT EXISTS (SELECT 1 FROM accounts WHERE user_id = ? AND is_default AND deleted_at IS NULL)",
+					acc.IsDefault && acc.DeletedAt == nil, uid),
+				acc.CreatedAt, acc.DeletedAt).
+			Suffix("ON CONFLICT (id) DO NOTHING").
+			ToSql()
+		if err != nil {
+			return nil, err
+		}
+
+		tag, err := tx.Exec(ctx, sql, args...)
+		if err != nil {
+			return nil, fmt.Errorf("account not saved: %w", err)
+		}
+
+		res.Accounts += tag.RowsAffected()
+	}
+
+	return restored, nil
+}
+
+// replaceDefaultAccount deletes unused default account of user if backup has another default account,
+// so default account of backup becomes default one and may keep its name.
+func (s *BackupStorage) replaceDefaultAccount(ctx context.Context, tx pgx.Tx, active []usedAccount,
+	accounts []domain.BackupAccount, restored map[string]string, byName map[string]usedAccount, now time.Time,
+) error {
+	i := slices.IndexFunc(accounts, func(acc domain.BackupAccount) bool { return acc.IsDefault && acc.DeletedAt == nil })
+	if i < 0 {
+		return nil
+	}
+
+	backupDefault := accounts[i]
+
+	for _, acc := range active {
+		if !acc.IsDefault || acc.Used || acc.OpeningBalance != 0 || acc.ID == restored[backupDefault.ID] {
+			continue
+		}
+
+		// the same account is merged with default account of backup
+		if strings.EqualFold(acc.Name, backupDefault.Name) && acc.Currency == backupDefault.Currency {
+			continue
+		}
+
+		sql, args, err := s.Builder.
+			Update("accounts").
+			Set("is_default", false).
+			Set("deleted_at", now).
+			Where(sq.Eq{"id": acc.ID}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return fmt.Errorf("default account not replaced: %w", err)
+		}
+
+		delete(byName, strings.ToLower(acc.Name))
+	}
+
+	return nil
+}
+
+// mergeAccount carries opening balance of backup account to unused account with the same name and makes
+// it default if backup account is default and user has no default account.
+func (s *BackupStorage) mergeAccount(ctx context.Context, tx pgx.Tx, uid int64, existing usedAccount,
+	acc domain.BackupAccount,
+) error {
+	clauses := make(map[string]any, 2)
+
+	if !existing.Used && existing.OpeningBalance == 0 && acc.OpeningBalance != 0 {
+		clauses["opening_balance"] = acc.OpeningBalance
+	}
+
+	if acc.IsDefault && !existing.IsDefault {
+		clauses["is_default"] = sq.Expr(
+			"NOT EXISTS (SELECT 1 FROM accounts WHERE user_id = ? AND is_default AND deleted_at IS NULL)", uid)
+	}
+
+	if len(clauses) == 0 {
+		return nil
+	}
+
+	sql, args, err := s.Builder.
+		Update("accounts").
+		SetMap(clauses).
+		Where(sq.Eq{"id": existing.ID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("account not merged: %w", err)
+	}
+
+	return nil
+}
+
+// freeAccountName returns name with currency suffix which is not taken by active accounts,
+// e.g. "Cash_USD" or "Cash_USD_2", name is truncated to fit suffix in max name length.
+func freeAccountName(taken map[string]usedAccount, name, currency string) string {
+	for i := 1; ; i++ {
+		suffix := "_" + currency
+		if i > 1 {
+			suffix += "_" + strconv.Itoa(i)
+		}
+
+		base := []rune(name)
+		if n := domain.MaxNameLen - utf8.RuneCountInString(suffix); len(base) > n {
+			base = base[:n]
+		}
+
+		candidate := string(base) + suffix
+		if _, ok := taken[strings.ToLower(candidate)]; !ok {
+			return candidate
+		}
+	}
+}
+
+// restoreOperations saves operations of backup in batches, operations with already imported
+// external id are skipped.
+func (s *BackupStorage) restoreOperations(ctx context.Context, tx pgx.Tx, uid int64, ops []domain.BackupOperation,
+	cats, accounts map[string]string, res *RestoreResult,
+) error {
+	ids := make([]string, len(ops))
+	for i, op := range ops {
+		ids[i] = op.ID
+	}
+
+	restored, err := s.restoreIDs(ctx, tx, "operations", "user_id", uid, ids)
+	if err != nil {
+		return fmt.Errorf("operations: %w", err)
+	}
+
+	for i := 0; i < len(ops); i += importBatchSize {
+		b := s.Builder.
+			Insert("operations").
+			Columns("id, user_id, type, account_id, category_id, name, currency, money",
+				"to_account_id, to_currency, to_money, rate, reference_rate, external_id, occured_at, created_at",
+				"duplicate_kept").
+			Suffix("ON CONFLICT DO NOTHING")
+
+		for _, op := range ops[i:min(i+importBatchSize, len(ops))] {
+			var catID, toAccountID, toCurrency, toMoney, externalID any
+
+			if op.CatID != "" {
+				catID = domain.OtherCategoryID
+				if id, ok := cats[op.CatID]; ok {
+					catID = id
+				}
+			}
+
+			if op.ToAccountID != "" {
+				toAccountID, toCurrency, toMoney = accounts[op.ToAccountID], op.ToCurrency, op.ToMoney
+			}
+
+			if op.ExternalID != "" {
+				externalID = op.ExternalID
+			}
+
+			b = b.Values(restored[op.ID], uid, op.Type, accounts[op.AccountID], catID, op.Name, op.Currency, op.Money,
+				toAccountID, toCurrency, toMoney, op.Rate, op.ReferenceRate, externalID, op.OccuredAt, op.CreatedAt,
+				op.DuplicateKept)
+		}
+
+		n, err := execInsert(ctx, tx, b)
+		if err != nil {
+			return fmt.Errorf("operations not saved: %w", err)
+		}
+
+		res.Operations += n
+	}
+
+	return nil
+}
+
+// restoreIDs returns restored ids by ids of backup records in table, ids which are taken by records
+// of other owners are replaced, see domain.BackupID.
+func (s *BackupStorage) restoreIDs(ctx context.Context, tx pgx.Tx, table, owner string, uid int64,
+	ids []string,
+) (map[string]string, error) {
+	sql, args, err := s.Builder.
+		Select("id::text").
+		From(table).
+		Where(sq.Expr("id = ANY(?)", ids)).
+		Where(sq.Expr(owner+" IS DISTINCT FROM ?", uid)).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	found, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	taken := make(map[string]bool, len(found))
+	for _, id := range found {
+		taken[id] = true
+	}
+
+	res := make(map[string]string, len(ids))
+	for _, id := range ids {
+		res[id] = domain.BackupID(id, uid, taken[id])
+	}
+
+	return res, nil
+}
+
+func execInsert(ctx context.Context, tx pgx.Tx, b sq.InsertBuilder) (int64, error) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
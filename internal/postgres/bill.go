@@ -0,0 +1,302 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+	"github.com/ysomad/financer/internal/postgres/pgclient"
+)
+
+type BillStorage struct {
+	*pgclient.Client
+}
+
+type bill struct {
+	ID         string      `db:"id"`
+	UID        int64       `db:"user_id"`
+	Name       string      `db:"name"`
+	Currency   string      `db:"currency"`
+	Money      money.Money `db:"money"`
+	DueDay     int         `db:"due_day"`
+	RemindDays int         `db:"remind_days"`
+	NextDueAt  time.Time   `db:"next_due_at"`
+}
+
+const billColumns = "b.id id, b.user_id user_id, b.name name, b.currency currency, b.money money, " +
+	"b.due_day due_day, b.remind_days remind_days, b.next_due_at next_due_at"
+
+type SaveBillParams struct {
+	ID         string
+	UID        int64
+	Name       string
+	Currency   string
+	Money      money.Money
+	DueDay     int
+	RemindDays int
+	NextDueAt  time.Time
+	CreatedAt  time.Time
+}
+
+func (s *BillStorage) Save(ctx context.Context, p SaveBillParams) error {
+	sql, args, err := s.Builder.
+		Insert("bills").
+		Columns("id, user_id, name, currency, money",
+			"due_day, remind_days, next_due_at, created_at").
+		Values(p.ID, p.UID, p.Name, p.Currency, p.Money,
+			p.DueDay, p.RemindDays, p.NextDueAt, p.CreatedAt).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.Pool.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+func (s *BillStorage) ListByUserID(ctx context.Context, uid int64) ([]domain.Bill, error) {
+	sql, args, err := s.Builder.
+		Select(billColumns).
+		From("bills b").
+		Where(sq.And{
+			sq.Eq{"b.user_id": uid},
+			sq.Eq{"b.deleted_at": nil},
+		}).
+		OrderBy("b.next_due_at").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[bill])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	bills := make([]domain.Bill, len(res))
+	for i, b := range res {
+		bills[i] = domain.Bill(b)
+	}
+
+	return bills, nil
+}
+
+func (s *BillStorage) FindByID(ctx context.Context, uid int64, billID string) (domain.Bill, error) {
+	sql, args, err := s.Builder.
+		Select(billColumns).
+		From("bills b").
+		Where(sq.And{
+			sq.Eq{"b.id": billID},
+			sq.Eq{"b.user_id": uid},
+			sq.Eq{"b.deleted_at": nil},
+		}).
+		ToSql()
+	if err != nil {
+		return domain.Bill{}, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return domain.Bill{}, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[bill])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Bill{}, ErrNotFound
+		}
+
+		return domain.Bill{}, fmt.Errorf("scan: %w", err)
+	}
+
+	return domain.Bill(res), nil
+}
+
+type BillNotification struct {
+	Bill     domain.Bill
+	Language string
+}
+
+type billNotification struct {
+	bill
+	Language string `db:"language"`
+}
+
+// ListToRemind returns bills which user must be reminded about before due date.
+func (s *BillStorage) ListToRemind(ctx context.Context, today time.Time) ([]BillNotification, error) {
+	return s.listNotifications(ctx, sq.And{
+		sq.Eq{"b.reminded_at": nil},
+		sq.Expr("b.next_due_at - b.remind_days <= ?::date", today),
+	})
+}
+
+// ListToEscalate returns bills which are due and still not paid.
+func (s *BillStorage) ListToEscalate(ctx context.Context, today time.Time) ([]BillNotification, error) {
+	return s.listNotifications(ctx, sq.And{
+		sq.Eq{"b.escalated_at": nil},
+		sq.LtOrEq{"b.next_due_at": today},
+	})
+}
+
+func (s *BillStorage) listNotifications(ctx context.Context, pred sq.Sqlizer) ([]BillNotification, error) {
+	sql, args, err := s.Builder.
+		Select(billColumns, "u.language language").
+		From("bills b").
+		InnerJoin("users u ON u.id = b.user_id").
+		Where(sq.Eq{"b.deleted_at": nil}).
+		Where(pred).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[billNotification])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	notifications := make([]BillNotification, len(res))
+	for i, n := range res {
+		notifications[i] = BillNotification{Bill: domain.Bill(n.bill), Language: n.Language}
+	}
+
+	return notifications, nil
+}
+
+func (s *BillStorage) MarkReminded(ctx context.Context, billID string, t time.Time) error {
+	return s.update(ctx, billID, map[string]any{"reminded_at": t})
+}
+
+func (s *BillStorage) MarkEscalated(ctx context.Context, billID string, t time.Time) error {
+	return s.update(ctx, billID, map[string]any{"escalated_at": t})
+}
+
+type SaveBillPaymentParams struct {
+	BillID    string
+	PaidDueAt time.Time
+	NextDueAt time.Time
+	Operation SaveOperationParams
+}
+
+// SavePayment moves bill from paid due date to the next one, resets its notifications and saves payment
+// operation in one transaction. Nothing is saved and false is returned if due date of bill is not paidDueAt
+// anymore, so payment is recorded once.
+func (s *BillStorage) SavePayment(ctx context.Context, p SaveBillPaymentParams) (bool, error) {
+	billSQL, billArgs, err := s.Builder.
+		Update("bills").
+		SetMap(map[string]any{
+			"next_due_at":  p.NextDueAt,
+			"reminded_at":  nil,
+			"escalated_at": nil,
+			"updated_at":   p.Operation.CreatedAt,
+		}).
+		Where(sq.Eq{"id": p.BillID, "next_due_at": p.PaidDueAt, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	op := p.Operation
+
+	opSQL, opArgs, err := s.Builder.
+		Insert("operations").
+		Columns("id, user_id, account_id, category_id, name",
+			"currency, money, occured_at, created_at").
+		Values(op.ID, op.UID, op.AccountID, op.CatID, op.Operation,
+			op.Currency, op.Money, op.OccuredAt, op.CreatedAt).
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	keywordSQL, keywordArgs, err := s.Builder.
+		Insert("user_keywords").
+		Columns("user_id, category_id, operation").
+		Values(op.UID, op.CatID, op.Operation).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+	if err != nil {
+		return false, err
+	}
+
+	var paid bool
+
+	err = pgx.BeginTxFunc(ctx, s.Pool, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, billSQL, billArgs...)
+		if err != nil {
+			return fmt.Errorf("bill not updated: %w", err)
+		}
+
+		if paid = tag.RowsAffected() > 0; !paid {
+			return nil
+		}
+
+		if _, err := tx.Exec(ctx, opSQL, opArgs...); err != nil {
+			return fmt.Errorf("operation not saved: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, keywordSQL, keywordArgs...); err != nil {
+			return fmt.Errorf("keyword not saved: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("tx: %w", err)
+	}
+
+	return paid, nil
+}
+
+func (s *BillStorage) Delete(ctx context.Context, uid int64, billID string, t time.Time) error {
+	sql, args, err := s.Builder.
+		Update("bills").
+		Set("deleted_at", t).
+		Where(sq.Eq{"id": billID, "user_id": uid}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.Pool.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+func (s *BillStorage) update(ctx context.Context, billID string, clauses map[string]any) error {
+	sql, args, err := s.Builder.
+		Update("bills").
+		SetMap(clauses).
+		Where(sq.Eq{"id": billID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.Pool.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+	"github.com/ysomad/financer/internal/postgres/pgclient"
+)
+
+type DebtStorage struct {
+	*pgclient.Client
+}
+
+type SaveDebtParams struct {
+	ID        string
+	UID       int64
+	Person    string
+	Type      domain.DebtType
+	Currency  string
+	Money     money.Money
+	OccuredAt time.Time
+	CreatedAt time.Time
+}
+
+func (s *DebtStorage) Save(ctx context.Context, p SaveDebtParams) error {
+	sql, args, err := s.Builder.
+		Insert("debts").
+		Columns("id, user_id, person, type, currency, money, occured_at, created_at").
+		Values(p.ID, p.UID, p.Person, p.Type, p.Currency, p.Money, p.OccuredAt, p.CreatedAt).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.Pool.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+type debt struct {
+	ID        string          `db:"id"`
+	UID       int64           `db:"user_id"`
+	Person    string          `db:"person"`
+	Type      domain.DebtType `db:"type"`
+	Currency  string          `db:"currency"`
+	Money     money.Money     `db:"money"`
+	OccuredAt time.Time       `db:"occured_at"`
+}
+
+// ListByUserID returns not deleted debts of user ordered by occured_at.
+func (s *DebtStorage) ListByUserID(ctx context.Context, uid int64) ([]domain.Debt, error) {
+	sql, args, err := s.Builder.
+		Select("id, user_id, person, type, currency, money, occured_at").
+		From("debts").
+		Where(sq.Eq{"user_id": uid, "deleted_at": nil}).
+		OrderBy("occured_at").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[debt])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	debts := make([]domain.Debt, len(res))
+	for i, d := range res {
+		debts[i] = domain.Debt(d)
+	}
+
+	return debts, nil
+}
+
+type debtBalance struct {
+	Person   string      `db:"person"`
+	Currency string      `db:"currency"`
+	Money    money.Money `db:"money"`
+}
+
+// ListBalances returns not settled debts of user grouped by case insensitive person name and currency.
+func (s *DebtStorage) ListBalances(ctx context.Context, uid int64) ([]domain.DebtBalance, error) {
+	return s.listBalances(ctx, sq.Eq{"user_id": uid})
+}
+
+// ListPersonBalances returns not settled debts between user and person grouped by currency.
+func (s *DebtStorage) ListPersonBalances(ctx context.Context, uid int64, person string) ([]domain.DebtBalance, error) {
+	return s.listBalances(ctx, sq.And{
+		sq.Eq{"user_id": uid},
+		sq.Expr("lower(person) = lower(?)", person),
+	})
+}
+
+func (s *DebtStorage) listBalances(ctx context.Context, pred sq.Sqlizer) ([]domain.DebtBalance, error) {
+	sql, args, err := s.Builder.
+		Select("(array_agg(person ORDER BY created_at))[1] person, currency, sum(money)::bigint money").
+		From("debts").
+		Where(pred).
+		Where(sq.Eq{"deleted_at": nil}).
+		GroupBy("lower(person), currency").
+		Having("sum(money) <> 0").
+		OrderBy("person, currency").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[debtBalance])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	balances := make([]domain.DebtBalance, len(res))
+	for i, b := range res {
+		balances[i] = domain.DebtBalance(b)
+	}
+
+	return balances, nil
+}
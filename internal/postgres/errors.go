@@ -2,4 +2,7 @@ package postgres
 
 import "errors"
 
-var ErrNotFound = errors.New("postgres: record not found")
+var (
+	ErrNotFound      = errors.New("postgres: record not found")
+	ErrAlreadyExists = errors.New("postgres: record already exists")
+)
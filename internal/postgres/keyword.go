@@ -46,6 +46,44 @@ func (s *KeywordStorage) FindCategory(ctx context.Context, uid int64, opName str
 	return cat, nil
 }
 
+type keyword struct {
+	Operation string         `db:"operation"`
+	CatID     string         `db:"category_id"`
+	CatName   string         `db:"category_name"`
+	CatType   domain.CatType `db:"category_type"`
+}
+
+// ListByUserID returns all keywords of user with their not deleted categories.
+func (s *KeywordStorage) ListByUserID(ctx context.Context, uid int64) ([]domain.Keyword, error) {
+	sql, args, err := s.Builder.
+		Select("uk.operation operation, c.id::text category_id, c.name category_name, c.type category_type").
+		From("user_keywords uk").
+		InnerJoin("categories c ON uk.category_id = c.id").
+		Where(sq.Eq{"uk.user_id": uid}).
+		Where(sq.Eq{"c.deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[keyword])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	keywords := make([]domain.Keyword, len(res))
+	for i, kw := range res {
+		keywords[i] = domain.Keyword(kw)
+	}
+
+	return keywords, nil
+}
+
 func (s *KeywordStorage) DeleteAll(ctx context.Context, uid int64) error {
 	sql, args, err := s.Builder.
 		Delete("user_keywords").
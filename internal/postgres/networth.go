@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+	"github.com/ysomad/financer/internal/postgres/pgclient"
+)
+
+type NetWorthStorage struct {
+	*pgclient.Client
+}
+
+type netWorthSnapshot struct {
+	Month       time.Time   `db:"month"`
+	Money       money.Money `db:"money"`
+	Approximate bool        `db:"approximate"`
+}
+
+// snapshotChanged is condition of stale snapshot, snapshot is stale if operation, debt, asset operation,
+// exchange rate or asset price which occured before the end of snapshot month or any account was changed
+// after snapshot was computed.
+const snapshotChanged = `EXISTS (SELECT 1 FROM operations o WHERE o.user_id = s.user_id
+		AND o.occured_at < s.month + interval '1 month'
+		AND greatest(o.created_at, o.updated_at, o.deleted_at) > s.computed_at)
+	OR EXISTS (SELECT 1 FROM debts d WHERE d.user_id = s.user_id
+		AND d.occured_at < s.month + interval '1 month'
+		AND greatest(d.created_at, d.deleted_at) > s.computed_at)
+	OR EXISTS (SELECT 1 FROM asset_operations ao WHERE ao.user_id = s.user_id
+		AND ao.occured_at < s.month + interval '1 month'
+		AND ao.created_at > s.computed_at)
+	OR EXISTS (SELECT 1 FROM accounts a WHERE a.user_id = s.user_id
+		AND greatest(a.created_at, a.deleted_at) > s.computed_at)
+	OR EXISTS (SELECT 1 FROM exchange_rates r WHERE r.date < s.month + interval '1 month'
+		AND r.created_at > s.computed_at)
+	OR EXISTS (SELECT 1 FROM asset_prices p WHERE p.user_id = s.user_id
+		AND p.date < s.month + interval '1 month'
+		AND p.created_at > s.computed_at)`
+
+// ListFresh returns not stale snapshots of user in currency made for months in [from, to).
+func (s *NetWorthStorage) ListFresh(ctx context.Context, uid int64, currency string, from, to time.Time) ([]domain.NetWorthSnapshot, error) {
+	sql, args, err := s.Builder.
+		Select("s.month, s.money, s.approximate").
+		From("networth_snapshots s").
+		Where(sq.Eq{"s.user_id": uid, "s.currency": currency}).
+		Where(sq.GtOrEq{"s.month": from}).
+		Where(sq.Lt{"s.month": to}).
+		Where("NOT (" + snapshotChanged + ")").
+		OrderBy("s.month").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[netWorthSnapshot])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	snapshots := make([]domain.NetWorthSnapshot, len(res))
+	for i, s := range res {
+		snapshots[i] = domain.NetWorthSnapshot(s)
+	}
+
+	return snapshots, nil
+}
+
+// Save saves snapshots replacing existing ones for the same months.
+func (s *NetWorthStorage) Save(ctx context.Context, uid int64, currency string, snapshots []domain.NetWorthSnapshot, computedAt time.Time) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	b := s.Builder.
+		Insert("networth_snapshots").
+		Columns("user_id, month, currency, money, approximate, computed_at").
+		Suffix("ON CONFLICT (user_id, month) DO UPDATE SET currency = excluded.currency, money = excluded.money, " +
+			"approximate = excluded.approximate, computed_at = excluded.computed_at")
+
+	for _, snap := range snapshots {
+		b = b.Values(uid, snap.Month, currency, snap.Money, snap.Approximate, computedAt)
+	}
+
+	sql, args, err := b.ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.Pool.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
@@ -7,10 +7,13 @@ import (
 	"log/slog"
 	"time"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 
+	"github.com/ysomad/financer/internal/domain"
 	"github.com/ysomad/financer/internal/money"
 	"github.com/ysomad/financer/internal/postgres/pgclient"
 )
@@ -22,20 +25,25 @@ type OperationStorage struct {
 type SaveOperationParams struct {
 	ID        string
 	UID       int64
+	AccountID string
 	CatID     string
 	Operation string
 	Currency  string
 	Money     money.Money
 	OccuredAt time.Time
 	CreatedAt time.Time
+	// ExternalID is id of operation in bank statement, used by Import only.
+	ExternalID string
+	// DuplicateKept is true if user kept operation which is likely duplicate, used by Import only.
+	DuplicateKept bool
 }
 
 func (s *OperationStorage) Save(ctx context.Context, p SaveOperationParams) error {
 	sql1, args1, err := s.Builder.
 		Insert("operations").
-		Columns("id, user_id, category_id, name",
+		Columns("id, user_id, account_id, category_id, name",
 			"currency, money, occured_at, created_at").
-		Values(p.ID, p.UID, p.CatID, p.Operation,
+		Values(p.ID, p.UID, p.AccountID, p.CatID, p.Operation,
 			p.Currency, p.Money, p.OccuredAt, p.CreatedAt).
 		ToSql()
 	if err != nil {
@@ -76,3 +84,460 @@ func (s *OperationStorage) Save(ctx context.Context, p SaveOperationParams) erro
 
 	return nil
 }
+
+// importBatchSize is number of operations inserted by one statement.
+const importBatchSize = 1000
+
+type ImportOperationsParams struct {
+	UID        int64
+	Categories []SaveCategoryParams // new categories of imported operations
+	Operations []SaveOperationParams
+}
+
+// Import saves new categories and operations in one transaction and returns number of saved operations,
+// operations with external id which is already imported are skipped. Keywords of imported operations are not saved.
+func (s *OperationStorage) Import(ctx context.Context, p ImportOperationsParams) (int64, error) {
+	var saved int64
+
+	err := pgx.BeginTxFunc(ctx, s.Pool, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		for _, c := range p.Categories {
+			sql1, args1, err := s.Builder.
+				Insert("categories").
+				Columns("id, name, type, author, created_at").
+				Values(c.ID, c.Name, c.Type, p.UID, c.CreatedAt).
+				ToSql()
+			if err != nil {
+				return err
+			}
+
+			if _, err := tx.Exec(ctx, sql1, args1...); err != nil {
+				return fmt.Errorf("category not saved: %w", err)
+			}
+
+			sql2, args2, err := s.Builder.
+				Insert("user_categories").
+				Columns("user_id, category_id").
+				Values(p.UID, c.ID).
+				ToSql()
+			if err != nil {
+				return err
+			}
+
+			if _, err := tx.Exec(ctx, sql2, args2...); err != nil {
+				return fmt.Errorf("category not attached to user: %w", err)
+			}
+		}
+
+		for i := 0; i < len(p.Operations); i += importBatchSize {
+			ops := p.Operations[i:min(i+importBatchSize, len(p.Operations))]
+
+			b := s.Builder.
+				Insert("operations").
+				Columns("id, user_id, account_id, category_id, name",
+					"currency, money, occured_at, created_at, external_id, duplicate_kept").
+				Suffix("ON CONFLICT (user_id, external_id) DO NOTHING")
+
+			for _, op := range ops {
+				var externalID any
+				if op.ExternalID != "" {
+					externalID = op.ExternalID
+				}
+
+				b = b.Values(op.ID, p.UID, op.AccountID, op.CatID, op.Operation,
+					op.Currency, op.Money, op.OccuredAt, op.CreatedAt, externalID, op.DuplicateKept)
+			}
+
+			sql, args, err := b.ToSql()
+			if err != nil {
+				return err
+			}
+
+			tag, err := tx.Exec(ctx, sql, args...)
+			if err != nil {
+				return fmt.Errorf("operations not saved: %w", err)
+			}
+
+			saved += tag.RowsAffected()
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("tx: %w", err)
+	}
+
+	return saved, nil
+}
+
+// ListImported returns external ids of operations of user which were imported with any of external ids.
+func (s *OperationStorage) ListImported(ctx context.Context, uid int64, externalIDs []string) ([]string, error) {
+	sql, args, err := s.Builder.
+		Select("external_id").
+		From("operations").
+		Where(sq.Eq{"user_id": uid}).
+		Where(sq.Expr("external_id = ANY(?)", externalIDs)).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	return ids, nil
+}
+
+type SaveTransferParams struct {
+	ID          string
+	UID         int64
+	AccountID   string
+	ToAccountID string
+	Name        string
+	Currency    string
+	Money       money.Money // negative
+	ToCurrency  string
+	ToMoney     money.Money // positive
+	OccuredAt   time.Time
+	CreatedAt   time.Time
+}
+
+// SaveTransfer saves transfer between accounts, transfer has no category and keyword.
+func (s *OperationStorage) SaveTransfer(ctx context.Context, p SaveTransferParams) error {
+	sql, args, err := s.Builder.
+		Insert("operations").
+		Columns("id, user_id, type, account_id, to_account_id, name",
+			"currency, money, to_currency, to_money, occured_at, created_at").
+		Values(p.ID, p.UID, domain.OperationTypeTransfer, p.AccountID, p.ToAccountID, p.Name,
+			p.Currency, p.Money, p.ToCurrency, p.ToMoney, p.OccuredAt, p.CreatedAt).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.Pool.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+type SaveExchangeParams struct {
+	SaveTransferParams
+	Rate          float64
+	ReferenceRate float64 // zero if unknown
+}
+
+// SaveExchange saves currency exchange between accounts in different currencies.
+func (s *OperationStorage) SaveExchange(ctx context.Context, p SaveExchangeParams) error {
+	var refRate any
+	if p.ReferenceRate > 0 {
+		refRate = p.ReferenceRate
+	}
+
+	sql, args, err := s.Builder.
+		Insert("operations").
+		Columns("id, user_id, type, account_id, to_account_id, name",
+			"currency, money, to_currency, to_money, rate, reference_rate, occured_at, created_at").
+		Values(p.ID, p.UID, domain.OperationTypeExchange, p.AccountID, p.ToAccountID, p.Name,
+			p.Currency, p.Money, p.ToCurrency, p.ToMoney, p.Rate, refRate, p.OccuredAt, p.CreatedAt).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.Pool.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+type operation struct {
+	ID        string               `db:"id"`
+	UID       int64                `db:"user_id"`
+	Type      domain.OperationType `db:"type"`
+	AccountID string               `db:"account_id"`
+	CatID     pgtype.Text          `db:"category_id"`
+	Name      string               `db:"name"`
+	Currency  string               `db:"currency"`
+	Money     money.Money          `db:"money"`
+	OccuredAt time.Time            `db:"occured_at"`
+	CreatedAt time.Time            `db:"created_at"`
+}
+
+func (o operation) toDomain() domain.Operation {
+	return domain.Operation{
+		ID:        o.ID,
+		UID:       o.UID,
+		Type:      o.Type,
+		AccountID: o.AccountID,
+		CatID:     o.CatID.String,
+		Name:      o.Name,
+		Currency:  o.Currency,
+		Money:     o.Money,
+		OccuredAt: o.OccuredAt,
+		CreatedAt: o.CreatedAt,
+	}
+}
+
+// ListByUserID returns not deleted user income and expenses occured in [from, to) ordered by occured_at.
+// Transfers between accounts are not returned.
+func (s *OperationStorage) ListByUserID(ctx context.Context, uid int64, from, to time.Time) ([]domain.Operation, error) {
+	sql, args, err := s.Builder.
+		Select("id, user_id, type, account_id::text, category_id::text, name, currency, money, occured_at, created_at").
+		From("operations").
+		Where(sq.And{
+			sq.Eq{"user_id": uid},
+			sq.Eq{"deleted_at": nil},
+			sq.Eq{"type": domain.OperationTypeRegular},
+			sq.GtOrEq{"occured_at": from},
+			sq.Lt{"occured_at": to},
+		}).
+		OrderBy("occured_at").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[operation])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	ops := make([]domain.Operation, len(res))
+	for i, op := range res {
+		ops[i] = op.toDomain()
+	}
+
+	return ops, nil
+}
+
+// ListByAccountID returns not deleted operations of any type which moved money of account
+// and were created since t, ordered by created_at.
+func (s *OperationStorage) ListByAccountID(ctx context.Context, uid int64, accountID string, since time.Time) ([]domain.Operation, error) {
+	sql, args, err := s.Builder.
+		Select("id, user_id, type, account_id::text, category_id::text, name").
+		Column(sq.Expr("CASE WHEN account_id = ? THEN currency ELSE to_currency END currency", accountID)).
+		Column(sq.Expr("CASE WHEN account_id = ? THEN money ELSE to_money END money", accountID)).
+		Columns("occured_at, created_at").
+		From("operations").
+		Where(sq.And{
+			sq.Eq{"user_id": uid},
+			sq.Eq{"deleted_at": nil},
+			sq.Or{sq.Eq{"account_id": accountID}, sq.Eq{"to_account_id": accountID}},
+			sq.GtOrEq{"created_at": since},
+		}).
+		OrderBy("created_at").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[operation])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	ops := make([]domain.Operation, len(res))
+	for i, op := range res {
+		ops[i] = op.toDomain()
+	}
+
+	return ops, nil
+}
+
+type duplicateOperation struct {
+	operation
+	DuplicateKept bool `db:"duplicate_kept"`
+}
+
+// ListForDuplicates returns not deleted user income and expenses occured in [from, to) ordered by occured_at
+// with flag of kept duplicates, zero from or to is not limited.
+func (s *OperationStorage) ListForDuplicates(ctx context.Context, uid int64, from, to time.Time) ([]domain.Operation, error) {
+	where := sq.And{
+		sq.Eq{"user_id": uid},
+		sq.Eq{"deleted_at": nil},
+		sq.Eq{"type": domain.OperationTypeRegular},
+	}
+
+	if !from.IsZero() {
+		where = append(where, sq.GtOrEq{"occured_at": from})
+	}
+
+	if !to.IsZero() {
+		where = append(where, sq.Lt{"occured_at": to})
+	}
+
+	sql, args, err := s.Builder.
+		Select("id, user_id, type, account_id::text, category_id::text, name, currency, money",
+			"occured_at, created_at, duplicate_kept").
+		From("operations").
+		Where(where).
+		OrderBy("occured_at").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[duplicateOperation])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	ops := make([]domain.Operation, len(res))
+	for i, op := range res {
+		ops[i] = op.toDomain()
+		ops[i].DuplicateKept = op.DuplicateKept
+	}
+
+	return ops, nil
+}
+
+// FindByID returns not deleted operation of user.
+func (s *OperationStorage) FindByID(ctx context.Context, uid int64, id string) (domain.Operation, error) {
+	sql, args, err := s.Builder.
+		Select("id, user_id, type, account_id::text, category_id::text, name, currency, money",
+			"occured_at, created_at, duplicate_kept").
+		From("operations").
+		Where(sq.Eq{"id": id, "user_id": uid, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return domain.Operation{}, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return domain.Operation{}, fmt.Errorf("query: %w", err)
+	}
+
+	op, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[duplicateOperation])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Operation{}, ErrNotFound
+		}
+
+		return domain.Operation{}, fmt.Errorf("scan: %w", err)
+	}
+
+	res := op.toDomain()
+	res.DuplicateKept = op.DuplicateKept
+
+	return res, nil
+}
+
+// Delete deletes not deleted operation of user softly, so net worth snapshots which include it are recalculated.
+func (s *OperationStorage) Delete(ctx context.Context, uid int64, id string, t time.Time) error {
+	return s.update(ctx, uid, id, map[string]any{"deleted_at": t})
+}
+
+// KeepDuplicate marks operation as not a duplicate, so it is not reported as duplicate again.
+func (s *OperationStorage) KeepDuplicate(ctx context.Context, uid int64, id string) error {
+	return s.update(ctx, uid, id, map[string]any{"duplicate_kept": true})
+}
+
+func (s *OperationStorage) update(ctx context.Context, uid int64, id string, clauses map[string]any) error {
+	sql, args, err := s.Builder.
+		Update("operations").
+		SetMap(clauses).
+		Where(sq.Eq{"id": id, "user_id": uid, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := s.Pool.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+type exportedOperation struct {
+	operation
+	Category   string      `db:"category"`
+	Account    string      `db:"account"`
+	ToAccount  string      `db:"to_account"`
+	ToCurrency string      `db:"to_currency"`
+	ToMoney    money.Money `db:"to_money"`
+}
+
+// Export calls fn for every not deleted operation of user of any type occured in [from, to) ordered by occured_at.
+// Rows are scanned one by one, so history of any size is not loaded to memory.
+func (s *OperationStorage) Export(ctx context.Context, uid int64, from, to time.Time, fn func(domain.ExportedOperation) error) error {
+	sql, args, err := s.Builder.
+		Select("o.id, o.user_id, o.type, o.account_id::text, o.category_id::text, o.name, o.currency, o.money",
+			"o.occured_at, o.created_at, coalesce(c.name, '') category, a.name account, coalesce(ta.name, '') to_account",
+			"coalesce(o.to_currency, '') to_currency, coalesce(o.to_money, 0) to_money").
+		From("operations o").
+		Join("accounts a ON a.id = o.account_id").
+		LeftJoin("accounts ta ON ta.id = o.to_account_id").
+		LeftJoin("categories c ON c.id = o.category_id").
+		Where(sq.And{
+			sq.Eq{"o.user_id": uid},
+			sq.Eq{"o.deleted_at": nil},
+			sq.GtOrEq{"o.occured_at": from},
+			sq.Lt{"o.occured_at": to},
+		}).
+		OrderBy("o.occured_at, o.created_at").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		op, err := pgx.RowToStructByName[exportedOperation](rows)
+		if err != nil {
+			return fmt.Errorf("scan: %w", err)
+		}
+
+		if err := fn(domain.ExportedOperation{
+			Operation:  op.toDomain(),
+			Category:   op.Category,
+			Account:    op.Account,
+			ToAccount:  op.ToAccount,
+			ToCurrency: op.ToCurrency,
+			ToMoney:    op.ToMoney,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows: %w", err)
+	}
+
+	return nil
+}
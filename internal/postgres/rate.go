@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/postgres/pgclient"
+)
+
+// RateSourceUser is source of asset prices set by user.
+const RateSourceUser = "manual"
+
+type ExchangeRateStorage struct {
+	*pgclient.Client
+}
+
+// Save saves exchange rates in one transaction, existing rates on the same date are replaced.
+// If rates have the same date, base and quote the last one is saved.
+func (s *ExchangeRateStorage) Save(ctx context.Context, rates []domain.ExchangeRate, createdAt time.Time) error {
+	type rateKey struct {
+		date        string
+		base, quote string
+	}
+
+	var (
+		unique = make([]domain.ExchangeRate, 0, len(rates))
+		index  = make(map[rateKey]int, len(rates))
+	)
+
+	for _, r := range rates {
+		key := rateKey{date: r.Date.Format(time.DateOnly), base: r.Base, quote: r.Quote}
+
+		if i, ok := index[key]; ok {
+			unique[i] = r
+			continue
+		}
+
+		index[key] = len(unique)
+		unique = append(unique, r)
+	}
+
+	if len(unique) == 0 {
+		return nil
+	}
+
+	err := pgx.BeginTxFunc(ctx, s.Pool, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		for i := 0; i < len(unique); i += importBatchSize {
+			b := s.Builder.
+				Insert("exchange_rates").
+				Columns("date, base, quote, rate, source, created_at").
+				Suffix("ON CONFLICT (date, base, quote) DO UPDATE SET rate = excluded.rate, source = excluded.source, created_at = excluded.created_at")
+
+			for _, r := range unique[i:min(i+importBatchSize, len(unique))] {
+				b = b.Values(r.Date, r.Base, r.Quote, r.Rate, r.Source, createdAt)
+			}
+
+			sql, args, err := b.ToSql()
+			if err != nil {
+				return err
+			}
+
+			if _, err := tx.Exec(ctx, sql, args...); err != nil {
+				return fmt.Errorf("exec: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("tx: %w", err)
+	}
+
+	return nil
+}
+
+type exchangeRate struct {
+	Date   time.Time `db:"date"`
+	Base   string    `db:"base"`
+	Quote  string    `db:"quote"`
+	Rate   float64   `db:"rate"`
+	Source string    `db:"source"`
+}
+
+// List returns rates of currencies on dates in [from, to) and the nearest rates before from together
+// with prices of assets set by user, price of user replaces shared rate of the same pair on the same date.
+func (s *ExchangeRateStorage) List(ctx context.Context, uid int64, currencies []string, from, to time.Time,
+) ([]domain.ExchangeRate, error) {
+	shared := s.Builder.
+		Select("date, base, quote, rate, source, 1 priority").
+		From("exchange_rates r").
+		Where(sq.Lt{"date": to}).
+		Where(sq.Or{
+			sq.GtOrEq{"date": from},
+			sq.Expr("date = (SELECT max(p.date) FROM exchange_rates p "+
+				"WHERE p.base = r.base AND p.quote = r.quote AND p.date < ?)", from),
+		}).
+		Where(sq.Or{
+			sq.Eq{"base": currencies},
+			sq.Eq{"quote": currencies},
+		})
+
+	// nested select of expression is built with question placeholders which are numbered by outer select
+	own := sq.
+		Select("date, ticker, quote, price", "'"+RateSourceUser+"', 0").
+		From("asset_prices r").
+		Where(sq.Eq{"user_id": uid}).
+		Where(sq.Lt{"date": to}).
+		Where(sq.Or{
+			sq.GtOrEq{"date": from},
+			sq.Expr("date = (SELECT max(p.date) FROM asset_prices p "+
+				"WHERE p.user_id = r.user_id AND p.ticker = r.ticker AND p.quote = r.quote AND p.date < ?)", from),
+		}).
+		Where(sq.Or{
+			sq.Eq{"ticker": currencies},
+			sq.Eq{"quote": currencies},
+		})
+
+	sql, args, err := s.Builder.
+		Select("DISTINCT ON (date, base, quote) date, base, quote, rate::float8, source").
+		FromSelect(shared.SuffixExpr(sq.ConcatExpr("UNION ALL ", own)), "r").
+		OrderBy("date, base, quote, priority").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[exchangeRate])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	rates := make([]domain.ExchangeRate, len(res))
+	for i, r := range res {
+		rates[i] = domain.ExchangeRate(r)
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+	"github.com/ysomad/financer/internal/postgres/pgclient"
+)
+
+type ReconciliationStorage struct {
+	*pgclient.Client
+}
+
+type reconciliation struct {
+	ID           string      `db:"id"`
+	UID          int64       `db:"user_id"`
+	AccountID    string      `db:"account_id"`
+	Currency     string      `db:"currency"`
+	Recorded     money.Money `db:"recorded"`
+	Actual       money.Money `db:"actual"`
+	AdjustmentID pgtype.Text `db:"adjustment_id"`
+	CreatedAt    time.Time   `db:"created_at"`
+}
+
+func (r reconciliation) toDomain() domain.Reconciliation {
+	return domain.Reconciliation{
+		ID:           r.ID,
+		UID:          r.UID,
+		AccountID:    r.AccountID,
+		Currency:     r.Currency,
+		Recorded:     r.Recorded,
+		Actual:       r.Actual,
+		AdjustmentID: r.AdjustmentID.String,
+		CreatedAt:    r.CreatedAt,
+	}
+}
+
+const reconciliationColumns = "id, user_id, account_id::text, currency, recorded, actual, adjustment_id::text, created_at"
+
+func (s *ReconciliationStorage) Save(ctx context.Context, r domain.Reconciliation) error {
+	sql, args, err := s.Builder.
+		Insert("reconciliations").
+		Columns("id, user_id, account_id, currency, recorded, actual, created_at").
+		Values(r.ID, r.UID, r.AccountID, r.Currency, r.Recorded, r.Actual, r.CreatedAt).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.Pool.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ReconciliationStorage) FindByID(ctx context.Context, uid int64, id string) (domain.Reconciliation, error) {
+	return s.find(ctx, sq.Eq{"user_id": uid, "id": id})
+}
+
+// FindLastBefore returns the latest reconciliation of account made before t.
+func (s *ReconciliationStorage) FindLastBefore(ctx context.Context, uid int64, accountID string, t time.Time) (domain.Reconciliation, error) {
+	return s.find(ctx, sq.And{
+		sq.Eq{"user_id": uid, "account_id": accountID},
+		sq.Lt{"created_at": t},
+	})
+}
+
+func (s *ReconciliationStorage) find(ctx context.Context, pred sq.Sqlizer) (domain.Reconciliation, error) {
+	sql, args, err := s.Builder.
+		Select(reconciliationColumns).
+		From("reconciliations").
+		Where(pred).
+		OrderBy("created_at DESC").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return domain.Reconciliation{}, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return domain.Reconciliation{}, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[reconciliation])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Reconciliation{}, ErrNotFound
+		}
+
+		return domain.Reconciliation{}, fmt.Errorf("scan: %w", err)
+	}
+
+	return res.toDomain(), nil
+}
+
+// ListByUserID returns reconciliations of user made in [from, to).
+func (s *ReconciliationStorage) ListByUserID(ctx context.Context, uid int64, from, to time.Time) ([]domain.Reconciliation, error) {
+	sql, args, err := s.Builder.
+		Select(reconciliationColumns).
+		From("reconciliations").
+		Where(sq.And{
+			sq.Eq{"user_id": uid},
+			sq.GtOrEq{"created_at": from},
+			sq.Lt{"created_at": to},
+		}).
+		OrderBy("created_at").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[reconciliation])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	recs := make([]domain.Reconciliation, len(res))
+	for i, r := range res {
+		recs[i] = r.toDomain()
+	}
+
+	return recs, nil
+}
+
+// SaveAdjustment saves adjustment operation of reconciliation difference and links it to reconciliation.
+func (s *ReconciliationStorage) SaveAdjustment(ctx context.Context, r domain.Reconciliation, opID string, now time.Time) error {
+	sql1, args1, err := s.Builder.
+		Insert("operations").
+		Columns("id, user_id, type, account_id, name, currency, money, occured_at, created_at").
+		Values(opID, r.UID, domain.OperationTypeAdjustment, r.AccountID, domain.AdjustmentName,
+			r.Currency, r.Difference(), now, now).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	sql2, args2, err := s.Builder.
+		Update("reconciliations").
+		Set("adjustment_id", opID).
+		Where(sq.Eq{"id": r.ID, "user_id": r.UID, "adjustment_id": nil}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	return pgx.BeginTxFunc(ctx, s.Pool, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, sql1, args1...); err != nil {
+			return fmt.Errorf("adjustment not saved: %w", err)
+		}
+
+		tag, err := tx.Exec(ctx, sql2, args2...)
+		if err != nil {
+			return fmt.Errorf("reconciliation not updated: %w", err)
+		}
+
+		// adjustment is already posted
+		if tag.RowsAffected() == 0 {
+			return ErrAlreadyExists
+		}
+
+		return nil
+	})
+}
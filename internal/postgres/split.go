@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+	"github.com/ysomad/financer/internal/postgres/pgclient"
+)
+
+type SplitStorage struct {
+	*pgclient.Client
+}
+
+// participantArg converts split participant to nullable column value.
+func participantArg(p string) *string {
+	if p == domain.SplitSelf {
+		return nil
+	}
+
+	return &p
+}
+
+func (s *SplitStorage) Save(ctx context.Context, split domain.Split, createdAt time.Time) error {
+	var opID *string
+	if split.OperationID != "" {
+		opID = &split.OperationID
+	}
+
+	sql, args, err := s.Builder.
+		Insert("splits").
+		Columns("id, user_id, operation_id, name, currency, money, payer, occured_at, created_at").
+		Values(split.ID, split.UID, opID, split.Name, split.Currency,
+			split.Money, participantArg(split.Payer), split.OccuredAt, createdAt).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	b := s.Builder.
+		Insert("split_shares").
+		Columns("split_id, participant, money")
+
+	for _, share := range split.Shares {
+		b = b.Values(split.ID, participantArg(share.Participant), share.Money)
+	}
+
+	sharesSQL, sharesArgs, err := b.ToSql()
+	if err != nil {
+		return err
+	}
+
+	return pgx.BeginTxFunc(ctx, s.Pool, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return fmt.Errorf("split not saved: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, sharesSQL, sharesArgs...); err != nil {
+			return fmt.Errorf("split shares not saved: %w", err)
+		}
+
+		return nil
+	})
+}
+
+type splitShare struct {
+	SplitID     string      `db:"split_id"`
+	Name        string      `db:"name"`
+	Currency    string      `db:"currency"`
+	Total       money.Money `db:"total"`
+	Payer       pgtype.Text `db:"payer"`
+	OccuredAt   time.Time   `db:"occured_at"`
+	Participant pgtype.Text `db:"participant"`
+	Money       money.Money `db:"money"`
+}
+
+// ListUnsettled returns splits of user which are not settled up yet.
+func (s *SplitStorage) ListUnsettled(ctx context.Context, uid int64) ([]domain.Split, error) {
+	sql, args, err := s.Builder.
+		Select("s.id split_id, s.name name, s.currency currency, s.money total, s.payer payer",
+			"s.occured_at occured_at, ss.participant participant, ss.money money").
+		From("splits s").
+		InnerJoin("split_shares ss ON ss.split_id = s.id").
+		Where(sq.And{
+			sq.Eq{"s.user_id": uid},
+			sq.Eq{"s.settled_at": nil},
+		}).
+		OrderBy("s.occured_at, s.id").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[splitShare])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	splits := make([]domain.Split, 0)
+
+	for _, r := range res {
+		if len(splits) == 0 || splits[len(splits)-1].ID != r.SplitID {
+			splits = append(splits, domain.Split{
+				ID:        r.SplitID,
+				UID:       uid,
+				Name:      r.Name,
+				Currency:  r.Currency,
+				Payer:     r.Payer.String,
+				Money:     r.Total,
+				OccuredAt: r.OccuredAt,
+			})
+		}
+
+		last := &splits[len(splits)-1]
+		last.Shares = append(last.Shares, domain.SplitShare{
+			Participant: r.Participant.String,
+			Money:       r.Money,
+			Exact:       true,
+		})
+	}
+
+	return splits, nil
+}
+
+// SettleAll marks all splits of user as settled up.
+func (s *SplitStorage) SettleAll(ctx context.Context, uid int64, t time.Time) error {
+	sql, args, err := s.Builder.
+		Update("splits").
+		Set("settled_at", t).
+		Where(sq.And{
+			sq.Eq{"user_id": uid},
+			sq.Eq{"settled_at": nil},
+		}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.Pool.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
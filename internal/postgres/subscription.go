@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+	"github.com/ysomad/financer/internal/postgres/pgclient"
+)
+
+type SubscriptionStorage struct {
+	*pgclient.Client
+}
+
+type subscription struct {
+	ID       string      `db:"id"`
+	UID      int64       `db:"user_id"`
+	CatID    pgtype.Text `db:"category_id"`
+	Name     string      `db:"name"`
+	Currency string      `db:"currency"`
+	Money    money.Money `db:"money"`
+	Interval int         `db:"interval_days"`
+	NextAt   time.Time   `db:"next_payment_at"`
+}
+
+func (s subscription) toDomain() domain.Subscription {
+	return domain.Subscription{
+		ID:       s.ID,
+		UID:      s.UID,
+		CatID:    s.CatID.String,
+		Name:     s.Name,
+		Currency: s.Currency,
+		Money:    s.Money,
+		Interval: s.Interval,
+		NextAt:   s.NextAt,
+		Tracked:  true,
+	}
+}
+
+const subscriptionColumns = "id, user_id, category_id::text, name, currency, money, interval_days, next_payment_at"
+
+type SaveSubscriptionParams struct {
+	ID        string
+	UID       int64
+	CatID     string
+	Name      string
+	Currency  string
+	Money     money.Money
+	Interval  int
+	NextAt    time.Time
+	CreatedAt time.Time
+}
+
+func (s *SubscriptionStorage) Save(ctx context.Context, p SaveSubscriptionParams) error {
+	var catID *string
+	if p.CatID != "" {
+		catID = &p.CatID
+	}
+
+	sql, args, err := s.Builder.
+		Insert("subscriptions").
+		Columns("id, user_id, category_id, name, currency",
+			"money, interval_days, next_payment_at, created_at").
+		Values(p.ID, p.UID, catID, p.Name, p.Currency,
+			p.Money, p.Interval, p.NextAt, p.CreatedAt).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.Pool.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SubscriptionStorage) ListByUserID(ctx context.Context, uid int64) ([]domain.Subscription, error) {
+	sql, args, err := s.Builder.
+		Select(subscriptionColumns).
+		From("subscriptions").
+		Where(sq.And{
+			sq.Eq{"user_id": uid},
+			sq.Eq{"deleted_at": nil},
+		}).
+		OrderBy("next_payment_at").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[subscription])
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+
+	subs := make([]domain.Subscription, len(res))
+	for i, sub := range res {
+		subs[i] = sub.toDomain()
+	}
+
+	return subs, nil
+}
+
+// FindByName finds tracked subscription by operation name and currency, names are compared
+// in the form of domain.NormalizeOperationName.
+func (s *SubscriptionStorage) FindByName(ctx context.Context, uid int64, name, currency string) (domain.Subscription, error) {
+	sql, args, err := s.Builder.
+		Select(subscriptionColumns).
+		From("subscriptions").
+		Where(sq.And{
+			sq.Eq{"user_id": uid},
+			sq.Eq{"deleted_at": nil},
+			sq.Expr(`btrim(regexp_replace(lower(name), '\s+', ' ', 'g')) = ?`, domain.NormalizeOperationName(name)),
+			sq.Eq{"currency": currency},
+		}).
+		ToSql()
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+
+	rows, err := s.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return domain.Subscription{}, fmt.Errorf("query: %w", err)
+	}
+
+	res, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[subscription])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Subscription{}, ErrNotFound
+		}
+
+		return domain.Subscription{}, fmt.Errorf("scan: %w", err)
+	}
+
+	return res.toDomain(), nil
+}
+
+type UpdateSubscriptionChargeParams struct {
+	ID        string
+	Money     money.Money
+	NextAt    time.Time
+	UpdatedAt time.Time
+}
+
+// UpdateCharge updates last charged amount and next payment date of subscription.
+func (s *SubscriptionStorage) UpdateCharge(ctx context.Context, p UpdateSubscriptionChargeParams) error {
+	sql, args, err := s.Builder.
+		Update("subscriptions").
+		Set("money", p.Money).
+		Set("next_payment_at", p.NextAt).
+		Set("updated_at", p.UpdatedAt).
+		Where(sq.Eq{"id": p.ID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.Pool.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
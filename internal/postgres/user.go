@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
@@ -18,15 +19,18 @@ type UserStorage struct {
 }
 
 type user struct {
-	ID       int64  `db:"id"`
-	Currency string `db:"currency"`
-	Language string `db:"language"`
+	ID              int64  `db:"id"`
+	Currency        string `db:"currency"`
+	Language        string `db:"language"`
+	PayDay          int    `db:"pay_day"`
+	AllowanceFooter bool   `db:"allowance_footer"`
 }
 
 type CreateUserParams struct {
 	UID       int64
 	Currency  string
 	Language  string
+	AccountID string // id of default account
 	CreatedAt time.Time
 }
 
@@ -53,6 +57,18 @@ func (s *UserStorage) Create(ctx context.Context, p CreateUserParams) error {
 		return err
 	}
 
+	sql3, args3, err := SaveAccountParams{
+		ID:        p.AccountID,
+		UID:       p.UID,
+		Name:      domain.DefaultAccountName,
+		Currency:  p.Currency,
+		IsDefault: true,
+		CreatedAt: p.CreatedAt,
+	}.insert(s.Builder)
+	if err != nil {
+		return err
+	}
+
 	return pgx.BeginTxFunc(ctx, s.Pool, pgx.TxOptions{}, func(tx pgx.Tx) error {
 		if _, err := tx.Exec(ctx, sql1, args1...); err != nil {
 			return fmt.Errorf("user not created: %w", err)
@@ -62,13 +78,17 @@ func (s *UserStorage) Create(ctx context.Context, p CreateUserParams) error {
 			return fmt.Errorf("categories not attached: %w", err)
 		}
 
+		if _, err := tx.Exec(ctx, sql3, args3...); err != nil {
+			return fmt.Errorf("default account not created: %w", err)
+		}
+
 		return nil
 	})
 }
 
 func (s *UserStorage) Find(ctx context.Context, uid int64) (domain.User, error) {
 	sql, args, err := s.Builder.
-		Select("id, currency, language").
+		Select("id, currency, language, pay_day, allowance_footer").
 		From("users").
 		Where(sq.Eq{"id": uid}).
 		ToSql()
@@ -94,17 +114,34 @@ func (s *UserStorage) Find(ctx context.Context, uid int64) (domain.User, error)
 }
 
 type UpdateParams struct {
-	UID       int64
-	Language  string
-	Currency  string
-	UpdatedAt time.Time
+	UID             int64
+	Language        string
+	Currency        string
+	PayDay          int
+	AllowanceFooter bool
+	AccountID       string // id of account created if user has no account in new currency
+	UpdatedAt       time.Time
 }
 
+// Update updates user settings. If currency is changed, account in the new currency becomes default
+// one as described in domain.DefaultAccountFor.
 func (s *UserStorage) Update(ctx context.Context, p UpdateParams) error {
-	sql, args, err := s.Builder.
+	sql1, args1, err := s.Builder.
+		Select("currency").
+		From("users").
+		Where(sq.Eq{"id": p.UID}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	sql2, args2, err := s.Builder.
 		Update("users").
 		Set("language", p.Language).
 		Set("currency", p.Currency).
+		Set("pay_day", p.PayDay).
+		Set("allowance_footer", p.AllowanceFooter).
 		Set("updated_at", p.UpdatedAt).
 		Where(sq.Eq{"id": p.UID}).
 		ToSql()
@@ -112,9 +149,127 @@ func (s *UserStorage) Update(ctx context.Context, p UpdateParams) error {
 		return err
 	}
 
-	if _, err := s.Pool.Exec(ctx, sql, args...); err != nil {
+	return pgx.BeginTxFunc(ctx, s.Pool, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		var currency string
+
+		if err := tx.QueryRow(ctx, sql1, args1...).Scan(&currency); err != nil {
+			return fmt.Errorf("currency not found: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, sql2, args2...); err != nil {
+			return err
+		}
+
+		if currency == p.Currency {
+			return nil
+		}
+
+		return s.setDefaultAccount(ctx, tx, p)
+	})
+}
+
+// setDefaultAccount makes account in user currency default one, new account is created
+// if user has no account in the currency.
+func (s *UserStorage) setDefaultAccount(ctx context.Context, tx pgx.Tx, p UpdateParams) error {
+	sql, args, err := s.Builder.
+		Select(accountColumns, accountUsed).
+		From("accounts a").
+		Where(sq.Eq{"a.user_id": p.UID, "a.deleted_at": nil}).
+		OrderBy("a.is_default DESC, a.created_at").
+		ToSql()
+	if err != nil {
 		return err
 	}
 
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("accounts: query: %w", err)
+	}
+
+	res, err := pgx.CollectRows(rows, pgx.RowToStructByName[usedAccount])
+	if err != nil {
+		return fmt.Errorf("accounts: scan: %w", err)
+	}
+
+	var (
+		accs   = make([]domain.Account, len(res))
+		used   = make(map[string]bool, len(res))
+		byName = make(map[string]usedAccount, len(res))
+	)
+
+	for i, a := range res {
+		accs[i] = domain.Account(a.account)
+		used[a.ID] = a.Used
+		byName[strings.ToLower(a.Name)] = a
+	}
+
+	acc, ok := domain.DefaultAccountFor(accs, used, p.Currency)
+	if !ok {
+		acc = domain.Account{
+			ID:       p.AccountID,
+			Name:     freeAccountName(byName, domain.DefaultAccountName, p.Currency),
+			Currency: p.Currency,
+		}
+
+		sql, args, err := SaveAccountParams{
+			ID:        acc.ID,
+			UID:       p.UID,
+			Name:      acc.Name,
+			Currency:  acc.Currency,
+			CreatedAt: p.UpdatedAt,
+		}.insert(s.Builder)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return fmt.Errorf("account not created: %w", err)
+		}
+	}
+
+	// unused default account is moved to the new currency
+	if acc.IsDefault {
+		sql, args, err := s.Builder.
+			Update("accounts").
+			Set("currency", acc.Currency).
+			Where(sq.Eq{"id": acc.ID}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return fmt.Errorf("default account currency not updated: %w", err)
+		}
+
+		return nil
+	}
+
+	sql1, args1, err := s.Builder.
+		Update("accounts").
+		Set("is_default", false).
+		Where(sq.Eq{"user_id": p.UID, "is_default": true}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	sql2, args2, err := s.Builder.
+		Update("accounts").
+		Set("is_default", true).
+		Where(sq.Eq{"id": acc.ID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, sql1, args1...); err != nil {
+		return fmt.Errorf("default account not reset: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, sql2, args2...); err != nil {
+		return fmt.Errorf("default account not set: %w", err)
+	}
+
 	return nil
 }
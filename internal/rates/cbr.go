@@ -0,0 +1,76 @@
+package rates
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/charmap"
+
+	"github.com/ysomad/financer/internal/domain"
+)
+
+const (
+	SourceCBR = "cbr"
+	CBRURL    = "https://www.cbr.ru/scripts/XML_daily.asp"
+)
+
+type cbrValCurs struct {
+	Date    string `xml:"Date,attr"`
+	Valutes []struct {
+		CharCode string `xml:"CharCode"`
+		Nominal  string `xml:"Nominal"`
+		Value    string `xml:"Value"`
+	} `xml:"Valute"`
+}
+
+// ParseCBR parses daily rates of Central Bank of Russia, rates are quoted as 1 currency unit in RUB.
+// Documents are encoded in windows-1251 and use comma as decimal separator.
+func ParseCBR(r io.Reader) ([]domain.ExchangeRate, error) {
+	var vc cbrValCurs
+
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		if strings.EqualFold(label, "windows-1251") {
+			return charmap.Windows1251.NewDecoder().Reader(input), nil
+		}
+
+		return nil, fmt.Errorf("unsupported charset: %s", label)
+	}
+
+	if err := dec.Decode(&vc); err != nil {
+		return nil, fmt.Errorf("cbr: %w", err)
+	}
+
+	date, err := time.Parse("02.01.2006", vc.Date)
+	if err != nil {
+		return nil, fmt.Errorf("cbr: %w", err)
+	}
+
+	res := make([]domain.ExchangeRate, 0, len(vc.Valutes))
+
+	for _, v := range vc.Valutes {
+		value, err := strconv.ParseFloat(strings.Replace(v.Value, ",", ".", 1), 64)
+		if err != nil {
+			return nil, fmt.Errorf("cbr: %s value: %w", v.CharCode, err)
+		}
+
+		nominal, err := strconv.Atoi(v.Nominal)
+		if err != nil || nominal <= 0 {
+			return nil, fmt.Errorf("cbr: %s invalid nominal: %s", v.CharCode, v.Nominal)
+		}
+
+		res = append(res, domain.ExchangeRate{
+			Date:   date,
+			Base:   v.CharCode,
+			Quote:  "RUB",
+			Rate:   value / float64(nominal),
+			Source: SourceCBR,
+		})
+	}
+
+	return res, nil
+}
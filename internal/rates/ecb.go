@@ -0,0 +1,62 @@
+package rates
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ysomad/financer/internal/domain"
+)
+
+const (
+	SourceECB = "ecb"
+	ECBURL    = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+)
+
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// ParseECB parses daily or historical euro foreign exchange reference rates of European Central Bank,
+// rates are quoted as 1 EUR in currency.
+func ParseECB(r io.Reader) ([]domain.ExchangeRate, error) {
+	var env ecbEnvelope
+
+	if err := xml.NewDecoder(r).Decode(&env); err != nil {
+		return nil, fmt.Errorf("ecb: %w", err)
+	}
+
+	var res []domain.ExchangeRate
+
+	for _, day := range env.Days {
+		date, err := time.Parse(time.DateOnly, day.Time)
+		if err != nil {
+			return nil, fmt.Errorf("ecb: %w", err)
+		}
+
+		for _, r := range day.Rates {
+			rate, err := strconv.ParseFloat(r.Rate, 64)
+			if err != nil {
+				return nil, fmt.Errorf("ecb: %s rate: %w", r.Currency, err)
+			}
+
+			res = append(res, domain.ExchangeRate{
+				Date:   date,
+				Base:   "EUR",
+				Quote:  r.Currency,
+				Rate:   rate,
+				Source: SourceECB,
+			})
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,53 @@
+package rates
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/ysomad/financer/internal/domain"
+)
+
+// HTTP fetches exchange rates document from URL, client and URL can be replaced in tests.
+type HTTP struct {
+	URL    string
+	Client *http.Client
+	Parse  ParseFunc
+}
+
+func NewECB(client *http.Client) *HTTP {
+	return &HTTP{URL: ECBURL, Client: client, Parse: ParseECB}
+}
+
+func NewCBR(client *http.Client) *HTTP {
+	return &HTTP{URL: CBRURL, Client: client, Parse: ParseCBR}
+}
+
+func (h *HTTP) Rates(ctx context.Context) ([]domain.ExchangeRate, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	parse := h.Parse
+	if parse == nil {
+		parse = Parse
+	}
+
+	return parse(resp.Body)
+}
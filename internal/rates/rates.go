@@ -0,0 +1,84 @@
+// Package rates loads exchange rates published by central banks.
+package rates
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ysomad/financer/internal/domain"
+)
+
+var ErrUnknownFormat = errors.New("unknown exchange rates format")
+
+// Provider provides exchange rates from any source.
+type Provider interface {
+	Rates(ctx context.Context) ([]domain.ExchangeRate, error)
+}
+
+// ParseFunc parses exchange rates document.
+type ParseFunc func(r io.Reader) ([]domain.ExchangeRate, error)
+
+// Parse detects format of exchange rates document and parses it.
+func Parse(r io.Reader) ([]domain.ExchangeRate, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case bytes.Contains(b, []byte("eurofxref")):
+		return ParseECB(bytes.NewReader(b))
+	case bytes.Contains(b, []byte("<ValCurs")):
+		return ParseCBR(bytes.NewReader(b))
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// Dir is a directory with downloaded ECB and CBR xml files.
+type Dir string
+
+func (d Dir) Rates(_ context.Context) ([]domain.ExchangeRate, error) {
+	return LoadDir(string(d))
+}
+
+// LoadDir parses all xml files in directory.
+func LoadDir(dir string) ([]domain.ExchangeRate, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []domain.ExchangeRate
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.EqualFold(filepath.Ext(e.Name()), ".xml") {
+			continue
+		}
+
+		rates, err := loadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), err)
+		}
+
+		res = append(res, rates...)
+	}
+
+	return res, nil
+}
+
+func loadFile(path string) ([]domain.ExchangeRate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
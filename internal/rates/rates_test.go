@@ -0,0 +1,85 @@
+package rates
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ysomad/financer/internal/domain"
+)
+
+func TestParseECB(t *testing.T) {
+	f, err := os.Open("testdata/eurofxref-daily.xml")
+	require.NoError(t, err)
+	defer f.Close()
+
+	rates, err := ParseECB(f)
+	require.NoError(t, err)
+	require.Len(t, rates, 3)
+	require.Equal(t, domain.ExchangeRate{
+		Date:   time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+		Base:   "EUR",
+		Quote:  "USD",
+		Rate:   1.0845,
+		Source: SourceECB,
+	}, rates[0])
+}
+
+func TestParseCBR(t *testing.T) {
+	f, err := os.Open("testdata/XML_daily.xml")
+	require.NoError(t, err)
+	defer f.Close()
+
+	rates, err := ParseCBR(f)
+	require.NoError(t, err)
+	require.Len(t, rates, 3)
+
+	date := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	require.Equal(t, domain.ExchangeRate{Date: date, Base: "USD", Quote: "RUB", Rate: 91.2829, Source: SourceCBR}, rates[0])
+	// JPY nominal is 100
+	require.Equal(t, "JPY", rates[2].Base)
+	require.InDelta(t, 0.607341, rates[2].Rate, 1e-9)
+}
+
+func TestParseUnknown(t *testing.T) {
+	_, err := Parse(strings.NewReader("<rates/>"))
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestLoadDir(t *testing.T) {
+	rates, err := Dir("testdata").Rates(context.Background())
+	require.NoError(t, err)
+	// 4 from historical, 3 from daily ECB and 3 from CBR
+	require.Len(t, rates, 10)
+}
+
+func TestHTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/scripts/XML_daily.asp" {
+			http.NotFound(w, r)
+			return
+		}
+
+		http.ServeFile(w, r, "testdata/XML_daily.xml")
+	}))
+	defer srv.Close()
+
+	p := NewCBR(srv.Client())
+	p.URL = srv.URL + "/scripts/XML_daily.asp"
+
+	rates, err := p.Rates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rates, 3)
+
+	p.URL = srv.URL + "/missing"
+
+	_, err = p.Rates(context.Background())
+	require.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/money"
+	"github.com/ysomad/financer/internal/postgres"
+)
+
+type Allowance struct {
+	operation    *postgres.OperationStorage
+	bill         *postgres.BillStorage
+	subscription *postgres.SubscriptionStorage
+	rate         *postgres.ExchangeRateStorage
+}
+
+func NewAllowance(op *postgres.OperationStorage, bill *postgres.BillStorage, sub *postgres.SubscriptionStorage,
+	rate *postgres.ExchangeRateStorage,
+) *Allowance {
+	return &Allowance{
+		operation:    op,
+		bill:         bill,
+		subscription: sub,
+		rate:         rate,
+	}
+}
+
+// Today calculates money which is safe to spend today converted to user default currency.
+func (a *Allowance) Today(ctx context.Context, usr domain.User, now time.Time) (domain.Allowance, error) {
+	from := domain.AllowanceHistoryStart(usr.PayDay, now)
+
+	ops, err := a.operation.ListByUserID(ctx, usr.ID, from, now.AddDate(0, 0, 1))
+	if err != nil {
+		return domain.Allowance{}, fmt.Errorf("operations not listed: %w", err)
+	}
+
+	bills, err := a.bill.ListByUserID(ctx, usr.ID)
+	if err != nil {
+		return domain.Allowance{}, fmt.Errorf("bills not listed: %w", err)
+	}
+
+	subs, err := a.subscription.ListByUserID(ctx, usr.ID)
+	if err != nil {
+		return domain.Allowance{}, fmt.Errorf("subscriptions not listed: %w", err)
+	}
+
+	curs := slices.Concat(
+		currencies(ops, func(op domain.Operation) string { return op.Currency }),
+		currencies(bills, func(b domain.Bill) string { return b.Currency }),
+		currencies(subs, func(s domain.Subscription) string { return s.Currency }),
+	)
+
+	conv, err := newConverter(ctx, a.rate, usr.ID, usr.Currency, curs, from, now.AddDate(0, 0, 1))
+	if err != nil {
+		return domain.Allowance{}, err
+	}
+
+	var approximate bool
+
+	// operations are converted by rate on operation date, upcoming payments by the latest rate
+	convert := func(m *money.Money, cur string, at time.Time) bool {
+		res, exact, err := conv.Convert(*m, cur, usr.Currency, at)
+		if err != nil {
+			return false
+		}
+
+		*m = res
+		approximate = approximate || !exact
+
+		return true
+	}
+
+	ops = convertItems(ops, func(op *domain.Operation) bool { return convert(&op.Money, op.Currency, op.OccuredAt) })
+	bills = convertItems(bills, func(b *domain.Bill) bool { return convert(&b.Money, b.Currency, now) })
+	subs = convertItems(subs, func(s *domain.Subscription) bool { return convert(&s.Money, s.Currency, now) })
+
+	allowance := domain.CalcAllowance(domain.AllowanceParams{
+		PayDay:        usr.PayDay,
+		Now:           now,
+		Operations:    ops,
+		Bills:         bills,
+		Subscriptions: subs,
+	})
+	allowance.Approximate = approximate
+
+	return allowance, nil
+}
+
+// convertItems converts money of items in place, items which cannot be converted are removed.
+func convertItems[T any](items []T, convert func(*T) bool) []T {
+	res := items[:0]
+
+	for i := range items {
+		if convert(&items[i]) {
+			res = append(res, items[i])
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/postgres"
+)
+
+// netWorthMonths is number of previous months in net worth history.
+const netWorthMonths = 12
+
+type NetWorth struct {
+	account  *postgres.AccountStorage
+	debt     *postgres.DebtStorage
+	snapshot *postgres.NetWorthStorage
+	rate     *postgres.ExchangeRateStorage
+	asset    *postgres.AssetStorage
+}
+
+func NewNetWorth(acc *postgres.AccountStorage, debt *postgres.DebtStorage, snapshot *postgres.NetWorthStorage,
+	rate *postgres.ExchangeRateStorage, asset *postgres.AssetStorage,
+) *NetWorth {
+	return &NetWorth{
+		account:  acc,
+		debt:     debt,
+		snapshot: snapshot,
+		rate:     rate,
+		asset:    asset,
+	}
+}
+
+// Calc returns net worth of user in default currency with monthly history.
+// Snapshots of previous months are reused if nothing they depend on was changed, stale and missing ones are recomputed.
+func (n *NetWorth) Calc(ctx context.Context, usr domain.User, now time.Time) (domain.NetWorth, error) {
+	// dates are stored without time zone and scanned in UTC
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	curMonth := domain.MonthStart(today)
+	from := curMonth.AddDate(0, -netWorthMonths, 0)
+
+	fresh, err := n.snapshot.ListFresh(ctx, usr.ID, usr.Currency, from, curMonth)
+	if err != nil {
+		return domain.NetWorth{}, fmt.Errorf("snapshots not listed: %w", err)
+	}
+
+	accs, err := n.account.ListByUserID(ctx, usr.ID)
+	if err != nil {
+		return domain.NetWorth{}, fmt.Errorf("accounts not listed: %w", err)
+	}
+
+	movements, err := n.account.ListMovements(ctx, usr.ID)
+	if err != nil {
+		return domain.NetWorth{}, fmt.Errorf("movements not listed: %w", err)
+	}
+
+	debts, err := n.debt.ListByUserID(ctx, usr.ID)
+	if err != nil {
+		return domain.NetWorth{}, fmt.Errorf("debts not listed: %w", err)
+	}
+
+	assets, err := n.asset.ListMovements(ctx, usr.ID)
+	if err != nil {
+		return domain.NetWorth{}, fmt.Errorf("asset movements not listed: %w", err)
+	}
+
+	// history starts from the month of the first movement, debt or asset operation
+	first := curMonth
+
+	for _, m := range movements {
+		if m.OccuredAt.Before(first) {
+			first = domain.MonthStart(m.OccuredAt)
+		}
+	}
+
+	for _, d := range debts {
+		if d.OccuredAt.Before(first) {
+			first = domain.MonthStart(d.OccuredAt)
+		}
+	}
+
+	for _, a := range assets {
+		if a.OccuredAt.Before(first) {
+			first = domain.MonthStart(a.OccuredAt)
+		}
+	}
+
+	from = maxTime(from, first)
+
+	curs := slices.Concat(
+		currencies(accs, func(a domain.Account) string { return a.Currency }),
+		currencies(debts, func(d domain.Debt) string { return d.Currency }),
+		currencies(assets, func(a domain.AssetMovement) string { return a.Ticker }),
+	)
+
+	conv, err := newConverter(ctx, n.rate, usr.ID, usr.Currency, curs, from, today.AddDate(0, 0, 1))
+	if err != nil {
+		return domain.NetWorth{}, err
+	}
+
+	params := domain.NetWorthParams{
+		Currency:  usr.Currency,
+		Accounts:  accs,
+		Movements: movements,
+		Debts:     debts,
+		Assets:    assets,
+		Converter: conv,
+	}
+
+	nw := domain.NetWorth{Currency: usr.Currency}
+	computed := make([]domain.NetWorthSnapshot, 0)
+
+	for month := from; month.Before(curMonth); month = month.AddDate(0, 1, 0) {
+		i := slices.IndexFunc(fresh, func(s domain.NetWorthSnapshot) bool { return s.Month.Equal(month) })
+		if i >= 0 {
+			nw.History = append(nw.History, fresh[i])
+			continue
+		}
+
+		params.To = month.AddDate(0, 1, 0)
+		snap := domain.NetWorthSnapshot{Month: month}
+		snap.Money, snap.Approximate = domain.CalcNetWorth(params)
+
+		nw.History = append(nw.History, snap)
+		computed = append(computed, snap)
+	}
+
+	// current month is never saved because it changes with every operation
+	params.To = today.AddDate(0, 0, 1)
+	cur := domain.NetWorthSnapshot{Month: curMonth}
+	cur.Money, cur.Approximate = domain.CalcNetWorth(params)
+	nw.History = append(nw.History, cur)
+
+	if err := n.snapshot.Save(ctx, usr.ID, usr.Currency, computed, now); err != nil {
+		return domain.NetWorth{}, fmt.Errorf("snapshots not saved: %w", err)
+	}
+
+	return nw, nil
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
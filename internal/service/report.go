@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ysomad/financer/internal/domain"
+	"github.com/ysomad/financer/internal/postgres"
+)
+
+type Report struct {
+	operation      *postgres.OperationStorage
+	rate           *postgres.ExchangeRateStorage
+	reconciliation *postgres.ReconciliationStorage
+	asset          *postgres.AssetStorage
+}
+
+func NewReport(op *postgres.OperationStorage, rate *postgres.ExchangeRateStorage,
+	rec *postgres.ReconciliationStorage, asset *postgres.AssetStorage,
+) *Report {
+	return &Report{
+		operation:      op,
+		rate:           rate,
+		reconciliation: rec,
+		asset:          asset,
+	}
+}
+
+// Build returns income and expenses of user in [from, to) and assets held at the end of period
+// converted to user default currency.
+func (r *Report) Build(ctx context.Context, usr domain.User, from, to time.Time) (domain.Report, error) {
+	ops, err := r.operation.ListByUserID(ctx, usr.ID, from, to)
+	if err != nil {
+		return domain.Report{}, fmt.Errorf("operations not listed: %w", err)
+	}
+
+	recs, err := r.reconciliation.ListByUserID(ctx, usr.ID, from, to)
+	if err != nil {
+		return domain.Report{}, fmt.Errorf("reconciliations not listed: %w", err)
+	}
+
+	assets, err := r.asset.ListMovements(ctx, usr.ID)
+	if err != nil {
+		return domain.Report{}, fmt.Errorf("asset movements not listed: %w", err)
+	}
+
+	curs := slices.Concat(
+		currencies(ops, func(op domain.Operation) string { return op.Currency }),
+		currencies(recs, func(r domain.Reconciliation) string { return r.Currency }),
+		currencies(assets, func(a domain.AssetMovement) string { return a.Ticker }),
+	)
+
+	conv, err := newConverter(ctx, r.rate, usr.ID, usr.Currency, curs, from, to)
+	if err != nil {
+		return domain.Report{}, err
+	}
+
+	report := domain.CalcReport(ops, conv, usr.Currency, from, to)
+	report.AddDrift(recs, conv)
+	report.AddAssets(assets, conv)
+
+	return report, nil
+}
+
+// Rate returns reference rate of base currency or asset of user in quote currency on date and true if rate is exact.
+func (r *Report) Rate(ctx context.Context, uid int64, base, quote string, at time.Time) (float64, bool, error) {
+	conv, err := newConverter(ctx, r.rate, uid, quote, []string{base}, at, at.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, false, err
+	}
+
+	return conv.Rate(base, quote, at)
+}
+
+// newConverter loads exchange rates and asset prices of user required for conversion to currency
+// on dates in [from, to).
+func newConverter(ctx context.Context, rates *postgres.ExchangeRateStorage, uid int64, currency string, curs []string,
+	from, to time.Time,
+) (*domain.Converter, error) {
+	curs = slices.DeleteFunc(curs, func(cur string) bool { return cur == currency })
+	if len(curs) == 0 {
+		return domain.NewConverter(nil), nil
+	}
+
+	rr, err := rates.List(ctx, uid, append(curs, currency), from, to)
+	if err != nil {
+		return nil, fmt.Errorf("exchange rates not listed: %w", err)
+	}
+
+	return domain.NewConverter(rr), nil
+}
+
+// currencies returns unique currencies of items.
+func currencies[T any](items []T, currency func(T) string) []string {
+	res := make([]string, 0)
+
+	for _, item := range items {
+		if cur := currency(item); !slices.Contains(res, cur) {
+			res = append(res, cur)
+		}
+	}
+
+	return res
+}
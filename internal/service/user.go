@@ -7,6 +7,8 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/ysomad/financer/internal/domain"
 	"github.com/ysomad/financer/internal/postgres"
 )
@@ -14,6 +16,7 @@ import (
 const (
 	defaultCurrency = "USD"
 	defaultLanguage = "en"
+	defaultPayDay   = 1
 )
 
 type User struct {
@@ -40,6 +43,7 @@ func (u *User) GetOrCreate(ctx context.Context, uid int64) (domain.User, error)
 		UID:       uid,
 		Currency:  defaultCurrency,
 		Language:  defaultLanguage,
+		AccountID: uuid.NewString(),
 		CreatedAt: time.Now(),
 	}
 
@@ -51,6 +55,7 @@ func (u *User) GetOrCreate(ctx context.Context, uid int64) (domain.User, error)
 		ID:       params.UID,
 		Currency: params.Currency,
 		Language: params.Language,
+		PayDay:   defaultPayDay,
 	}, nil
 }
 
@@ -60,10 +65,13 @@ func (u *User) Update(ctx context.Context, usr domain.User) error {
 	}
 
 	if err := u.storage.Update(ctx, postgres.UpdateParams{
-		UID:       usr.ID,
-		Language:  usr.Language,
-		Currency:  usr.Currency,
-		UpdatedAt: time.Now(),
+		UID:             usr.ID,
+		Language:        usr.Language,
+		Currency:        usr.Currency,
+		PayDay:          usr.PayDay,
+		AllowanceFooter: usr.AllowanceFooter,
+		AccountID:       uuid.NewString(),
+		UpdatedAt:       time.Now(),
 	}); err != nil {
 		return fmt.Errorf("user not updated: %w", err)
 	}
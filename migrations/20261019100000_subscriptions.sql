@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id uuid PRIMARY KEY NOT NULL,
+    user_id bigint NOT NULL REFERENCES users (id),
+    category_id uuid REFERENCES categories (id),
+    name varchar(64) NOT NULL,
+    currency char(3) NOT NULL,
+    money int NOT NULL,
+    interval_days smallint NOT NULL,
+    next_payment_at date NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+
+-- name is compared as domain.NormalizeOperationName does, see SubscriptionStorage.FindByName
+CREATE UNIQUE INDEX idx_active_subscription_name
+ON subscriptions (user_id, btrim(regexp_replace(lower(name), '\s+', ' ', 'g')), currency)
+WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_operations_user_occured_at ON operations (user_id, occured_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_operations_user_occured_at;
+DROP TABLE IF EXISTS subscriptions;
+-- +goose StatementEnd
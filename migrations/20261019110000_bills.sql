@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS bills (
+    id uuid PRIMARY KEY NOT NULL,
+    user_id bigint NOT NULL REFERENCES users (id),
+    name varchar(64) NOT NULL,
+    currency char(3) NOT NULL,
+    money int NOT NULL,
+    due_day smallint NOT NULL CHECK (due_day BETWEEN 1 AND 31),
+    remind_days smallint NOT NULL CHECK (remind_days >= 0),
+    next_due_at date NOT NULL,
+    reminded_at timestamptz,
+    escalated_at timestamptz,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+
+CREATE INDEX idx_active_bills_next_due_at ON bills (next_due_at)
+WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS bills;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE debt_type AS ENUM ('LEND', 'BORROW', 'REPAY');
+
+-- money is positive when person owes user and negative when user owes person
+CREATE TABLE IF NOT EXISTS debts (
+    id uuid PRIMARY KEY NOT NULL,
+    user_id bigint NOT NULL REFERENCES users (id),
+    person varchar(64) NOT NULL,
+    type debt_type NOT NULL,
+    currency char(3) NOT NULL,
+    money int NOT NULL,
+    occured_at date NOT NULL,
+    created_at timestamptz NOT NULL,
+    deleted_at timestamptz
+);
+
+CREATE INDEX idx_debts_user_person ON debts (user_id, lower(person))
+WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS debts;
+DROP TYPE IF EXISTS debt_type;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- payer and participant are NULL for the user who records the split
+CREATE TABLE IF NOT EXISTS splits (
+    id uuid PRIMARY KEY NOT NULL,
+    user_id bigint NOT NULL REFERENCES users (id),
+    operation_id uuid REFERENCES operations (id),
+    name varchar(64) NOT NULL,
+    currency char(3) NOT NULL,
+    money int NOT NULL,
+    payer varchar(64),
+    occured_at date NOT NULL,
+    created_at timestamptz NOT NULL,
+    settled_at timestamptz
+);
+
+CREATE INDEX idx_unsettled_splits ON splits (user_id)
+WHERE settled_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS split_shares (
+    split_id uuid NOT NULL REFERENCES splits (id),
+    participant varchar(64),
+    money int NOT NULL
+);
+
+CREATE INDEX idx_split_shares_split_id ON split_shares (split_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS split_shares;
+DROP TABLE IF EXISTS splits;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN pay_day smallint NOT NULL DEFAULT 1 CHECK (pay_day BETWEEN 1 AND 31),
+    ADD COLUMN allowance_footer boolean NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN IF EXISTS allowance_footer,
+    DROP COLUMN IF EXISTS pay_day;
+-- +goose StatementEnd
@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS accounts (
+    id uuid PRIMARY KEY NOT NULL,
+    user_id bigint NOT NULL REFERENCES users (id),
+    name varchar(64) NOT NULL,
+    currency char(3) NOT NULL,
+    opening_balance int NOT NULL DEFAULT 0,
+    is_default boolean NOT NULL DEFAULT false,
+    created_at timestamptz NOT NULL,
+    deleted_at timestamptz
+);
+
+CREATE UNIQUE INDEX idx_active_account_name ON accounts (user_id, lower(name))
+WHERE deleted_at IS NULL;
+
+CREATE UNIQUE INDEX idx_default_account ON accounts (user_id)
+WHERE is_default AND deleted_at IS NULL;
+
+-- every existing user gets default account in user currency and account in every other currency
+-- of user operations, so balance of account is in single currency
+INSERT INTO accounts (id, user_id, name, currency, is_default, created_at)
+SELECT gen_random_uuid(), id, 'Cash', currency, true, CURRENT_TIMESTAMP FROM users;
+
+INSERT INTO accounts (id, user_id, name, currency, is_default, created_at)
+SELECT gen_random_uuid(), c.user_id, 'Cash_' || c.currency, c.currency, false, CURRENT_TIMESTAMP
+FROM (
+    SELECT DISTINCT o.user_id, o.currency
+    FROM operations o
+    JOIN users u ON u.id = o.user_id
+    WHERE o.currency <> u.currency
+) c;
+
+ALTER TABLE operations ADD COLUMN account_id uuid REFERENCES accounts (id);
+
+UPDATE operations o SET account_id = a.id
+FROM accounts a
+WHERE a.user_id = o.user_id AND a.currency = o.currency;
+
+ALTER TABLE operations ALTER COLUMN account_id SET NOT NULL;
+
+CREATE INDEX idx_operations_account_id ON operations (account_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE operations DROP COLUMN IF EXISTS account_id;
+DROP TABLE IF EXISTS accounts;
+-- +goose StatementEnd
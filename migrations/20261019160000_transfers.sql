@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE operation_type AS ENUM ('REGULAR', 'TRANSFER');
+
+-- transfer takes money from account_id and puts to_money to to_account_id,
+-- to_money differs from money only for transfers between accounts in different currencies
+ALTER TABLE operations
+    ADD COLUMN type operation_type NOT NULL DEFAULT 'REGULAR',
+    ADD COLUMN to_account_id uuid REFERENCES accounts (id),
+    ADD COLUMN to_currency char(3),
+    ADD COLUMN to_money int;
+
+ALTER TABLE operations ADD CONSTRAINT chk_operations_transfer CHECK (
+    (type = 'TRANSFER') = (to_account_id IS NOT NULL AND to_currency IS NOT NULL AND to_money IS NOT NULL)
+);
+
+CREATE INDEX idx_operations_to_account_id ON operations (to_account_id)
+WHERE to_account_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE operations
+    DROP CONSTRAINT IF EXISTS chk_operations_transfer,
+    DROP COLUMN IF EXISTS type,
+    DROP COLUMN IF EXISTS to_account_id,
+    DROP COLUMN IF EXISTS to_currency,
+    DROP COLUMN IF EXISTS to_money;
+DROP TYPE IF EXISTS operation_type;
+-- +goose StatementEnd
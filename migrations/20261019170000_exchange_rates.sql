@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- 1 base = rate quote on date
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    date date NOT NULL,
+    base char(3) NOT NULL,
+    quote char(3) NOT NULL,
+    rate numeric(24, 10) NOT NULL CHECK (rate > 0),
+    source varchar(32) NOT NULL,
+    created_at timestamptz NOT NULL,
+    PRIMARY KEY (date, base, quote)
+);
+
+CREATE INDEX idx_exchange_rates_pair ON exchange_rates (base, quote, date DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS exchange_rates;
+-- +goose StatementEnd
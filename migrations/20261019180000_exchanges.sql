@@ -0,0 +1,32 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- new enum value cannot be used in the transaction which adds it
+ALTER TYPE operation_type ADD VALUE IF NOT EXISTS 'EXCHANGE';
+
+-- +goose StatementBegin
+-- rate is effective price of 1 to_currency unit in currency,
+-- reference_rate is central bank rate on operation date if it was known
+ALTER TABLE operations
+    ADD COLUMN rate numeric(24, 10),
+    ADD COLUMN reference_rate numeric(24, 10),
+    DROP CONSTRAINT chk_operations_transfer,
+    ADD CONSTRAINT chk_operations_transfer CHECK (
+        (type IN ('TRANSFER', 'EXCHANGE')) = (to_account_id IS NOT NULL AND to_currency IS NOT NULL AND to_money IS NOT NULL)
+    ),
+    ADD CONSTRAINT chk_operations_exchange CHECK ((type = 'EXCHANGE') = (rate IS NOT NULL));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- enum value cannot be dropped, exchanges are removed instead
+DELETE FROM operations WHERE type = 'EXCHANGE';
+
+ALTER TABLE operations
+    DROP CONSTRAINT chk_operations_exchange,
+    DROP CONSTRAINT chk_operations_transfer,
+    DROP COLUMN rate,
+    DROP COLUMN reference_rate,
+    ADD CONSTRAINT chk_operations_transfer CHECK (
+        (type = 'TRANSFER') = (to_account_id IS NOT NULL AND to_currency IS NOT NULL AND to_money IS NOT NULL)
+    );
+-- +goose StatementEnd
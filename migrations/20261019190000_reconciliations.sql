@@ -0,0 +1,28 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+ALTER TYPE operation_type ADD VALUE IF NOT EXISTS 'ADJUSTMENT';
+
+-- +goose StatementBegin
+-- reconciliation is a point where user confirmed real account balance,
+-- recorded is balance calculated from operations at that moment
+CREATE TABLE IF NOT EXISTS reconciliations (
+    id uuid PRIMARY KEY NOT NULL,
+    user_id bigint NOT NULL REFERENCES users (id),
+    account_id uuid NOT NULL REFERENCES accounts (id),
+    currency char(3) NOT NULL,
+    recorded int NOT NULL,
+    actual int NOT NULL,
+    adjustment_id uuid REFERENCES operations (id),
+    created_at timestamptz NOT NULL
+);
+
+CREATE INDEX idx_reconciliations_account ON reconciliations (account_id, created_at DESC);
+CREATE INDEX idx_reconciliations_user ON reconciliations (user_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS reconciliations;
+-- enum value cannot be dropped, adjustments are removed instead
+DELETE FROM operations WHERE type = 'ADJUSTMENT';
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- snapshot is net worth at the end of month in user default currency,
+-- it is stale if anything it depends on was changed after computed_at
+CREATE TABLE IF NOT EXISTS networth_snapshots (
+    user_id bigint NOT NULL REFERENCES users (id),
+    month date NOT NULL,
+    currency char(3) NOT NULL,
+    money int NOT NULL,
+    approximate boolean NOT NULL,
+    computed_at timestamptz NOT NULL,
+    PRIMARY KEY (user_id, month)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS networth_snapshots;
+-- +goose StatementEnd
@@ -0,0 +1,125 @@
+-- +goose Up
+-- +goose StatementBegin
+-- money was stored in cents of every currency, now it is stored in minor units
+-- with exponent of ISO 4217 currency, e.g. JPY has no minor units and KWD has 3 digits,
+-- lists of currencies must match money.Exponent, it is checked by TestExponentMigration
+CREATE OR REPLACE FUNCTION pg_temp.minor_units(cents bigint, currency char(3), up boolean) RETURNS bigint AS $$
+DECLARE
+    shift int := CASE
+        WHEN currency IN ('BIF', 'CLP', 'DJF', 'GNF', 'ISK', 'JPY', 'KMF', 'KRW', 'PYG',
+                          'RWF', 'UGX', 'UYI', 'VND', 'VUV', 'XAF', 'XAG', 'XAU', 'XBA',
+                          'XBB', 'XBC', 'XBD', 'XDR', 'XOF', 'XPD', 'XPF', 'XPT', 'XSU',
+                          'XTS', 'XUA', 'XXX') THEN -2
+        WHEN currency IN ('BHD', 'IQD', 'JOD', 'KWD', 'LYD', 'OMR', 'TND') THEN 1
+        WHEN currency IN ('CLF', 'UYW') THEN 2
+        ELSE 0
+    END;
+BEGIN
+    IF NOT up THEN
+        shift := -shift;
+    END IF;
+
+    IF shift >= 0 THEN
+        RETURN cents * 10::numeric ^ shift;
+    END IF;
+
+    RETURN round(cents / 10::numeric ^ (-shift));
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+ALTER TABLE operations
+    ALTER COLUMN money TYPE bigint USING pg_temp.minor_units(money, currency, true),
+    ALTER COLUMN to_money TYPE bigint USING pg_temp.minor_units(to_money, to_currency, true);
+
+ALTER TABLE accounts
+    ALTER COLUMN opening_balance TYPE bigint USING pg_temp.minor_units(opening_balance, currency, true);
+
+ALTER TABLE bills
+    ALTER COLUMN money TYPE bigint USING pg_temp.minor_units(money, currency, true);
+
+ALTER TABLE subscriptions
+    ALTER COLUMN money TYPE bigint USING pg_temp.minor_units(money, currency, true);
+
+ALTER TABLE debts
+    ALTER COLUMN money TYPE bigint USING pg_temp.minor_units(money, currency, true);
+
+ALTER TABLE split_shares ALTER COLUMN money TYPE bigint;
+
+UPDATE split_shares ss SET money = pg_temp.minor_units(ss.money, s.currency, true)
+FROM splits s WHERE s.id = ss.split_id;
+
+ALTER TABLE splits
+    ALTER COLUMN money TYPE bigint USING pg_temp.minor_units(money, currency, true);
+
+ALTER TABLE reconciliations
+    ALTER COLUMN recorded TYPE bigint USING pg_temp.minor_units(recorded, currency, true),
+    ALTER COLUMN actual TYPE bigint USING pg_temp.minor_units(actual, currency, true);
+
+-- snapshots are computed again on next request
+DELETE FROM networth_snapshots;
+
+ALTER TABLE networth_snapshots ALTER COLUMN money TYPE bigint;
+
+DROP FUNCTION pg_temp.minor_units;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION pg_temp.minor_units(cents bigint, currency char(3), up boolean) RETURNS bigint AS $$
+DECLARE
+    shift int := CASE
+        WHEN currency IN ('BIF', 'CLP', 'DJF', 'GNF', 'ISK', 'JPY', 'KMF', 'KRW', 'PYG',
+                          'RWF', 'UGX', 'UYI', 'VND', 'VUV', 'XAF', 'XAG', 'XAU', 'XBA',
+                          'XBB', 'XBC', 'XBD', 'XDR', 'XOF', 'XPD', 'XPF', 'XPT', 'XSU',
+                          'XTS', 'XUA', 'XXX') THEN -2
+        WHEN currency IN ('BHD', 'IQD', 'JOD', 'KWD', 'LYD', 'OMR', 'TND') THEN 1
+        WHEN currency IN ('CLF', 'UYW') THEN 2
+        ELSE 0
+    END;
+BEGIN
+    IF NOT up THEN
+        shift := -shift;
+    END IF;
+
+    IF shift >= 0 THEN
+        RETURN cents * 10::numeric ^ shift;
+    END IF;
+
+    RETURN round(cents / 10::numeric ^ (-shift));
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+DELETE FROM networth_snapshots;
+
+ALTER TABLE networth_snapshots ALTER COLUMN money TYPE int;
+
+ALTER TABLE reconciliations
+    ALTER COLUMN recorded TYPE int USING pg_temp.minor_units(recorded, currency, false),
+    ALTER COLUMN actual TYPE int USING pg_temp.minor_units(actual, currency, false);
+
+ALTER TABLE splits
+    ALTER COLUMN money TYPE int USING pg_temp.minor_units(money, currency, false);
+
+UPDATE split_shares ss SET money = pg_temp.minor_units(ss.money, s.currency, false)
+FROM splits s WHERE s.id = ss.split_id;
+
+ALTER TABLE split_shares ALTER COLUMN money TYPE int;
+
+ALTER TABLE debts
+    ALTER COLUMN money TYPE int USING pg_temp.minor_units(money, currency, false);
+
+ALTER TABLE subscriptions
+    ALTER COLUMN money TYPE int USING pg_temp.minor_units(money, currency, false);
+
+ALTER TABLE bills
+    ALTER COLUMN money TYPE int USING pg_temp.minor_units(money, currency, false);
+
+ALTER TABLE accounts
+    ALTER COLUMN opening_balance TYPE int USING pg_temp.minor_units(opening_balance, currency, false);
+
+ALTER TABLE operations
+    ALTER COLUMN money TYPE int USING pg_temp.minor_units(money, currency, false),
+    ALTER COLUMN to_money TYPE int USING pg_temp.minor_units(to_money, to_currency, false);
+
+DROP FUNCTION pg_temp.minor_units;
+-- +goose StatementEnd
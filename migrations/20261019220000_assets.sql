@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+-- rates of assets are stored with ticker as base
+ALTER TABLE exchange_rates
+    ALTER COLUMN base TYPE varchar(10),
+    ALTER COLUMN quote TYPE varchar(10);
+
+-- asset is user defined holding which is not a currency, e.g. BTC or ETH
+CREATE TABLE IF NOT EXISTS assets (
+    id uuid PRIMARY KEY NOT NULL,
+    user_id bigint NOT NULL REFERENCES users (id),
+    ticker varchar(10) NOT NULL,
+    decimals smallint NOT NULL CHECK (decimals BETWEEN 0 AND 18),
+    created_at timestamptz NOT NULL,
+    UNIQUE (user_id, ticker)
+);
+
+-- amount is positive when asset is bought and negative when sold
+CREATE TABLE IF NOT EXISTS asset_operations (
+    id uuid PRIMARY KEY NOT NULL,
+    user_id bigint NOT NULL REFERENCES users (id),
+    asset_id uuid NOT NULL REFERENCES assets (id),
+    amount numeric(38, 18) NOT NULL CHECK (amount <> 0),
+    note varchar(64) NOT NULL DEFAULT '',
+    occured_at date NOT NULL,
+    created_at timestamptz NOT NULL
+);
+
+CREATE INDEX idx_asset_operations_user_id ON asset_operations (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS asset_operations;
+DROP TABLE IF EXISTS assets;
+
+DELETE FROM exchange_rates WHERE length(base) <> 3 OR length(quote) <> 3;
+
+ALTER TABLE exchange_rates
+    ALTER COLUMN base TYPE char(3),
+    ALTER COLUMN quote TYPE char(3);
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- external_id is id of imported operation in bank statement, e.g. FITID of OFX transaction,
+-- the same operation is never imported twice
+ALTER TABLE operations ADD COLUMN external_id text;
+
+CREATE UNIQUE INDEX idx_operations_external_id ON operations (user_id, external_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_operations_external_id;
+ALTER TABLE operations DROP COLUMN IF EXISTS external_id;
+-- +goose StatementEnd
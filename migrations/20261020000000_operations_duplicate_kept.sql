@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- duplicate_kept is true if user confirmed that operation is not a duplicate of a similar one
+ALTER TABLE operations ADD COLUMN duplicate_kept boolean NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE operations DROP COLUMN IF EXISTS duplicate_kept;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- 1 ticker = price quote on date set by user, tickers are defined by users,
+-- so their prices are not shared through exchange_rates
+CREATE TABLE IF NOT EXISTS asset_prices (
+    user_id bigint NOT NULL REFERENCES users (id),
+    ticker varchar(10) NOT NULL,
+    quote varchar(10) NOT NULL,
+    date date NOT NULL,
+    price numeric(24, 10) NOT NULL CHECK (price > 0),
+    created_at timestamptz NOT NULL,
+    PRIMARY KEY (user_id, ticker, quote, date)
+);
+
+-- author of manual rate is unknown, so it is kept for every user holding the asset
+-- and valuations do not change
+INSERT INTO asset_prices (user_id, ticker, quote, date, price, created_at)
+SELECT a.user_id, r.base, r.quote, r.date, r.rate, r.created_at
+FROM exchange_rates r
+JOIN assets a ON a.ticker = r.base
+WHERE r.source = 'manual';
+
+DELETE FROM exchange_rates WHERE source = 'manual';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+INSERT INTO exchange_rates (date, base, quote, rate, source, created_at)
+SELECT DISTINCT ON (date, ticker, quote) date, ticker, quote, price, 'manual', created_at
+FROM asset_prices
+ORDER BY date, ticker, quote, created_at DESC
+ON CONFLICT DO NOTHING;
+
+DROP TABLE IF EXISTS asset_prices;
+-- +goose StatementEnd